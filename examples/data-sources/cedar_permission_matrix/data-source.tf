@@ -0,0 +1,39 @@
+data "cedar_policyset" "example" {
+  policy {
+    effect = "permit"
+    principal_in = {
+      type = "Group"
+      id   = "eng"
+    }
+    action = {
+      type = "Action"
+      id   = "Read"
+    }
+    any_resource = true
+  }
+}
+
+data "cedar_permission_matrix" "example" {
+  policy_set = data.cedar_policyset.example.text
+
+  entities = jsonencode([
+    { uid = { type = "User", id = "alice" }, parents = [{ type = "Group", id = "eng" }] },
+    { uid = { type = "User", id = "bob" }, parents = [] },
+    { uid = { type = "Group", id = "eng" }, parents = [] },
+    { uid = { type = "Document", id = "plan" }, parents = [] },
+    { uid = { type = "Action", id = "Read" }, parents = [] },
+  ])
+
+  principal_types = ["User"]
+  resource_types  = ["Document"]
+}
+
+output "permissions" {
+  // renders the following table:
+  //
+  // | principal | action | resource | decision | policies |
+  // | --- | --- | --- | --- | --- |
+  // | `User::"alice"` | `Action::"Read"` | `Document::"plan"` | allow | policy0 |
+  // | `User::"bob"` | `Action::"Read"` | `Document::"plan"` | deny |  |
+  value = data.cedar_permission_matrix.example.markdown
+}
@@ -57,3 +57,16 @@ output "policy_text" {
   // );
   value = data.cedar_policyset.example.text
 }
+
+# With entity UID strings, which are given in an object with a 'uid' attribute
+data "cedar_policyset" "with_uids" {
+  policy {
+    principal = {
+      uid = "CF::User::\"alice\""
+    }
+    action = {
+      uid = "CF::Action::\"Read\""
+    }
+    any_resource = true
+  }
+}
@@ -0,0 +1,32 @@
+// conditions shared by many policies can be defined once as macros,
+// here or on the provider, and referred to by name.
+data "cedar_policyset" "admin" {
+  condition_macro {
+    name = "mfa_required"
+    text = "context.mfa == true && context.device.managed"
+  }
+
+  condition_macro {
+    name   = "member_of"
+    text   = "principal in $group"
+    params = ["group"]
+  }
+
+  policy {
+    effect        = "permit"
+    any_principal = true
+    action        = { type = "Action", id = "Delete" }
+    any_resource  = true
+
+    when {
+      macro = "mfa_required"
+    }
+
+    when {
+      macro = "member_of"
+      params = {
+        group = { entity = { type = "Group", id = "admins" } }
+      }
+    }
+  }
+}
@@ -0,0 +1,18 @@
+// values are bound to '$name' placeholders as typed Cedar literals,
+// rather than interpolated into the condition text.
+data "cedar_policyset" "owner" {
+  policy {
+    effect        = "permit"
+    any_principal = true
+    action        = { type = "Action", id = "Edit" }
+    any_resource  = true
+
+    when {
+      text = "resource.owner == $owner && context.ip.isInRange($office)"
+      params = {
+        owner  = { entity = { type = "User", id = var.user } }
+        office = { ip = "10.0.0.0/8" }
+      }
+    }
+  }
+}
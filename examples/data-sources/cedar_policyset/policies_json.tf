@@ -0,0 +1,22 @@
+// grants.yaml:
+//
+// admins:
+//   - Read
+//   - Write
+// readers:
+//   - Read
+locals {
+  grants = yamldecode(file("${path.module}/grants.yaml"))
+}
+
+data "cedar_policyset" "grants" {
+  policies_json = jsonencode([
+    for group, actions in local.grants : {
+      effect       = "permit"
+      annotation   = [{ name = "id", value = group }]
+      principal_in = { type = "CF::Group", id = group }
+      action_in    = [for action in actions : { uid = "CF::Action::\"${action}\"" }]
+      any_resource = true
+    }
+  ])
+}
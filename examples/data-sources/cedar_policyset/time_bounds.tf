@@ -0,0 +1,13 @@
+// a just-in-time access grant, which only applies during an incident.
+// Requests must include the current time in 'context.now', for example
+// {"now": {"__extn": {"fn": "datetime", "arg": "2024-10-15T10:00:00Z"}}}.
+data "cedar_policyset" "break_glass" {
+  policy {
+    effect       = "permit"
+    principal    = { type = "User", id = "alice" }
+    action       = { type = "Action", id = "Deploy" }
+    any_resource = true
+    not_before   = "2024-10-15T09:00:00Z"
+    not_after    = "2024-10-15T17:00:00Z"
+  }
+}
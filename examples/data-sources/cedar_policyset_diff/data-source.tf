@@ -0,0 +1,15 @@
+data "cedar_policyset_diff" "example" {
+  old = file("${path.module}/policies/current.cedar")
+  new = data.cedar_policyset.example.text
+}
+
+output "policy_changes" {
+  // renders a summary such as:
+  //
+  // 1 added, 0 removed, 1 modified.
+  //
+  // + policy2
+  // ~ admins
+  //     unless[0]: + context.locked
+  value = data.cedar_policyset_diff.example.summary
+}
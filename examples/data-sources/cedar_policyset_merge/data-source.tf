@@ -0,0 +1,14 @@
+data "cedar_policyset_merge" "example" {
+  prefix_ids = true
+
+  source = [
+    {
+      name = "platform"
+      text = file("${path.module}/policies/platform.cedar")
+    },
+    {
+      name = "product"
+      json = data.cedar_policyset.example.json
+    },
+  ]
+}
@@ -0,0 +1,27 @@
+data "cedar_rbac" "example" {
+  namespace = "CF"
+
+  permission_set = [
+    { name = "viewer", actions = ["Read", "List"] },
+    { name = "editor", actions = ["Read", "List", "Write"] },
+  ]
+
+  role = [
+    {
+      name            = "engineering"
+      groups          = ["engineers", "contractors"]
+      permission_sets = ["viewer"]
+    },
+    {
+      name            = "docs-editors"
+      groups          = ["writers"]
+      permission_sets = ["editor"]
+      resource_in     = { type = "Folder", id = "docs" }
+    },
+  ]
+
+  // the owner of a document can do anything with it.
+  owner = [
+    { name = "document-owner", attribute = "owner", resource_is = "Document" },
+  ]
+}
@@ -0,0 +1,4 @@
+output "expires" {
+  // renders a literal such as 'datetime("2024-10-15T09:00:00Z")'
+  value = provider::cedar::datetime(timeadd(timestamp(), "24h"))
+}
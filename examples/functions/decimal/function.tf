@@ -0,0 +1,4 @@
+output "max_risk" {
+  // renders 'decimal("2.0")'
+  value = provider::cedar::decimal(2)
+}
@@ -0,0 +1,4 @@
+output "session_length" {
+  // renders 'duration("1h30m")'
+  value = provider::cedar::duration("90m")
+}
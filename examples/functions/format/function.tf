@@ -0,0 +1,13 @@
+output "formatted" {
+  // renders the following policy:
+  //
+  // permit (
+  //  principal in Group::"admins",
+  //  action,
+  //  resource
+  // )
+  // when {
+  //  resource.is_public
+  // };
+  value = provider::cedar::format(file("${path.module}/policy.cedar"))
+}
@@ -0,0 +1,13 @@
+data "cedar_policyset" "office" {
+  policy {
+    effect        = "permit"
+    any_principal = true
+    any_action    = true
+    any_resource  = true
+
+    when {
+      // renders 'context.ip.isInRange(ip("10.0.0.0/8"))'
+      text = "context.ip.isInRange(${provider::cedar::ip(var.office_cidr)})"
+    }
+  }
+}
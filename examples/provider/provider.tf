@@ -1,3 +1,36 @@
 provider "cedar" {
-  # example configuration here
+  # prepended to unqualified entity types, so 'User' is rendered as 'CF::User'.
+  default_namespace = "CF"
+
+  # policies are validated against the schema.
+  schema = "${path.module}/schema.cedarschema.json"
+
+  # report redundant and overridden policies as errors.
+  strict = true
+
+  format = {
+    indent     = "  "
+    line_width = 100
+  }
+
+  # 'not_before' and 'not_after' are compared with this attribute.
+  time_attribute = "context.now"
+
+  # leave policies out of policy sets once their 'not_after' time has passed.
+  drop_expired = true
+
+  # reject syntax which the Cedar runtime in production doesn't support.
+  cedar_version = "3.4"
+
+  # conditions which policies refer to with 'macro = "mfa_required"'.
+  condition_macro {
+    name = "mfa_required"
+    text = "context.mfa == true && context.device.managed"
+  }
+
+  condition_macro {
+    name   = "member_of"
+    text   = "principal in $group"
+    params = ["group"]
+  }
 }
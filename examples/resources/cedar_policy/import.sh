@@ -0,0 +1,5 @@
+# Policies are imported using the policy store ID and the policy ID, separated by a comma.
+terraform import cedar_policy.static ./policy-store,admins-read
+
+# Policies can also be imported using the policy store ID and the Cedar text of the policy, separated by '//'.
+terraform import cedar_policy.static './policy-store//permit (principal in CF::Group::"admins", action == CF::Action::"Read", resource);'
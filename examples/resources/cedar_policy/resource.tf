@@ -0,0 +1,33 @@
+resource "cedar_policy" "static" {
+  policy_store_id = cedar_policy_store.example.id
+  policy_id       = "admins-read"
+  description     = "Admins can read all documents"
+  statement       = <<-EOT
+    permit (
+      principal in CF::Group::"admins",
+      action == CF::Action::"Read",
+      resource
+    );
+  EOT
+}
+
+resource "cedar_policy" "linked" {
+  policy_store_id = cedar_policy_store.example.id
+  template_linked = {
+    template_id = cedar_policy_template.owner.template_id
+    principal   = { uid = "CF::User::\"alice\"" }
+    resource    = { type = "CF::Document", id = "plan" }
+  }
+}
+
+resource "cedar_policy" "structured" {
+  policy_store_id = cedar_policy_store.example.id
+  policy_id       = "auditors-read"
+  policy = {
+    effect       = "permit"
+    principal_in = { uid = "CF::Group::\"auditors\"" }
+    action       = { type = "CF::Action", id = "Read" }
+    any_resource = true
+    when         = [{ text = "context.mfa" }]
+  }
+}
@@ -0,0 +1,26 @@
+data "cedar_policyset" "example" {
+  policy {
+    annotation {
+      name  = "id"
+      value = "admins-read"
+    }
+
+    effect = "permit"
+    principal_in = {
+      type = "Group"
+      id   = "admins"
+    }
+    action = {
+      type = "Action"
+      id   = "Read"
+    }
+    any_resource = true
+  }
+}
+
+# writes policies/admins-read.cedar and policies/entities.json
+resource "cedar_policy_files" "example" {
+  directory  = "${path.module}/policies"
+  policy_set = data.cedar_policyset.example.text
+  entities   = file("${path.module}/entities.json")
+}
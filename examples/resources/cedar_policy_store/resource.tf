@@ -0,0 +1,5 @@
+resource "cedar_policy_store" "example" {
+  # use a path ending in '.json' to keep the store in a single file.
+  path   = "${path.module}/policy-store"
+  schema = file("${path.module}/schema.cedarschema.json")
+}
@@ -0,0 +1,2 @@
+# Templates are imported using the policy store ID and the template ID, separated by a comma.
+terraform import cedar_policy_template.owner ./policy-store,owner
@@ -0,0 +1,5 @@
+resource "cedar_policy_template" "owner" {
+  policy_store_id = cedar_policy_store.example.id
+  template_id     = "owner"
+  statement       = "permit (principal == ?principal, action == CF::Action::\"Read\", resource == ?resource);"
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+)
+
+// The request and response types of the 'serve' command, which match
+// the Amazon Verified Permissions IsAuthorized and BatchIsAuthorized APIs.
+
+type avpEntityIdentifier struct {
+	EntityType string `json:"entityType"`
+	EntityID   string `json:"entityId"`
+}
+
+type avpActionIdentifier struct {
+	ActionType string `json:"actionType"`
+	ActionID   string `json:"actionId"`
+}
+
+// avpAttributeValue is a value in the request context or an entity attribute.
+// Exactly one field must be set.
+type avpAttributeValue struct {
+	Boolean          *bool                         `json:"boolean,omitempty"`
+	EntityIdentifier *avpEntityIdentifier          `json:"entityIdentifier,omitempty"`
+	Long             *int64                        `json:"long,omitempty"`
+	String           *string                       `json:"string,omitempty"`
+	Set              *[]avpAttributeValue          `json:"set,omitempty"`
+	Record           *map[string]avpAttributeValue `json:"record,omitempty"`
+	IPAddr           *string                       `json:"ipaddr,omitempty"`
+	Decimal          *string                       `json:"decimal,omitempty"`
+}
+
+// avpContext is the request context, given either as a map of
+// attribute values or as a Cedar JSON object.
+type avpContext struct {
+	ContextMap map[string]avpAttributeValue `json:"contextMap,omitempty"`
+	CedarJSON  *string                      `json:"cedarJson,omitempty"`
+}
+
+type avpEntityItem struct {
+	Identifier avpEntityIdentifier          `json:"identifier"`
+	Attributes map[string]avpAttributeValue `json:"attributes,omitempty"`
+	Parents    []avpEntityIdentifier        `json:"parents,omitempty"`
+}
+
+type avpEntities struct {
+	EntityList []avpEntityItem `json:"entityList,omitempty"`
+}
+
+type avpRequest struct {
+	Principal *avpEntityIdentifier `json:"principal,omitempty"`
+	Action    *avpActionIdentifier `json:"action,omitempty"`
+	Resource  *avpEntityIdentifier `json:"resource,omitempty"`
+	Context   *avpContext          `json:"context,omitempty"`
+}
+
+type avpIsAuthorizedInput struct {
+	PolicyStoreID string `json:"policyStoreId,omitempty"`
+	avpRequest
+	Entities *avpEntities `json:"entities,omitempty"`
+}
+
+type avpBatchIsAuthorizedInput struct {
+	PolicyStoreID string       `json:"policyStoreId,omitempty"`
+	Entities      *avpEntities `json:"entities,omitempty"`
+	Requests      []avpRequest `json:"requests"`
+}
+
+type avpDeterminingPolicy struct {
+	PolicyID string `json:"policyId"`
+}
+
+type avpEvaluationError struct {
+	ErrorDescription string `json:"errorDescription"`
+}
+
+type avpIsAuthorizedOutput struct {
+	Decision            string                 `json:"decision"`
+	DeterminingPolicies []avpDeterminingPolicy `json:"determiningPolicies"`
+	Errors              []avpEvaluationError   `json:"errors"`
+}
+
+type avpBatchIsAuthorizedOutputItem struct {
+	Request avpRequest `json:"request"`
+	avpIsAuthorizedOutput
+}
+
+type avpBatchIsAuthorizedOutput struct {
+	Results []avpBatchIsAuthorizedOutputItem `json:"results"`
+}
+
+func (e avpEntityIdentifier) uid() cedarpolicy.EntityUID {
+	return cedarpolicy.EntityUID{Type: e.EntityType, ID: e.EntityID}
+}
+
+// cedarRequest converts the request to a Cedar authorization request.
+func (r avpRequest) cedarRequest() (cedarpolicy.Request, error) {
+	switch {
+	case r.Principal == nil:
+		return cedarpolicy.Request{}, fmt.Errorf("'principal' must be specified")
+	case r.Action == nil:
+		return cedarpolicy.Request{}, fmt.Errorf("'action' must be specified")
+	case r.Resource == nil:
+		return cedarpolicy.Request{}, fmt.Errorf("'resource' must be specified")
+	}
+
+	req := cedarpolicy.Request{
+		Principal: r.Principal.uid(),
+		Action:    cedarpolicy.EntityUID{Type: r.Action.ActionType, ID: r.Action.ActionID},
+		Resource:  r.Resource.uid(),
+		Context:   cedarpolicy.Record{},
+	}
+
+	if r.Context == nil {
+		return req, nil
+	}
+
+	if r.Context.CedarJSON != nil {
+		if r.Context.ContextMap != nil {
+			return cedarpolicy.Request{}, fmt.Errorf("context: only one of 'contextMap' or 'cedarJson' may be specified")
+		}
+		v, err := cedarpolicy.ParseValueJSON([]byte(*r.Context.CedarJSON))
+		if err != nil {
+			return cedarpolicy.Request{}, fmt.Errorf("context: %w", err)
+		}
+		record, ok := v.(cedarpolicy.Record)
+		if !ok {
+			return cedarpolicy.Request{}, fmt.Errorf("context: 'cedarJson' must be a JSON object")
+		}
+		req.Context = record
+		return req, nil
+	}
+
+	record, err := avpRecord(r.Context.ContextMap)
+	if err != nil {
+		return cedarpolicy.Request{}, fmt.Errorf("context: %w", err)
+	}
+	req.Context = record
+	return req, nil
+}
+
+// cedarEntities converts the entities in a request to Cedar entities,
+// which replace any entities with the same UID in base.
+func (e *avpEntities) cedarEntities(base cedarpolicy.Entities) (cedarpolicy.Entities, error) {
+	if e == nil || len(e.EntityList) == 0 {
+		return base, nil
+	}
+
+	entities := make(cedarpolicy.Entities, len(base)+len(e.EntityList))
+	for uid, entity := range base {
+		entities[uid] = entity
+	}
+
+	for _, item := range e.EntityList {
+		uid := item.Identifier.uid()
+		attrs, err := avpRecord(item.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("entity %s: %w", uid, err)
+		}
+		entity := &cedarpolicy.Entity{UID: uid, Attributes: attrs}
+		for _, parent := range item.Parents {
+			entity.Parents = append(entity.Parents, parent.uid())
+		}
+		entities[uid] = entity
+	}
+
+	return entities, nil
+}
+
+func avpRecord(attrs map[string]avpAttributeValue) (cedarpolicy.Record, error) {
+	record := cedarpolicy.Record{}
+	for name, attr := range attrs {
+		v, err := attr.value()
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		record[name] = v
+	}
+	return record, nil
+}
+
+// value converts the attribute value to a Cedar value.
+func (a avpAttributeValue) value() (cedarpolicy.Value, error) {
+	var values []cedarpolicy.Value
+	var err error
+
+	if a.Boolean != nil {
+		values = append(values, cedarpolicy.Bool(*a.Boolean))
+	}
+	if a.EntityIdentifier != nil {
+		values = append(values, a.EntityIdentifier.uid())
+	}
+	if a.Long != nil {
+		values = append(values, cedarpolicy.Long(*a.Long))
+	}
+	if a.String != nil {
+		values = append(values, cedarpolicy.String(*a.String))
+	}
+	if a.Set != nil {
+		set := make(cedarpolicy.Set, len(*a.Set))
+		for i, elem := range *a.Set {
+			if set[i], err = elem.value(); err != nil {
+				return nil, fmt.Errorf("set element %d: %w", i, err)
+			}
+		}
+		values = append(values, set)
+	}
+	if a.Record != nil {
+		record, err := avpRecord(*a.Record)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, record)
+	}
+	if a.IPAddr != nil {
+		ip, err := cedarpolicy.ParseIPAddr(*a.IPAddr)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, ip)
+	}
+	if a.Decimal != nil {
+		d, err := cedarpolicy.ParseDecimal(*a.Decimal)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, d)
+	}
+
+	if len(values) != 1 {
+		data, _ := json.Marshal(a)
+		return nil, fmt.Errorf("exactly one of 'boolean', 'entityIdentifier', 'long', 'string', 'set', 'record', 'ipaddr' or 'decimal' must be specified, got %s", data)
+	}
+	return values[0], nil
+}
+
+// avpOutput converts a Cedar authorization response to the IsAuthorized output.
+func avpOutput(resp cedarpolicy.Response) avpIsAuthorizedOutput {
+	out := avpIsAuthorizedOutput{
+		Decision:            "DENY",
+		DeterminingPolicies: []avpDeterminingPolicy{},
+		Errors:              []avpEvaluationError{},
+	}
+	if resp.Decision == cedarpolicy.Allow {
+		out.Decision = "ALLOW"
+	}
+	for _, id := range resp.Reasons {
+		out.DeterminingPolicies = append(out.DeterminingPolicies, avpDeterminingPolicy{PolicyID: id})
+	}
+	for _, e := range resp.Errors {
+		out.Errors = append(out.Errors, avpEvaluationError{ErrorDescription: fmt.Sprintf("policy %q: %s", e.PolicyID, e.Message)})
+	}
+	return out
+}
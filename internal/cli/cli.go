@@ -0,0 +1,435 @@
+// Package cli implements the command line interface of the provider binary,
+// which renders, validates, formats and evaluates Cedar policies using the same
+// logic as the provider, without a Terraform configuration.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+)
+
+const usage = `Usage: terraform-provider-cedar <command> [flags] [args]
+
+Commands:
+  render     Render policies from a JSON policy document as Cedar text
+  validate   Check that Cedar policy files parse, and optionally validate them against a schema
+  fmt        Rewrite Cedar policy files in canonical form (files with comments are only checked)
+  authorize  Evaluate an authorization request, exiting with a non-zero status if it is denied
+  serve      Serve IsAuthorized and BatchIsAuthorized endpoints, reloading the files when they change
+
+Run 'terraform-provider-cedar <command> -h' for the flags of a command.
+Without a command, the Terraform provider server is started.
+`
+
+// errFailed is returned by a command which has already reported
+// its failure, such as 'fmt -check' finding unformatted files.
+var errFailed = errors.New("failed")
+
+type command struct {
+	name string
+	run  func(args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{"render", runRender},
+	{"validate", runValidate},
+	{"fmt", runFmt},
+	{"authorize", runAuthorize},
+	{"serve", runServe},
+}
+
+// IsCommand returns true if name is a CLI command, rather than a flag for the provider server.
+func IsCommand(name string) bool {
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		return true
+	}
+	for _, c := range commands {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs the command given by args[0] and returns the process exit code.
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		err := c.run(args[1:], stdout, stderr)
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errFailed):
+			return 1
+		default:
+			fmt.Fprintf(stderr, "%s: %s\n", c.name, err)
+			return 1
+		}
+	}
+
+	if IsCommand(args[0]) {
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+	fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+	return 2
+}
+
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: terraform-provider-cedar %s [flags] %s\n\nFlags:\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+func runRender(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("render", "<file.json | ->", stderr)
+	namespace := flags.String("namespace", "", "a namespace prepended to unqualified entity types in policy scopes")
+	order := flags.String("order", "", "the order of the rendered policies, one of: "+strings.Join(cedarpolicy.Orders, ", "))
+	format := flags.Bool("format", false, "render policies using the canonical formatter, which wraps long action lists and conditions")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected a single policy document")
+	}
+
+	data, err := readInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	policies, err := cedarpolicy.ParsePolicyDocument(data)
+	if err != nil {
+		return fmt.Errorf("unable to parse %s: %w", flags.Arg(0), err)
+	}
+
+	for i, policy := range policies {
+		if err := policy.CheckActionTypes(); err != nil {
+			return fmt.Errorf("policy %q: %w", policy.ID(i), err)
+		}
+		policies[i] = policy.WithNamespace(*namespace)
+	}
+
+	policies, err = cedarpolicy.SortPolicies(policies, *order)
+	if err != nil {
+		return err
+	}
+
+	var text string
+	if *format {
+		text, err = cedarpolicy.FormatPolicySet(policies, cedarpolicy.DefaultFormatOptions())
+	} else {
+		text, err = renderPolicySet(policies)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(stdout, text)
+	return err
+}
+
+// renderPolicySet renders the policies as text, in the same way as the
+// 'text' attribute of the 'cedar_policyset' data source.
+func renderPolicySet(policies []cedarpolicy.Policy) (string, error) {
+	rendered := make([]string, len(policies))
+	for i, policy := range policies {
+		text, err := policy.RenderString()
+		if err != nil {
+			return "", fmt.Errorf("policy %q: %w", policy.ID(i), err)
+		}
+		rendered[i] = text
+	}
+	return strings.Join(rendered, "\n\n") + "\n", nil
+}
+
+func runValidate(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("validate", "<path>...", stderr)
+	schemaPath := flags.String("schema", "", "the path to a Cedar schema in JSON format to validate the policies against")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("expected at least one file or directory")
+	}
+
+	var schema *cedarpolicy.Schema
+	if *schemaPath != "" {
+		data, err := os.ReadFile(*schemaPath)
+		if err != nil {
+			return err
+		}
+		schema, err = cedarpolicy.ParseSchema(data)
+		if err != nil {
+			return fmt.Errorf("unable to parse schema: %w", err)
+		}
+	}
+
+	files, err := policyFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	for _, file := range files {
+		policies, err := readPolicies(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", file, err)
+			failed = true
+			continue
+		}
+
+		for i, policy := range policies {
+			errs := []error{policy.CheckActionTypes()}
+			if schema != nil {
+				errs = append(errs, schema.ValidatePolicy(policy)...)
+			}
+			for _, err := range errs {
+				if err != nil {
+					fmt.Fprintf(stderr, "%s: policy %q: %s\n", file, policy.ID(i), err)
+					failed = true
+				}
+			}
+		}
+	}
+
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+func runFmt(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("fmt", "<path>...", stderr)
+	check := flags.Bool("check", false, "don't rewrite files, but list the files which are not formatted and exit with a non-zero status")
+	indent := flags.String("indent", "\t", "the string used for each level of indentation")
+	lineWidth := flags.Int("line-width", 80, "the maximum line width before action lists and '&&'/'||' chains are wrapped, or 0 to disable wrapping")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("expected at least one file or directory")
+	}
+
+	opts := cedarpolicy.FormatOptions{Indent: *indent, LineWidth: *lineWidth}
+
+	files, err := policyFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		formatted, err := cedarpolicy.Format(string(src), opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", file, err)
+			failed = true
+			continue
+		}
+		if formatted == string(src) {
+			continue
+		}
+
+		// the formatter doesn't preserve comments, so files with comments are
+		// never rewritten. They are formatted if they only differ in comments.
+		if stripped := cedarpolicy.StripComments(string(src)); stripped != string(src) {
+			if formatted == stripped {
+				continue
+			}
+			fmt.Fprintf(stderr, "%s: not formatted, and can't be rewritten as formatting would remove its comments\n", file)
+			failed = true
+			continue
+		}
+
+		fmt.Fprintln(stdout, file)
+		if *check {
+			failed = true
+			continue
+		}
+		if err := os.WriteFile(file, []byte(formatted), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+func runAuthorize(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("authorize", "<request.json>", stderr)
+	var policyPaths stringsFlag
+	flags.Var(&policyPaths, "policies", "a Cedar policy file or directory of '.cedar' files (may be repeated)")
+	entitiesPath := flags.String("entities", "", "the path to the entities in the Cedar JSON entity format")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected a single request file")
+	}
+	if len(policyPaths) == 0 {
+		return errors.New("at least one -policies path must be specified")
+	}
+
+	files, err := policyFiles(policyPaths)
+	if err != nil {
+		return err
+	}
+	var policies []cedarpolicy.Policy
+	for _, file := range files {
+		p, err := readPolicies(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		policies = append(policies, p...)
+	}
+
+	authorizer, err := cedarpolicy.NewAuthorizer(policies)
+	if err != nil {
+		return err
+	}
+
+	entities := cedarpolicy.Entities{}
+	if *entitiesPath != "" {
+		data, err := os.ReadFile(*entitiesPath)
+		if err != nil {
+			return err
+		}
+		entities, err = cedarpolicy.ParseEntities(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := readInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	req, err := cedarpolicy.ParseRequest(data)
+	if err != nil {
+		return err
+	}
+
+	resp := authorizer.IsAuthorized(entities, req)
+
+	out := authorizeOutput{Decision: string(resp.Decision), Reasons: resp.Reasons, Errors: []authorizeError{}}
+	if out.Reasons == nil {
+		out.Reasons = []string{}
+	}
+	for _, e := range resp.Errors {
+		out.Errors = append(out.Errors, authorizeError{PolicyID: e.PolicyID, Message: e.Message})
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+
+	if resp.Decision != cedarpolicy.Allow {
+		return errFailed
+	}
+	return nil
+}
+
+type authorizeOutput struct {
+	Decision string           `json:"decision"`
+	Reasons  []string         `json:"reasons"`
+	Errors   []authorizeError `json:"errors"`
+}
+
+type authorizeError struct {
+	PolicyID string `json:"policy_id"`
+	Message  string `json:"message"`
+}
+
+// stringsFlag is a flag which may be repeated.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string { return strings.Join(*s, ",") }
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// readInput reads the file, or standard input if the path is '-'.
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// policyFiles returns the files given as arguments, and the '.cedar'
+// files in any directories, searched recursively.
+func policyFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && filepath.Ext(p) == ".cedar" {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// readPolicies reads the policies in a file. Files with a '.json' extension
+// are read as policy documents, and other files as Cedar policy text.
+func readPolicies(path string) ([]cedarpolicy.Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) == ".json" {
+		return cedarpolicy.ParsePolicyDocument(data)
+	}
+	return cedarpolicy.ParsePolicySet(string(data))
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	doc := writeFile(t, dir, "policies.json", `[
+		{"effect": "permit", "annotation": [{"name": "id", "value": "admins-read"}], "principal_in": {"uid": "Group::\"admins\""}, "action": {"type": "Action", "id": "Read"}, "any_resource": true},
+		{"effect": "forbid", "annotation": [{"name": "id", "value": "no-secrets"}], "any_principal": true, "any_action": true, "resource_is": "Secret"}
+	]`)
+
+	code, stdout, stderr := run("render", "-namespace", "CF", "-order", "by_effect_then_id", doc)
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, `@id("no-secrets")
+forbid (
+	principal,
+	action,
+	resource is CF::Secret
+);
+
+@id("admins-read")
+permit (
+	principal in CF::Group::"admins",
+	action == CF::Action::"Read",
+	resource
+);
+`, stdout)
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	schema := writeFile(t, dir, "schema.json", `{"": {
+		"entityTypes": {"User": {}, "Document": {}},
+		"actions": {"Read": {"appliesTo": {"principalTypes": ["User"], "resourceTypes": ["Document"]}}}
+	}}`)
+	writeFile(t, dir, "ok.cedar", `permit (principal is User, action == Action::"Read", resource);`)
+
+	code, _, stderr := run("validate", "-schema", schema, dir)
+	assert.Equal(t, 0, code, stderr)
+
+	writeFile(t, dir, "bad.cedar", `@id("write") permit (principal, action == Action::"Write", resource);`)
+
+	code, _, stderr = run("validate", "-schema", schema, dir)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, filepath.Join(dir, "bad.cedar")+`: policy "write": action: unknown action Action::"Write"`+"\n", stderr)
+}
+
+func TestFmt(t *testing.T) {
+	dir := t.TempDir()
+	formatted := "permit (\n\tprincipal,\n\taction,\n\tresource\n);\n"
+	writeFile(t, dir, "ok.cedar", formatted)
+	messy := writeFile(t, dir, "messy.cedar", "permit(principal,action,resource);")
+
+	code, stdout, _ := run("fmt", "-check", dir)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, messy+"\n", stdout)
+
+	code, stdout, stderr := run("fmt", dir)
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, messy+"\n", stdout)
+
+	got, err := os.ReadFile(messy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, formatted, string(got))
+
+	code, stdout, _ = run("fmt", "-check", dir)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout)
+}
+
+func TestFmt_Comments(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "ok.cedar", "// everyone can read\npermit (\n\tprincipal,\n\taction, // any action\n\tresource\n);\n")
+	messySrc := "// everyone can read\npermit(principal,action,resource);"
+	messy := writeFile(t, dir, "messy.cedar", messySrc)
+
+	for _, args := range [][]string{{"fmt", "-check", dir}, {"fmt", dir}} {
+		code, stdout, stderr := run(args...)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "", stdout)
+		assert.Equal(t, messy+": not formatted, and can't be rewritten as formatting would remove its comments\n", stderr)
+	}
+
+	got, err := os.ReadFile(messy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, messySrc, string(got))
+}
+
+func TestAuthorize(t *testing.T) {
+	dir := t.TempDir()
+	policies := writeFile(t, dir, "policies.cedar", `
+@id("eng-read")
+permit (principal in Group::"eng", action == Action::"Read", resource)
+when { context.mfa };
+`)
+	entities := writeFile(t, dir, "entities.json", `[
+		{"uid": {"type": "User", "id": "alice"}, "parents": [{"type": "Group", "id": "eng"}]}
+	]`)
+
+	tests := []struct {
+		name    string
+		request string
+		code    int
+		want    string
+	}{
+		{
+			name:    "allow",
+			request: `{"principal": {"type": "User", "id": "alice"}, "action": {"type": "Action", "id": "Read"}, "resource": {"type": "Doc", "id": "a"}, "context": {"mfa": true}}`,
+			code:    0,
+			want:    "{\n  \"decision\": \"allow\",\n  \"reasons\": [\n    \"eng-read\"\n  ],\n  \"errors\": []\n}\n",
+		},
+		{
+			name:    "missing_context",
+			request: `{"principal": {"type": "User", "id": "alice"}, "action": {"type": "Action", "id": "Read"}, "resource": {"type": "Doc", "id": "a"}}`,
+			code:    1,
+			want:    "{\n  \"decision\": \"deny\",\n  \"reasons\": [],\n  \"errors\": [\n    {\n      \"policy_id\": \"eng-read\",\n      \"message\": \"record does not have the attribute \\\"mfa\\\"\"\n    }\n  ]\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := writeFile(t, dir, tt.name+".json", tt.request)
+			code, stdout, stderr := run("authorize", "-policies", policies, "-entities", entities, request)
+			assert.Equal(t, tt.code, code, stderr)
+			assert.Equal(t, tt.want, stdout)
+		})
+	}
+
+	code, _, stderr := run("authorize", "-policies", policies, writeFile(t, dir, "bad.json", `{"principal": {"type": "User", "id": "alice"}}`))
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "authorize: action: must be specified\n", stderr)
+}
@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+)
+
+func runServe(args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("serve", "", stderr)
+	var policyPaths stringsFlag
+	flags.Var(&policyPaths, "policies", "a Cedar policy file or directory of '.cedar' files (may be repeated)")
+	schemaPath := flags.String("schema", "", "the path to a Cedar schema in JSON format, which the policies are validated against and which defines the action groups")
+	entitiesPath := flags.String("entities", "", "the path to the entities in the Cedar JSON entity format")
+	addr := flags.String("addr", "localhost:8180", "the address to listen on")
+	interval := flags.Duration("interval", time.Second, "how often the files are checked for changes")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return errors.New("unexpected arguments")
+	}
+	if len(policyPaths) == 0 {
+		return errors.New("at least one -policies path must be specified")
+	}
+
+	logger := log.New(stderr, "", log.LstdFlags)
+
+	s := &server{
+		policyPaths:  policyPaths,
+		schemaPath:   *schemaPath,
+		entitiesPath: *entitiesPath,
+		logger:       logger,
+	}
+	if _, err := s.reload(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go s.watch(ctx, *interval)
+
+	srv := &http.Server{Addr: *addr, Handler: s.handler()}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	logger.Printf("listening on http://%s", *addr)
+	err := srv.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		wg.Wait()
+		return nil
+	}
+	stop()
+	return err
+}
+
+// server evaluates authorization requests against policy files,
+// reloading them when they change.
+type server struct {
+	policyPaths  []string
+	schemaPath   string
+	entitiesPath string
+	logger       *log.Logger
+
+	state atomic.Pointer[serverState]
+
+	// failed is the fingerprint of the files if they couldn't be loaded,
+	// so that they are only reloaded once they change again.
+	failed string
+}
+
+// serverState is the result of loading the files.
+type serverState struct {
+	authorizer *cedarpolicy.Authorizer
+	entities   cedarpolicy.Entities
+
+	// fingerprint identifies the version of the files which were loaded.
+	fingerprint string
+}
+
+// fingerprint returns a string which changes when any of
+// the files are modified, added or removed.
+func (s *server) fingerprint() (string, error) {
+	files, err := policyFiles(s.policyPaths)
+	if err != nil {
+		return "", err
+	}
+	for _, path := range []string{s.schemaPath, s.entitiesPath} {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+
+	var b strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+// reload loads the files if they have changed since they were last loaded,
+// and returns true if they were reloaded. If the files can't be loaded,
+// the previously loaded files continue to be used.
+func (s *server) reload() (bool, error) {
+	fingerprint, err := s.fingerprint()
+	if err != nil {
+		return false, err
+	}
+	if current := s.state.Load(); current != nil && current.fingerprint == fingerprint {
+		return false, nil
+	}
+	if fingerprint == s.failed {
+		return false, nil
+	}
+
+	state, count, err := s.load()
+	if err != nil {
+		s.failed = fingerprint
+		return false, err
+	}
+	state.fingerprint = fingerprint
+	s.state.Store(state)
+
+	s.logger.Printf("loaded %d policies", count)
+	return true, nil
+}
+
+func (s *server) load() (*serverState, int, error) {
+	var schema *cedarpolicy.Schema
+	if s.schemaPath != "" {
+		data, err := os.ReadFile(s.schemaPath)
+		if err != nil {
+			return nil, 0, err
+		}
+		schema, err = cedarpolicy.ParseSchema(data)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to parse schema: %w", err)
+		}
+	}
+
+	files, err := policyFiles(s.policyPaths)
+	if err != nil {
+		return nil, 0, err
+	}
+	var policies []cedarpolicy.Policy
+	for _, file := range files {
+		p, err := readPolicies(file)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		policies = append(policies, p...)
+	}
+
+	if schema != nil {
+		var errs []error
+		for i, policy := range policies {
+			for _, err := range schema.ValidatePolicy(policy) {
+				errs = append(errs, fmt.Errorf("policy %q: %w", policy.ID(i), err))
+			}
+		}
+		if len(errs) > 0 {
+			return nil, 0, fmt.Errorf("policies are not valid for the schema: %w", errors.Join(errs...))
+		}
+	}
+
+	authorizer, err := cedarpolicy.NewAuthorizer(policies)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entities := cedarpolicy.Entities{}
+	if s.entitiesPath != "" {
+		data, err := os.ReadFile(s.entitiesPath)
+		if err != nil {
+			return nil, 0, err
+		}
+		entities, err = cedarpolicy.ParseEntities(data)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	if schema != nil {
+		entities = schema.WithActions(entities)
+	}
+
+	return &serverState{authorizer: authorizer, entities: entities}, len(policies), nil
+}
+
+// watch reloads the files when they change, until the context is cancelled.
+func (s *server) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.reload(); err != nil {
+				s.logger.Printf("unable to reload files, continuing to use the previous version: %s", err)
+			}
+		}
+	}
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /is_authorized", s.isAuthorized)
+	mux.HandleFunc("POST /batch_is_authorized", s.batchIsAuthorized)
+	return mux
+}
+
+func (s *server) isAuthorized(w http.ResponseWriter, r *http.Request) {
+	var input avpIsAuthorizedInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, fmt.Errorf("unable to parse request: %w", err))
+		return
+	}
+
+	state := s.state.Load()
+
+	entities, err := input.Entities.cedarEntities(state.entities)
+	if err != nil {
+		writeError(w, fmt.Errorf("entities: %w", err))
+		return
+	}
+	req, err := input.cedarRequest()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, avpOutput(state.authorizer.IsAuthorized(entities, req)))
+}
+
+func (s *server) batchIsAuthorized(w http.ResponseWriter, r *http.Request) {
+	var input avpBatchIsAuthorizedInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, fmt.Errorf("unable to parse request: %w", err))
+		return
+	}
+
+	state := s.state.Load()
+
+	entities, err := input.Entities.cedarEntities(state.entities)
+	if err != nil {
+		writeError(w, fmt.Errorf("entities: %w", err))
+		return
+	}
+
+	out := avpBatchIsAuthorizedOutput{Results: []avpBatchIsAuthorizedOutputItem{}}
+	for i, item := range input.Requests {
+		req, err := item.cedarRequest()
+		if err != nil {
+			writeError(w, fmt.Errorf("request %d: %w", i, err))
+			return
+		}
+		out.Results = append(out.Results, avpBatchIsAuthorizedOutputItem{
+			Request:               item,
+			avpIsAuthorizedOutput: avpOutput(state.authorizer.IsAuthorized(entities, req)),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a validation error, in the same shape as the errors returned by AWS APIs.
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+}
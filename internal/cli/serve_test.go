@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func post(t *testing.T, h http.Handler, path, body string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	return rec.Code, rec.Body.String()
+}
+
+func TestServe(t *testing.T) {
+	dir := t.TempDir()
+	policyDir := filepath.Join(dir, "policies")
+	if err := os.Mkdir(policyDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, policyDir, "eng.cedar", `
+@id("eng-read")
+permit (principal in Group::"eng", action == Action::"Read", resource)
+when { context.mfa };
+`)
+	entities := writeFile(t, dir, "entities.json", `[
+		{"uid": {"type": "User", "id": "alice"}, "parents": [{"type": "Group", "id": "eng"}]}
+	]`)
+
+	s := &server{
+		policyPaths:  []string{policyDir},
+		entitiesPath: entities,
+		logger:       log.New(io.Discard, "", 0),
+	}
+	if _, err := s.reload(); err != nil {
+		t.Fatal(err)
+	}
+	h := s.handler()
+
+	code, body := post(t, h, "/is_authorized", `{
+		"policyStoreId": "local",
+		"principal": {"entityType": "User", "entityId": "alice"},
+		"action": {"actionType": "Action", "actionId": "Read"},
+		"resource": {"entityType": "Doc", "entityId": "a"},
+		"context": {"contextMap": {"mfa": {"boolean": true}}}
+	}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"decision": "ALLOW", "determiningPolicies": [{"policyId": "eng-read"}], "errors": []}`, body)
+
+	// entities in the request are used in addition to the entities file.
+	code, body = post(t, h, "/batch_is_authorized", `{
+		"entities": {"entityList": [
+			{"identifier": {"entityType": "User", "entityId": "bob"}, "parents": [{"entityType": "Group", "entityId": "eng"}]}
+		]},
+		"requests": [
+			{
+				"principal": {"entityType": "User", "entityId": "bob"},
+				"action": {"actionType": "Action", "actionId": "Read"},
+				"resource": {"entityType": "Doc", "entityId": "a"},
+				"context": {"cedarJson": "{\"mfa\": true}"}
+			},
+			{
+				"principal": {"entityType": "User", "entityId": "alice"},
+				"action": {"actionType": "Action", "actionId": "Read"},
+				"resource": {"entityType": "Doc", "entityId": "a"}
+			}
+		]
+	}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"results": [
+		{
+			"request": {
+				"principal": {"entityType": "User", "entityId": "bob"},
+				"action": {"actionType": "Action", "actionId": "Read"},
+				"resource": {"entityType": "Doc", "entityId": "a"},
+				"context": {"cedarJson": "{\"mfa\": true}"}
+			},
+			"decision": "ALLOW",
+			"determiningPolicies": [{"policyId": "eng-read"}],
+			"errors": []
+		},
+		{
+			"request": {
+				"principal": {"entityType": "User", "entityId": "alice"},
+				"action": {"actionType": "Action", "actionId": "Read"},
+				"resource": {"entityType": "Doc", "entityId": "a"}
+			},
+			"decision": "DENY",
+			"determiningPolicies": [],
+			"errors": [{"errorDescription": "policy \"eng-read\": record does not have the attribute \"mfa\""}]
+		}
+	]}`, body)
+
+	code, body = post(t, h, "/is_authorized", `{
+		"principal": {"entityType": "User", "entityId": "alice"},
+		"action": {"actionType": "Action", "actionId": "Read"},
+		"resource": {"entityType": "Doc", "entityId": "a"},
+		"context": {"contextMap": {"mfa": {"boolean": true, "long": 1}}}
+	}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.JSONEq(t, `{"message": "context: attribute \"mfa\": exactly one of 'boolean', 'entityIdentifier', 'long', 'string', 'set', 'record', 'ipaddr' or 'decimal' must be specified, got {\"boolean\":true,\"long\":1}"}`, body)
+
+	// the files are reloaded when they change.
+	reloaded, err := s.reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	writeFile(t, policyDir, "deny.cedar", `@id("deny-alice") forbid (principal == User::"alice", action, resource);`)
+	reloaded, err = s.reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+
+	request := `{
+		"principal": {"entityType": "User", "entityId": "alice"},
+		"action": {"actionType": "Action", "actionId": "Read"},
+		"resource": {"entityType": "Doc", "entityId": "a"},
+		"context": {"contextMap": {"mfa": {"boolean": true}}}
+	}`
+	_, body = post(t, h, "/is_authorized", request)
+	assert.JSONEq(t, `{"decision": "DENY", "determiningPolicies": [{"policyId": "deny-alice"}], "errors": []}`, body)
+
+	// invalid files are not loaded, and the previous version continues to be used.
+	invalid := writeFile(t, policyDir, "deny.cedar", `forbid (principal ==`)
+	// ensure the modification time changes on filesystems with a coarse resolution.
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(invalid, future, future); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err = s.reload()
+	assert.Error(t, err)
+	assert.False(t, reloaded)
+
+	_, body = post(t, h, "/is_authorized", request)
+	assert.JSONEq(t, `{"decision": "DENY", "determiningPolicies": [{"policyId": "deny-alice"}], "errors": []}`, body)
+
+	// the same invalid files are not reloaded again.
+	reloaded, err = s.reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+}
+
+func TestServe_ActionGroups(t *testing.T) {
+	dir := t.TempDir()
+	policies := writeFile(t, dir, "policies.cedar", `
+@id("read-only")
+permit (principal, action in Action::"ReadOnly", resource);
+`)
+	schema := writeFile(t, dir, "schema.json", `{
+		"": {
+			"entityTypes": {"User": {}, "Doc": {}},
+			"actions": {
+				"ReadOnly": {},
+				"Read": {"memberOf": [{"id": "ReadOnly"}], "appliesTo": {"principalTypes": ["User"], "resourceTypes": ["Doc"]}}
+			}
+		}
+	}`)
+
+	s := &server{
+		policyPaths: []string{policies},
+		schemaPath:  schema,
+		logger:      log.New(io.Discard, "", 0),
+	}
+	if _, err := s.reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	code, body := post(t, s.handler(), "/is_authorized", `{
+		"principal": {"entityType": "User", "entityId": "alice"},
+		"action": {"actionType": "Action", "actionId": "Read"},
+		"resource": {"entityType": "Doc", "entityId": "a"}
+	}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"decision": "ALLOW", "determiningPolicies": [{"policyId": "read-only"}], "errors": []}`, body)
+}
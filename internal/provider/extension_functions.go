@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &ExtensionFunction{}
+
+// ExtensionFunction is a provider function which renders a value as a call
+// to a Cedar extension constructor, such as 'ip("10.0.0.1")', so that values
+// from elsewhere in a configuration can be used in conditions as valid literals.
+type ExtensionFunction struct {
+	name        string
+	summary     string
+	description string
+	parse       func(string) (cedarpolicy.Value, error)
+}
+
+func NewIPFunction() function.Function {
+	return &ExtensionFunction{
+		name:        "ip",
+		summary:     "Renders an IP address or CIDR range as a Cedar 'ip' literal.",
+		description: "Renders an IPv4 or IPv6 address, such as '10.0.0.1', or a CIDR range, such as '10.0.0.0/8', as a Cedar 'ip' extension literal, such as 'ip(\"10.0.0.0/8\")'. Returns an error if the value is not a valid address or range.",
+		parse:       func(s string) (cedarpolicy.Value, error) { return cedarpolicy.ParseIPAddr(s) },
+	}
+}
+
+func NewDecimalFunction() function.Function {
+	return &ExtensionFunction{
+		name:        "decimal",
+		summary:     "Renders a number as a Cedar 'decimal' literal.",
+		description: "Renders a number, such as '1.5' or '2', as a Cedar 'decimal' extension literal, such as 'decimal(\"1.5\")'. Whole numbers are given a fractional part, as Cedar requires one. Returns an error if the number has more than four digits after the decimal point, or is out of range.",
+		parse: func(s string) (cedarpolicy.Value, error) {
+			if !strings.Contains(s, ".") {
+				s += ".0"
+			}
+			return cedarpolicy.ParseDecimal(s)
+		},
+	}
+}
+
+func NewDatetimeFunction() function.Function {
+	return &ExtensionFunction{
+		name:        "datetime",
+		summary:     "Renders a timestamp as a Cedar 'datetime' literal.",
+		description: "Renders a date, such as '2024-10-15', or an RFC3339 timestamp, such as the result of 'timestamp()', as a Cedar 'datetime' extension literal in UTC, such as 'datetime(\"2024-10-15T09:00:00Z\")'. Timestamps with a UTC offset such as '+01:00' are converted to UTC, as Cedar doesn't accept offsets in that form. Returns an error if the value is not a valid date or timestamp.",
+		parse: func(s string) (cedarpolicy.Value, error) {
+			v, err := cedarpolicy.ParseDatetime(s)
+			if err == nil {
+				return v, nil
+			}
+			if t, rfcErr := time.Parse(time.RFC3339, s); rfcErr == nil {
+				return cedarpolicy.NewDatetime(t), nil
+			}
+			return nil, err
+		},
+	}
+}
+
+func NewDurationFunction() function.Function {
+	return &ExtensionFunction{
+		name:        "duration",
+		summary:     "Renders a duration as a Cedar 'duration' literal.",
+		description: "Renders a duration of days, hours, minutes, seconds and milliseconds, such as '90m' or '1d12h', as a Cedar 'duration' extension literal in canonical form, such as 'duration(\"1h30m\")'. Returns an error if the value is not a valid Cedar duration.",
+		parse:       func(s string) (cedarpolicy.Value, error) { return cedarpolicy.ParseDuration(s) },
+	}
+}
+
+func (f *ExtensionFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f *ExtensionFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             f.summary,
+		MarkdownDescription: f.description,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "The value to render.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ExtensionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	v, err := f.parse(value)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Unable to render Cedar "+f.name+" literal: "+err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, v.String()))
+}
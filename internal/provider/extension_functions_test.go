@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestExtensionFunctions(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				output "ip" {
+					value = provider::cedar::ip("10.0.0.0/8")
+				}
+				output "decimal" {
+					value = provider::cedar::decimal(2)
+				}
+				output "datetime" {
+					value = provider::cedar::datetime("2024-10-15T10:00:00+01:00")
+				}
+				output "duration" {
+					value = provider::cedar::duration("90m")
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("ip", `ip("10.0.0.0/8")`),
+					resource.TestCheckOutput("decimal", `decimal("2.0")`),
+					resource.TestCheckOutput("datetime", `datetime("2024-10-15T09:00:00Z")`),
+					resource.TestCheckOutput("duration", `duration("1h30m")`),
+				),
+			},
+		},
+	})
+}
+
+func TestExtensionFunctions_Invalid(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				output "test" {
+					value = provider::cedar::ip("10.0.0.300")
+				}
+				`,
+				ExpectError: regexp.MustCompile(`invalid IP address "10.0.0.300"`),
+			},
+		},
+	})
+}
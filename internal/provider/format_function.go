@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &FormatFunction{}
+
+type FormatFunction struct{}
+
+func NewFormatFunction() function.Function {
+	return &FormatFunction{}
+}
+
+func (f *FormatFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format"
+}
+
+func (f *FormatFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Formats Cedar policy text in canonical form.",
+		MarkdownDescription: `Parses Cedar policy text and renders it in canonical form, using the same formatter as the 'format' option of the 'cedar_policyset' data source with its default settings.
+
+Conditions are parsed and their whitespace normalized, and long 'action in [...]' lists and '&&'/'||' chains are wrapped onto multiple lines. Comments are not preserved.`,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "text",
+				MarkdownDescription: "The Cedar policy text to format.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FormatFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var text string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &text))
+	if resp.Error != nil {
+		return
+	}
+
+	formatted, err := cedarpolicy.Format(text, cedarpolicy.DefaultFormatOptions())
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Unable to format Cedar policy text: "+err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatted))
+}
@@ -0,0 +1,304 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &PermissionMatrixDataSource{}
+var _ datasource.DataSourceWithConfigure = &PermissionMatrixDataSource{}
+
+type PermissionMatrixDataSource struct {
+	provider *ProviderData
+}
+
+func NewPermissionMatrixDataSource() datasource.DataSource {
+	return &PermissionMatrixDataSource{}
+}
+
+type PermissionMatrixDataSourceModel struct {
+	PolicySet      types.String            `tfsdk:"policy_set"`
+	Entities       types.String            `tfsdk:"entities"`
+	Schema         types.String            `tfsdk:"schema"`
+	Context        types.String            `tfsdk:"context"`
+	PrincipalTypes []types.String          `tfsdk:"principal_types"`
+	ResourceTypes  []types.String          `tfsdk:"resource_types"`
+	Entries        []PermissionMatrixEntry `tfsdk:"entries"`
+	SkippedActions []types.String          `tfsdk:"skipped_actions"`
+	CSV            types.String            `tfsdk:"csv"`
+	Markdown       types.String            `tfsdk:"markdown"`
+}
+
+type PermissionMatrixEntry struct {
+	Principal types.String   `tfsdk:"principal"`
+	Action    types.String   `tfsdk:"action"`
+	Resource  types.String   `tfsdk:"resource"`
+	Decision  types.String   `tfsdk:"decision"`
+	Policies  []types.String `tfsdk:"policies"`
+	Errors    []types.String `tfsdk:"errors"`
+}
+
+func (d *PermissionMatrixDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_permission_matrix"
+}
+
+func (d *PermissionMatrixDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Computes the effective permissions granted by a Cedar Policy Set for every principal, action and resource combination.",
+		MarkdownDescription: `Computes the effective permissions granted by a Cedar Policy Set for every principal, action and resource combination.
+
+If a 'schema' is provided, each action declared in the schema is evaluated for the entities matching the action's 'principalTypes' and 'resourceTypes'.
+Otherwise, every action entity in 'entities' (entities of type 'Action', or a namespaced type ending in '::Action') is evaluated for every other entity as both the principal and the resource.
+
+Actions in the schema which don't declare both 'principalTypes' and 'resourceTypes' in 'appliesTo' are not evaluated, and are reported in 'skipped_actions' and as a warning. Action groups, which other actions are members of, are not evaluated or reported.
+
+Policies which cannot be evaluated for a request, for example because a condition references a missing context attribute, are ignored when making the decision and are reported in the 'errors' attribute of the entry.
+
+Conditions using the methods of the 'datetime' and 'duration' extensions, such as 'toDate' or 'offset', or the entity tag methods 'hasTag' and 'getTag', are not supported in evaluation and are reported as an error.
+`,
+		Attributes: map[string]schema.Attribute{
+			"policy_set": schema.StringAttribute{
+				MarkdownDescription: "The Cedar PolicySet to evaluate, as text. For example, the 'text' attribute of a 'cedar_policyset' data source.",
+				Required:            true,
+			},
+			"entities": schema.StringAttribute{
+				MarkdownDescription: "The entities to evaluate the PolicySet against, in the Cedar JSON entity format.",
+				Required:            true,
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "A Cedar schema in JSON format, or the path to a file containing one. If provided, the actions and their 'appliesTo' principal and resource types are read from the schema, and actions are evaluated as members of the action groups the schema declares. Defaults to the provider's 'schema'.",
+				Optional:            true,
+			},
+			"context": schema.StringAttribute{
+				MarkdownDescription: "The request context used for every request, as a JSON object. Defaults to an empty context.",
+				Optional:            true,
+			},
+			"principal_types": schema.ListAttribute{
+				MarkdownDescription: "If provided, only entities of these types are evaluated as principals.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"resource_types": schema.ListAttribute{
+				MarkdownDescription: "If provided, only entities of these types are evaluated as resources.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "The authorization decision for each principal, action and resource combination.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"principal": schema.StringAttribute{
+							MarkdownDescription: "The principal entity, for example 'User::\"alice\"'.",
+							Computed:            true,
+						},
+						"action": schema.StringAttribute{
+							MarkdownDescription: "The action entity, for example 'Action::\"Read\"'.",
+							Computed:            true,
+						},
+						"resource": schema.StringAttribute{
+							MarkdownDescription: "The resource entity, for example 'Document::\"plan\"'.",
+							Computed:            true,
+						},
+						"decision": schema.StringAttribute{
+							MarkdownDescription: "Either 'allow' or 'deny'.",
+							Computed:            true,
+						},
+						"policies": schema.ListAttribute{
+							MarkdownDescription: "The IDs of the policies which determined the decision. Empty if the request was denied because no permit policies apply.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"errors": schema.ListAttribute{
+							MarkdownDescription: "Errors for policies which could not be evaluated for this request.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+			"skipped_actions": schema.ListAttribute{
+				MarkdownDescription: "The actions in the schema which are not evaluated because they don't declare the principal and resource types they apply to, for example 'Action::\"Read\"'.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"csv": schema.StringAttribute{
+				MarkdownDescription: "The permission matrix rendered as CSV, with a header row.",
+				Computed:            true,
+			},
+			"markdown": schema.StringAttribute{
+				MarkdownDescription: "The permission matrix rendered as a Markdown table.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *PermissionMatrixDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.provider = providerData
+}
+
+func (d *PermissionMatrixDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data PermissionMatrixDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policies, err := cedarpolicy.ParsePolicySet(data.PolicySet.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create data source: Cedar Permission Matrix",
+			"Unable to parse 'policy_set': "+err.Error(),
+		)
+		return
+	}
+
+	authorizer, err := cedarpolicy.NewAuthorizer(withNamespace(policies, d.provider.defaultNamespace()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create data source: Cedar Permission Matrix",
+			"Unable to parse 'policy_set': "+err.Error(),
+		)
+		return
+	}
+
+	entities, err := cedarpolicy.ParseEntities([]byte(data.Entities.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create data source: Cedar Permission Matrix",
+			"Unable to parse 'entities': "+err.Error(),
+		)
+		return
+	}
+
+	opts := cedarpolicy.MatrixOptions{
+		PrincipalTypes: stringValues(data.PrincipalTypes),
+		ResourceTypes:  stringValues(data.ResourceTypes),
+	}
+
+	if data.Schema.ValueString() != "" {
+		schemaJSON, err := readSchema(data.Schema.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create data source: Cedar Permission Matrix",
+				"Unable to read 'schema': "+err.Error(),
+			)
+			return
+		}
+
+		opts.Schema, err = cedarpolicy.ParseSchema(schemaJSON)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create data source: Cedar Permission Matrix",
+				"Unable to parse 'schema': "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if opts.Schema == nil && d.provider != nil {
+		opts.Schema = d.provider.Schema
+	}
+
+	if data.Context.ValueString() != "" {
+		v, err := cedarpolicy.ParseValueJSON([]byte(data.Context.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create data source: Cedar Permission Matrix",
+				"Unable to parse 'context': "+err.Error(),
+			)
+			return
+		}
+		record, ok := v.(cedarpolicy.Record)
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Unable to Create data source: Cedar Permission Matrix",
+				"'context' must be a JSON object",
+			)
+			return
+		}
+		opts.Context = record
+	}
+
+	entries, skipped := cedarpolicy.PermissionMatrix(authorizer, entities, opts)
+
+	data.SkippedActions = []types.String{}
+	for _, action := range skipped {
+		data.SkippedActions = append(data.SkippedActions, types.StringValue(action.String()))
+	}
+	if len(skipped) > 0 {
+		resp.Diagnostics.AddWarning(
+			"Cedar Permission Matrix: actions skipped",
+			fmt.Sprintf("The following actions are not evaluated because the schema doesn't declare the principal and resource types they apply to in 'appliesTo': %s", strings.Join(stringValues(data.SkippedActions), ", ")),
+		)
+	}
+
+	data.Entries = make([]PermissionMatrixEntry, len(entries))
+	for i, e := range entries {
+		entry := PermissionMatrixEntry{
+			Principal: types.StringValue(e.Principal.String()),
+			Action:    types.StringValue(e.Action.String()),
+			Resource:  types.StringValue(e.Resource.String()),
+			Decision:  types.StringValue(string(e.Decision)),
+			Policies:  []types.String{},
+			Errors:    []types.String{},
+		}
+		for _, id := range e.Reasons {
+			entry.Policies = append(entry.Policies, types.StringValue(id))
+		}
+		for _, policyErr := range e.Errors {
+			entry.Errors = append(entry.Errors, types.StringValue(policyErr.PolicyID+": "+policyErr.Message))
+		}
+		data.Entries[i] = entry
+	}
+
+	csv, err := cedarpolicy.RenderMatrixCSV(entries)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create data source: Cedar Permission Matrix",
+			"An unexpected error occurred while rendering the CSV output. "+
+				"Please report this issue to the provider developers.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return
+	}
+
+	data.CSV = types.StringValue(csv)
+	data.Markdown = types.StringValue(cedarpolicy.RenderMatrixMarkdown(entries))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// stringValues converts a list of Terraform strings into Go strings.
+func stringValues(values []types.String) []string {
+	var out []string
+	for _, v := range values {
+		out = append(out, v.ValueString())
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestPermissionMatrixDataSource_Simple(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_permission_matrix" "test" {
+					policy_set = <<-EOT
+					permit (principal in Group::"eng", action == Action::"Read", resource);
+					EOT
+
+					entities = jsonencode([
+						{ uid = { type = "User", id = "alice" }, parents = [{ type = "Group", id = "eng" }] },
+						{ uid = { type = "User", id = "bob" }, parents = [] },
+						{ uid = { type = "Document", id = "plan" }, parents = [] },
+						{ uid = { type = "Action", id = "Read" }, parents = [] },
+					])
+
+					principal_types = ["User"]
+					resource_types  = ["Document"]
+				}
+
+				output "test" {
+					value = data.cedar_permission_matrix.test.csv
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_permission_matrix.test", "entries.#", "2"),
+					resource.TestCheckResourceAttr("data.cedar_permission_matrix.test", "entries.0.decision", "allow"),
+					resource.TestCheckResourceAttr("data.cedar_permission_matrix.test", "entries.0.policies.0", "policy0"),
+					resource.TestCheckResourceAttr("data.cedar_permission_matrix.test", "entries.1.decision", "deny"),
+					resource.TestCheckOutput("test", "principal,action,resource,decision,policies\n"+
+						"\"User::\"\"alice\"\"\",\"Action::\"\"Read\"\"\",\"Document::\"\"plan\"\"\",allow,policy0\n"+
+						"\"User::\"\"bob\"\"\",\"Action::\"\"Read\"\"\",\"Document::\"\"plan\"\"\",deny,\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestPermissionMatrixDataSource_Schema(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// conditions using methods which can't be evaluated are errors.
+				Config: `
+				data "cedar_permission_matrix" "test" {
+					policy_set = <<-EOT
+					permit (principal, action, resource) when { resource.hasTag("owner") };
+					EOT
+
+					entities = jsonencode([])
+				}
+				`,
+				ExpectError: regexp.MustCompile(`method "hasTag" is not supported in\s+evaluation`),
+			},
+			{
+				// actions without 'appliesTo' are reported as skipped.
+				Config: `
+				data "cedar_permission_matrix" "test" {
+					policy_set = <<-EOT
+					permit (principal, action, resource);
+					EOT
+
+					entities = jsonencode([
+						{ uid = { type = "User", id = "alice" }, parents = [] },
+						{ uid = { type = "Document", id = "plan" }, parents = [] },
+					])
+
+					schema = jsonencode({
+						"" = {
+							entityTypes = { User = {}, Document = {} }
+							actions = {
+								Read = { appliesTo = { principalTypes = ["User"], resourceTypes = ["Document"] } }
+								Archive = {}
+							}
+						}
+					})
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_permission_matrix.test", "entries.#", "1"),
+					resource.TestCheckResourceAttr("data.cedar_permission_matrix.test", "entries.0.action", `Action::"Read"`),
+					resource.TestCheckResourceAttr("data.cedar_permission_matrix.test", "skipped_actions.#", "1"),
+					resource.TestCheckResourceAttr("data.cedar_permission_matrix.test", "skipped_actions.0", `Action::"Archive"`),
+				),
+			},
+		},
+	})
+}
@@ -2,27 +2,96 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
-	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
 var _ datasource.DataSource = &PolicyDataSource{}
+var _ datasource.DataSourceWithConfigure = &PolicyDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &PolicyDataSource{}
 
-type PolicyDataSource struct{}
+type PolicyDataSource struct {
+	provider *ProviderData
+}
 
 func NewPolicyDataSource() datasource.DataSource {
 	return &PolicyDataSource{}
 }
 
 type PolicyDataSourceModel struct {
-	Policies []cedarpolicy.Policy `tfsdk:"policy"`
-	Text     types.String         `tfsdk:"text"`
+	Policies      []PolicyModel `tfsdk:"policy"`
+	PoliciesJSON  types.String  `tfsdk:"policies_json"`
+	Text          types.String  `tfsdk:"text"`
+	Findings      types.List    `tfsdk:"findings"`
+	Format        *FormatModel  `tfsdk:"format"`
+	Schema        types.String  `tfsdk:"schema"`
+	Namespace     types.String  `tfsdk:"namespace"`
+	JSON          types.String  `tfsdk:"json"`
+	Hash          types.String  `tfsdk:"hash"`
+	IgnoreOrder   types.Bool    `tfsdk:"ignore_order"`
+	Order         types.String  `tfsdk:"order"`
+	Rendered      types.List    `tfsdk:"policies"`
+	TimeAttribute types.String  `tfsdk:"time_attribute"`
+	Macros        []MacroModel  `tfsdk:"condition_macro"`
+	CedarVersion  types.String  `tfsdk:"cedar_version"`
+}
+
+// RenderedPolicyModel describes a single rendered policy in the PolicySet.
+type RenderedPolicyModel struct {
+	ID   types.String `tfsdk:"id"`
+	Text types.String `tfsdk:"text"`
+	Hash types.String `tfsdk:"hash"`
+}
+
+// renderedPolicyAttrTypes are the types of the elements of the 'policies' attribute.
+var renderedPolicyAttrTypes = map[string]attr.Type{
+	"id":   types.StringType,
+	"text": types.StringType,
+	"hash": types.StringType,
+}
+
+// FormatModel describes the formatting options for rendered policies.
+type FormatModel struct {
+	Indent    types.String `tfsdk:"indent"`
+	LineWidth types.Int64  `tfsdk:"line_width"`
+}
+
+// Options returns the formatting options, using the defaults for any unset fields.
+func (m FormatModel) Options() cedarpolicy.FormatOptions {
+	opts := cedarpolicy.DefaultFormatOptions()
+	if !m.Indent.IsNull() {
+		opts.Indent = m.Indent.ValueString()
+	}
+	if !m.LineWidth.IsNull() {
+		opts.LineWidth = int(m.LineWidth.ValueInt64())
+	}
+	return opts
+}
+
+// FindingModel describes a policy which is redundant or overridden.
+type FindingModel struct {
+	Kind       types.String `tfsdk:"kind"`
+	PolicyID   types.String `tfsdk:"policy_id"`
+	ByPolicyID types.String `tfsdk:"by_policy_id"`
+	Message    types.String `tfsdk:"message"`
+}
+
+// findingAttrTypes are the types of the elements of the 'findings' attribute.
+var findingAttrTypes = map[string]attr.Type{
+	"kind":         types.StringType,
+	"policy_id":    types.StringType,
+	"by_policy_id": types.StringType,
+	"message":      types.StringType,
 }
 
 func (d *PolicyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -42,15 +111,116 @@ For the principal clause, you can provide 'principal', 'principal_in', 'principa
 For the action clause, you can provide 'action', 'action_in', or 'any_action'.
 For the resource clause, you can provide 'resource', 'resource_in', 'resource_is', or 'any_resource'.
 
+Entities may be given either as a 'type' and 'id', or as a Cedar entity UID string such as 'principal = { uid = "CF::User::\"alice\"" }'. The UID string is always wrapped in an object with a 'uid' attribute, as Terraform providers can't accept attributes which may be either a string or an object inside repeated blocks such as 'policy', or inside lists such as 'action_in'.
+
 You may also optionally provide one or more 'when' and 'unless' conditions as blocks.
+
+Policies which are fully covered by another policy, or permits which are overridden by an unconditional forbid, are reported as warnings and in the 'findings' attribute.
+
+Policies may contain values which are not known until apply, such as the ID of a resource created in the same run. Terraform reads the data source once all of its values are known, so its attributes are unknown until then. The known parts of such policies are still checked when planning: the scope clauses, entity UIDs, conditions which don't use a macro, the Cedar version, and, if the data source sets both 'schema' and 'namespace', the schema. Everything else is checked when the data source is read.
 `,
 		Attributes: map[string]schema.Attribute{
+			"policies_json": schema.StringAttribute{
+				MarkdownDescription: "Additional policies as a JSON document, such as the output of 'jsonencode' or 'jsonencode(yamldecode(...))'. The document may be a single policy or a list of policies, where each policy has the same fields as a 'policy' block, for example 'jsonencode([{ effect = \"permit\", any_principal = true, any_action = true, any_resource = true }])'. Policies in the Cedar JSON policy format, and Cedar JSON policy sets, are also accepted. The policies are added after any 'policy' blocks, and are validated and rendered in the same way.",
+				Optional:            true,
+			},
 			"text": schema.StringAttribute{
 				MarkdownDescription: "The Cedar PolicySet, rendered as a string.",
 				Computed:            true,
 			},
+			"json": schema.StringAttribute{
+				MarkdownDescription: "The Cedar PolicySet, rendered in the Cedar JSON policy set format with each policy keyed by its ID. Null if a condition is not a valid Cedar expression.",
+				Computed:            true,
+			},
+			"hash": schema.StringAttribute{
+				MarkdownDescription: "A hash of the PolicySet which only changes when the meaning of a policy changes. Whitespace in conditions is normalized and annotations are sorted before hashing, so formatting changes don't change the hash. Suitable for use with 'replace_triggered_by'.",
+				Computed:            true,
+			},
+			"order": schema.StringAttribute{
+				MarkdownDescription: "The order to render the policies in. One of 'as_written' (the default), 'by_id', 'by_annotation:<name>' to sort by the value of an annotation, with policies without the annotation last, or 'by_effect_then_id' to render forbid policies before permit policies. Ties are broken by policy ID. The order applies to 'text', 'json' and 'policies'. Policies without an '@id' annotation are identified by their position, so every policy must have one unless the order is 'as_written'.",
+				Optional:            true,
+			},
+			"ignore_order": schema.BoolAttribute{
+				MarkdownDescription: "If true, reordering the policies doesn't change the 'hash' of the PolicySet. Defaults to false.",
+				Optional:            true,
+			},
+			"policies": schema.ListNestedAttribute{
+				MarkdownDescription: "Each policy in the PolicySet, rendered separately.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the policy, from its '@id' annotation, or 'policy' followed by its index if no ID annotation is present.",
+							Computed:            true,
+						},
+						"text": schema.StringAttribute{
+							MarkdownDescription: "The policy, rendered as a string.",
+							Computed:            true,
+						},
+						"hash": schema.StringAttribute{
+							MarkdownDescription: "A hash of the policy which only changes when the meaning of the policy changes.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "A namespace which is prepended to unqualified entity types in the policy scopes, so that 'User' is rendered as 'CF::User' in the 'CF' namespace. Entity types which already contain '::' are not changed. Actions are qualified in the same way, as Cedar actions have the type 'Action' within their namespace: 'Action::\"Read\"' is rendered as 'CF::Action::\"Read\"'. Overrides the provider's 'default_namespace'. Condition text is not modified.",
+				Optional:            true,
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "A Cedar schema in JSON format, or the path to a file containing one. If provided, the policies are validated against the schema, and entity types or actions which are not declared in the schema are reported as errors. Overrides the provider's 'schema'.",
+				Optional:            true,
+			},
+			"time_attribute": schema.StringAttribute{
+				MarkdownDescription: "The attribute which the 'not_before' and 'not_after' bounds of policies are compared with, which must be a Cedar 'datetime' in authorization requests. Overrides the provider's 'time_attribute'. Defaults to 'context.now'.",
+				Optional:            true,
+			},
+			"cedar_version": schema.StringAttribute{
+				MarkdownDescription: cedarVersionDescription + " Overrides the provider's 'cedar_version'.",
+				Optional:            true,
+			},
+			"format": schema.SingleNestedAttribute{
+				MarkdownDescription: "If provided, policies are rendered using the canonical Cedar formatter. Conditions are parsed and their whitespace normalized, and long 'action in [...]' lists and '&&'/'||' chains are wrapped onto multiple lines. The output matches the 'provider::cedar::format' function. Overrides the provider's 'format'.",
+				Optional:            true,
+				Attributes:          formatAttributes,
+			},
+			"findings": schema.ListNestedAttribute{
+				MarkdownDescription: "Policies in the PolicySet which are redundant or overridden, based on an analysis of the policy scopes. Policies are identified by their '@id' annotation, or by 'policy' followed by their index if no ID annotation is present.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"kind": schema.StringAttribute{
+							MarkdownDescription: "Either 'redundant', if the policy is fully covered by another policy with the same effect, or 'overridden', if the policy is a permit which is fully covered by an unconditional forbid.",
+							Computed:            true,
+						},
+						"policy_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the redundant or overridden policy.",
+							Computed:            true,
+						},
+						"by_policy_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the policy which covers the redundant or overridden policy.",
+							Computed:            true,
+						},
+						"message": schema.StringAttribute{
+							MarkdownDescription: "A human-readable description of the finding.",
+							Computed:            true,
+						},
+					},
+				},
+			},
 		},
 		Blocks: map[string]schema.Block{
+			"condition_macro": schema.ListNestedBlock{
+				MarkdownDescription: macroDescriptions["block"] + " Macros defined here replace provider macros with the same name.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name":   schema.StringAttribute{Required: true, MarkdownDescription: macroDescriptions["name"]},
+						"text":   schema.StringAttribute{Required: true, MarkdownDescription: macroDescriptions["text"]},
+						"params": schema.ListAttribute{Optional: true, ElementType: types.StringType, MarkdownDescription: macroDescriptions["params"]},
+					},
+				},
+			},
 			"policy": schema.ListNestedBlock{
 				MarkdownDescription: "a list of policies to be included in the PolicySet",
 
@@ -65,35 +235,35 @@ You may also optionally provide one or more 'when' and 'unless' conditions as bl
 							MarkdownDescription: "Specifies the principal component of the policy scope. Matches all principals. Equivalent to writing 'principal'",
 							Optional:            true,
 						},
-						"principal": schema.ObjectAttribute{
+						"principal": schema.SingleNestedAttribute{
 							MarkdownDescription: "Specifies the principal component of the policy scope. Equivalent to writing 'principal =='",
 							Optional:            true,
-							AttributeTypes:      eid.EIDAttrsForDataSource,
+							Attributes:          eidDataSourceAttributes,
 						},
 						"principal_is": schema.StringAttribute{
-							MarkdownDescription: "Specifies the principal component of the policy scope. Equivalent to writing 'principal in'",
+							MarkdownDescription: "Specifies the principal component of the policy scope. Equivalent to writing 'principal is'. Set together with 'principal_in' for 'principal is <type> in <entity>'",
 							Optional:            true,
 						},
-						"principal_in": schema.ObjectAttribute{
+						"principal_in": schema.SingleNestedAttribute{
 							MarkdownDescription: "Specifies the principal component of the policy scope. Equivalent to writing 'principal =='",
 							Optional:            true,
-							AttributeTypes:      eid.EIDAttrsForDataSource,
+							Attributes:          eidDataSourceAttributes,
 						},
 
 						"any_action": schema.BoolAttribute{
 							MarkdownDescription: "Specifies the action component of the policy scope. Matches all actions. Equivalent to writing 'action'",
 							Optional:            true,
 						},
-						"action": schema.ObjectAttribute{
+						"action": schema.SingleNestedAttribute{
 							MarkdownDescription: "Specifies the action component of the policy scope. Equivalent to writing 'action =='",
 							Optional:            true,
-							AttributeTypes:      eid.EIDAttrsForDataSource,
+							Attributes:          eidDataSourceAttributes,
 						},
-						"action_in": schema.ListAttribute{
+						"action_in": schema.ListNestedAttribute{
 							MarkdownDescription: "Specifies the action component of the policy scope. Equivalent to writing 'action in'",
 							Optional:            true,
-							ElementType: basetypes.ObjectType{
-								AttrTypes: eid.EIDAttrsForDataSource,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: eidDataSourceAttributes,
 							},
 						},
 
@@ -101,19 +271,28 @@ You may also optionally provide one or more 'when' and 'unless' conditions as bl
 							MarkdownDescription: "Specifies the resource component of the policy scope. Matches all resources. Equivalent to writing 'resource'",
 							Optional:            true,
 						},
-						"resource": schema.ObjectAttribute{
+						"resource": schema.SingleNestedAttribute{
 							MarkdownDescription: "Specifies the resource component of the policy scope. Equivalent to writing 'resource =='",
 							Optional:            true,
-							AttributeTypes:      eid.EIDAttrsForDataSource,
+							Attributes:          eidDataSourceAttributes,
 						},
 						"resource_is": schema.StringAttribute{
-							MarkdownDescription: "Specifies the resource component of the policy scope. Equivalent to writing 'resource is'",
+							MarkdownDescription: "Specifies the resource component of the policy scope. Equivalent to writing 'resource is'. Set together with 'resource_in' for 'resource is <type> in <entity>'",
 							Optional:            true,
 						},
-						"resource_in": schema.ObjectAttribute{
+						"resource_in": schema.SingleNestedAttribute{
 							MarkdownDescription: "Specifies the resource component of the policy scope. Equivalent to writing 'resource in'",
 							Optional:            true,
-							AttributeTypes:      eid.EIDAttrsForDataSource,
+							Attributes:          eidDataSourceAttributes,
+						},
+
+						"not_before": schema.StringAttribute{
+							MarkdownDescription: "An RFC3339 timestamp, such as '2024-10-15T09:00:00Z', before which the policy does not apply. Rendered as a 'when' condition comparing the 'time_attribute' with a Cedar 'datetime', such as 'context.now >= datetime(\"2024-10-15T09:00:00Z\")'.",
+							Optional:            true,
+						},
+						"not_after": schema.StringAttribute{
+							MarkdownDescription: "An RFC3339 timestamp, such as '2024-10-15T17:00:00Z', after which the policy does not apply. Rendered as a 'when' condition comparing the 'time_attribute' with a Cedar 'datetime', such as 'context.now <= datetime(\"2024-10-15T17:00:00Z\")'. If the provider's 'drop_expired' option is set, the policy is left out of the PolicySet once this time has passed.",
+							Optional:            true,
 						},
 					},
 					Blocks: map[string]schema.Block{
@@ -139,8 +318,17 @@ You may also optionally provide one or more 'when' and 'unless' conditions as bl
 							NestedObject: schema.NestedBlockObject{
 								Attributes: map[string]schema.Attribute{
 									"text": schema.StringAttribute{
-										MarkdownDescription: "The 'when' condition as a string. For example, 'resource.is_public && principal in Group::\"Example\"'",
-										Required:            true,
+										MarkdownDescription: "The 'when' condition as a string. For example, 'resource.is_public && principal in Group::\"Example\"'. Required unless 'macro' is specified.",
+										Optional:            true,
+									},
+									"macro": schema.StringAttribute{
+										MarkdownDescription: macroDescription,
+										Optional:            true,
+									},
+									"params": schema.MapNestedAttribute{
+										MarkdownDescription: paramsDescription,
+										Optional:            true,
+										NestedObject:        schema.NestedAttributeObject{Attributes: paramDataSourceAttributes(true)},
 									},
 								},
 							},
@@ -152,8 +340,17 @@ You may also optionally provide one or more 'when' and 'unless' conditions as bl
 							NestedObject: schema.NestedBlockObject{
 								Attributes: map[string]schema.Attribute{
 									"text": schema.StringAttribute{
-										MarkdownDescription: "The 'unless' condition as a string. For example, 'resource.is_private || principal in Group::\"Restricted\"'",
-										Required:            true,
+										MarkdownDescription: "The 'unless' condition as a string. For example, 'resource.is_private || principal in Group::\"Restricted\"'. Required unless 'macro' is specified.",
+										Optional:            true,
+									},
+									"macro": schema.StringAttribute{
+										MarkdownDescription: macroDescription,
+										Optional:            true,
+									},
+									"params": schema.MapNestedAttribute{
+										MarkdownDescription: paramsDescription,
+										Optional:            true,
+										NestedObject:        schema.NestedAttributeObject{Attributes: paramDataSourceAttributes(true)},
 									},
 								},
 							},
@@ -165,101 +362,188 @@ You may also optionally provide one or more 'when' and 'unless' conditions as bl
 	}
 }
 
-func (d *PolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var data PolicyDataSourceModel
+func (d *PolicyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
 
-	// Read Terraform configuration data into the model
-	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
 
-	renderedPolicies := make([]string, len(data.Policies))
-	for i, policy := range data.Policies {
+	d.provider = providerData
+}
 
-		var principalClauses, actionClauses, resourceClauses int
+// formatAttributes are the attributes of the 'format' block used to configure
+// the Cedar formatter.
+var formatAttributes = map[string]schema.Attribute{
+	"indent": schema.StringAttribute{
+		MarkdownDescription: "The string used for each level of indentation. Defaults to a tab.",
+		Optional:            true,
+	},
+	"line_width": schema.Int64Attribute{
+		MarkdownDescription: "The maximum line width before action lists and '&&'/'||' chains are wrapped onto multiple lines. Tabs count as four characters. Set to 0 to disable wrapping. Defaults to 80.",
+		Optional:            true,
+	},
+}
 
-		if policy.Principal != nil {
-			principalClauses++
-		}
+// ValidateConfig checks the policies before all of their values are known,
+// such as when an entity ID comes from a resource which is yet to be created.
+// Terraform only reads the data source once its whole configuration is known,
+// so without it such policies would only be checked when applying. Only the
+// known parts of the policies are checked, and they are checked in full when
+// the data source is read.
+func (d *PolicyDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data PolicyDataSourceModel
 
-		if policy.PrincipalIn != nil {
-			principalClauses++
-		}
+	// the configuration can't be read into the model while a whole block
+	// or nested object is unknown, in which case it is checked when read.
+	if diags := req.Config.Get(ctx, &data); diags.HasError() {
+		return
+	}
 
-		if policy.PrincipalIs.ValueString() != "" {
-			principalClauses++
+	for _, model := range data.Policies {
+		for _, msg := range checkScope(model) {
+			resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet", msg)
 		}
+	}
 
-		if policy.AnyPrincipal.ValueBool() {
-			principalClauses++
-		}
+	// the known parts of the policies are checked once the settings which
+	// apply to every policy in the set are known.
+	if data.Namespace.IsUnknown() || data.TimeAttribute.IsUnknown() || data.CedarVersion.IsUnknown() || data.Schema.IsUnknown() || !macrosKnown(data.Macros) || resp.Diagnostics.HasError() {
+		return
+	}
 
-		if principalClauses == 0 {
-			resp.Diagnostics.AddError(
-				"Unable to Create data source: Cedar PolicySet",
-				"a principal clause must be specified, one of: principal, principal_in, principal_is, any_principal",
-			)
-		}
+	// the provider is not yet configured when the configuration is validated,
+	// so only the data source's own settings are used. The policies are only
+	// checked against the schema if the data source sets the namespace, as
+	// the provider's default namespace may apply to them otherwise.
+	settings, diags := newPolicySetSettings(nil, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Namespace.IsNull() {
+		settings.schema = nil
+	}
+
+	// conditions using macros, and the policies in 'policies_json', are
+	// checked when read, as they may use the provider's macros.
+	for i, model := range data.Policies {
+		known := model.withoutUnknowns()
+		known.When, known.Unless = withoutMacros(known.When), withoutMacros(known.Unless)
 
-		if principalClauses > 1 {
+		policy, err := known.Policy(settings.macros)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet", err.Error())
+			continue
+		}
+		validity, err := known.Validity()
+		if err != nil {
 			resp.Diagnostics.AddError(
 				"Unable to Create data source: Cedar PolicySet",
-				"only one principal clause must be specified, one of: principal, principal_in, principal_is, any_principal",
+				fmt.Sprintf("Policy %q: %s", policy.ID(i), err),
 			)
+			continue
 		}
-
-		if policy.Action != nil {
-			actionClauses++
+		policy = policy.WithValidity(settings.timeAttribute, validity).WithNamespace(settings.namespace)
+		for _, msg := range checkPolicy(policy, i, settings.version, settings.schema) {
+			resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet", msg)
 		}
+	}
+}
 
-		if policy.ActionIn != nil {
-			actionClauses++
-		}
+func (d *PolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data PolicyDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, diags := newPolicySetSettings(d.provider, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configured, diags := configuredPolicies(data, settings.macros)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		if policy.AnyAction.ValueBool() {
-			actionClauses++
+	dropExpired := d.provider != nil && d.provider.DropExpired
+	now := time.Now()
+
+	// entity UID strings are resolved and policies are qualified with the
+	// namespace. Time bounds are rendered as conditions, and expired policies
+	// are left out if the provider is configured to drop them.
+	var policies []cedarpolicy.Policy
+	var expired []string
+	for i, model := range configured {
+		for _, msg := range checkScope(model) {
+			resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet", msg)
 		}
 
-		if actionClauses == 0 {
+		policy, err := model.Policy(settings.macros)
+		if err != nil {
 			resp.Diagnostics.AddError(
 				"Unable to Create data source: Cedar PolicySet",
-				"a action clause must be specified, one of: action, action_in, action_is, any_action",
+				err.Error(),
 			)
+			return
 		}
-
-		if actionClauses > 1 {
+		validity, err := model.Validity()
+		if err != nil {
 			resp.Diagnostics.AddError(
 				"Unable to Create data source: Cedar PolicySet",
-				"only one action clause must be specified, one of: action, action_in, action_is, any_action",
+				fmt.Sprintf("Policy %q: %s", policy.ID(i), err),
 			)
+			return
 		}
-
-		if policy.Resource != nil {
-			resourceClauses++
-		}
-
-		if policy.ResourceIn != nil {
-			resourceClauses++
+		if dropExpired && validity.Expired(now) {
+			expired = append(expired, fmt.Sprintf("%s (expired %s)", policy.ID(i), validity.NotAfter.Format(time.RFC3339)))
+			continue
 		}
 
-		if policy.ResourceIs.ValueString() != "" {
-			resourceClauses++
-		}
+		policies = append(policies, policy.WithValidity(settings.timeAttribute, validity).WithNamespace(settings.namespace))
+	}
 
-		if policy.AnyResource.ValueBool() {
-			resourceClauses++
-		}
+	if len(expired) > 0 {
+		d.provider.lint(&resp.Diagnostics,
+			"Cedar PolicySet: expired policies dropped",
+			"The following policies are not included in the PolicySet because their 'not_after' time has passed:\n\n  - "+strings.Join(expired, "\n  - "),
+		)
+	}
 
-		if resourceClauses == 0 {
-			resp.Diagnostics.AddError(
-				"Unable to Create data source: Cedar PolicySet",
-				"a resource clause must be specified, one of: resource, resource_in, resource_is, any_resource",
-			)
-		}
+	indexes, err := cedarpolicy.SortIndexes(policies, data.Order.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("order"),
+			"Unable to Create data source: Cedar PolicySet",
+			err.Error(),
+		)
+		return
+	}
+	sorted := make([]cedarpolicy.Policy, len(indexes))
+	for i, index := range indexes {
+		sorted[i] = policies[index]
+	}
+	policies = sorted
 
-		if resourceClauses > 1 {
-			resp.Diagnostics.AddError(
-				"Unable to Create data source: Cedar PolicySet",
-				"only one resource clause must be specified, one of: resource, resource_in, resource_is, any_resource",
-			)
+	renderedPolicies := make([]string, len(policies))
+	for i, policy := range policies {
+		for _, msg := range checkPolicy(policy, i, settings.version, nil) {
+			resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet", msg)
 		}
 
 		currentPolicy, err := policy.RenderString()
@@ -278,8 +562,308 @@ func (d *PolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	allPolicies := strings.Join(renderedPolicies, "\n\n") + "\n"
 
+	if settings.schema != nil && !resp.Diagnostics.HasError() {
+		for i, policy := range policies {
+			for _, err := range settings.schema.ValidatePolicy(policy) {
+				resp.Diagnostics.AddError(
+					"Unable to Create data source: Cedar PolicySet",
+					fmt.Sprintf("Policy %q is not valid for the schema: %s", policy.ID(i), err),
+				)
+			}
+		}
+	}
+
+	var format *cedarpolicy.FormatOptions
+	if data.Format != nil {
+		opts := data.Format.Options()
+		format = &opts
+	} else if d.provider != nil {
+		format = d.provider.Format
+	}
+
+	if format != nil && !resp.Diagnostics.HasError() {
+		formatted, err := cedarpolicy.FormatPolicySet(policies, *format)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create data source: Cedar PolicySet",
+				"Unable to format the PolicySet: "+err.Error(),
+			)
+			return
+		}
+		allPolicies = formatted
+	}
 	data.Text = types.StringValue(allPolicies)
 
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rendered := make([]RenderedPolicyModel, len(policies))
+	for i, policy := range policies {
+		text := renderedPolicies[i]
+		if format != nil {
+			formatted, err := policy.Format(*format)
+			if err != nil {
+				resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet", err.Error())
+				return
+			}
+			text = formatted
+		}
+
+		hash, err := policy.SemanticHash()
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet", err.Error())
+			return
+		}
+		rendered[i] = RenderedPolicyModel{
+			ID:   types.StringValue(policy.ID(i)),
+			Text: types.StringValue(text),
+			Hash: types.StringValue(hash),
+		}
+	}
+
+	data.Rendered, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: renderedPolicyAttrTypes}, rendered)
+	resp.Diagnostics.Append(diags...)
+
+	hash, err := cedarpolicy.PolicySetHash(policies, data.IgnoreOrder.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet", err.Error())
+		return
+	}
+	data.Hash = types.StringValue(hash)
+
+	data.JSON = types.StringNull()
+	policySetJSON, err := cedarpolicy.RenderPolicySetJSON(policies)
+	if err != nil {
+		d.provider.lint(&resp.Diagnostics,
+			"Cedar PolicySet: unable to render JSON",
+			"The 'json' attribute is null because the PolicySet could not be rendered in the Cedar JSON policy format: "+err.Error(),
+		)
+	} else {
+		data.JSON = types.StringValue(string(policySetJSON))
+	}
+
+	findings := []FindingModel{}
+	analyzed := make([]cedarpolicy.Policy, len(policies))
+	for i, policy := range policies {
+		analyzed[i] = policy.WithID(policy.ID(i))
+	}
+	for _, finding := range cedarpolicy.Analyze(analyzed) {
+		findings = append(findings, FindingModel{
+			Kind:       types.StringValue(string(finding.Kind)),
+			PolicyID:   types.StringValue(finding.PolicyID),
+			ByPolicyID: types.StringValue(finding.ByPolicyID),
+			Message:    types.StringValue(finding.Message()),
+		})
+
+		d.provider.lint(&resp.Diagnostics, "Cedar PolicySet: "+string(finding.Kind)+" policy", finding.Message())
+	}
+	data.Findings, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: findingAttrTypes}, findings)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// policySetSettings are the settings which apply to every policy in the set,
+// taken from the data source's attributes, or the provider's if not set.
+type policySetSettings struct {
+	namespace     string
+	timeAttribute string
+	macros        cedarpolicy.Macros
+	version       cedarpolicy.Version
+	schema        *cedarpolicy.Schema
+}
+
+// newPolicySetSettings returns the settings which apply to every policy in
+// the set. The attributes they are read from must be known. The provider may
+// be nil if it is not yet configured.
+func newPolicySetSettings(provider *ProviderData, data PolicyDataSourceModel) (policySetSettings, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	s := policySetSettings{
+		namespace:     provider.defaultNamespace(),
+		timeAttribute: provider.timeAttribute(),
+		version:       provider.cedarVersion(),
+	}
+	if !data.Namespace.IsNull() {
+		s.namespace = data.Namespace.ValueString()
+	}
+
+	if !data.TimeAttribute.IsNull() {
+		if err := checkTimeAttribute(data.TimeAttribute.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("time_attribute"),
+				"Unable to Create data source: Cedar PolicySet",
+				err.Error(),
+			)
+			return s, diags
+		}
+		s.timeAttribute = data.TimeAttribute.ValueString()
+	}
+
+	macros, err := newMacros(data.Macros)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("condition_macro"),
+			"Unable to Create data source: Cedar PolicySet",
+			err.Error(),
+		)
+		return s, diags
+	}
+	s.macros = provider.macros().With(macros)
+
+	if !data.CedarVersion.IsNull() {
+		s.version, err = cedarpolicy.ParseVersion(data.CedarVersion.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("cedar_version"),
+				"Unable to Create data source: Cedar PolicySet",
+				err.Error(),
+			)
+			return s, diags
+		}
+	}
+
+	s.schema, err = provider.schema(data.Schema.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("schema"),
+			"Unable to Create data source: Cedar PolicySet",
+			err.Error(),
+		)
+	}
+	return s, diags
+}
+
+// configuredPolicies returns the policies given as 'policy' blocks,
+// followed by the policies decoded from 'policies_json'.
+func configuredPolicies(data PolicyDataSourceModel, macros cedarpolicy.Macros) ([]PolicyModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if data.PoliciesJSON.IsNull() {
+		return data.Policies, diags
+	}
+
+	decoded, err := cedarpolicy.ParseDocumentPolicies([]byte(data.PoliciesJSON.ValueString()), macros)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("policies_json"),
+			"Unable to Create data source: Cedar PolicySet",
+			"Unable to decode 'policies_json': "+err.Error(),
+		)
+		return nil, diags
+	}
+	configured := slices.Clone(data.Policies)
+	for _, policy := range decoded {
+		configured = append(configured, newDocumentPolicyModel(policy))
+	}
+	return configured, diags
+}
+
+// withoutMacros returns the conditions which don't use a macro.
+func withoutMacros(conditions []ConditionModel) []ConditionModel {
+	var out []ConditionModel
+	for _, c := range conditions {
+		if c.Macro.ValueString() == "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// checkScope returns an error message for each of the principal, action and
+// resource scopes of the policy which doesn't have exactly one clause set.
+func checkScope(model PolicyModel) []string {
+	var msgs []string
+	for _, msg := range []string{
+		checkClauses("principal", "principal, principal_in, principal_is, any_principal",
+			isPresent(model.Principal), isOrIn(model.PrincipalIn, model.PrincipalIs), model.AnyPrincipal),
+		checkClauses("action", "action, action_in, any_action",
+			isPresent(model.Action), isPresent(model.ActionIn), model.AnyAction),
+		checkClauses("resource", "resource, resource_in, resource_is, any_resource",
+			isPresent(model.Resource), isOrIn(model.ResourceIn, model.ResourceIs), model.AnyResource),
+	} {
+		if msg != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// checkPolicy returns an error message for each problem with the policy
+// which can be found before it is rendered: actions of the wrong type,
+// features not supported by the Cedar version, and, if a schema is
+// given, entity types and actions which are not in the schema.
+func checkPolicy(policy cedarpolicy.Policy, index int, version cedarpolicy.Version, schema *cedarpolicy.Schema) []string {
+	var msgs []string
+	if err := policy.CheckActionTypes(); err != nil {
+		msgs = append(msgs, err.Error())
+	}
+	if err := version.CheckPolicy(policy); err != nil {
+		msgs = append(msgs, fmt.Sprintf("Policy %q: %s", policy.ID(index), err))
+	}
+	if schema != nil {
+		for _, err := range schema.ValidatePolicy(policy) {
+			msgs = append(msgs, fmt.Sprintf("Policy %q is not valid for the schema: %s", policy.ID(index), err))
+		}
+	}
+	return msgs
+}
+
+// macrosKnown returns true if all of the values of the macros are known.
+func macrosKnown(macros []MacroModel) bool {
+	for _, m := range macros {
+		if !m.isKnown() {
+			return false
+		}
+	}
+	return true
+}
+
+// checkClauses returns an error message unless exactly one of the clauses of
+// the policy scope is set. Clauses which are not yet known may be set or unset.
+func checkClauses(kind, names string, clauses ...types.Bool) string {
+	var set, unknown int
+	for _, clause := range clauses {
+		switch {
+		case clause.IsUnknown():
+			unknown++
+		case clause.ValueBool():
+			set++
+		}
+	}
+
+	if set == 0 && unknown == 0 {
+		return fmt.Sprintf("a %s clause must be specified, one of: %s", kind, names)
+	}
+	if set > 1 {
+		return fmt.Sprintf("only one %s clause must be specified, one of: %s", kind, names)
+	}
+	return ""
+}
+
+// isPresent returns whether a scope clause given as a nested attribute is set.
+func isPresent[T any](v *T) types.Bool {
+	return types.BoolValue(v != nil)
+}
+
+// isSet returns whether a scope clause given as a string is set,
+// which is unknown if the string is unknown.
+func isSet(v types.String) types.Bool {
+	if v.IsUnknown() {
+		return types.BoolUnknown()
+	}
+	return types.BoolValue(v.ValueString() != "")
+}
+
+// isOrIn returns whether an 'in' or 'is' scope clause is set. Both may be
+// set together, as a single 'is <type> in <entity>' clause.
+func isOrIn[T any](in *T, is types.String) types.Bool {
+	if in != nil {
+		return types.BoolValue(true)
+	}
+	return isSet(is)
+}
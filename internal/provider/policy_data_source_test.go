@@ -1,9 +1,12 @@
 package provider
 
 import (
+	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
@@ -112,3 +115,1077 @@ func TestPolicyDataSource_InOperator(t *testing.T) {
 		},
 	})
 }
+
+func TestPolicyDataSource_IsInOperator(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						principal_is = "User"
+						principal = {
+							type = "User"
+							id = "alice"
+						}
+						any_action = true
+						any_resource = true
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`only one principal clause must be specified`),
+			},
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						principal_is = "User"
+						principal_in = {
+							type = "Group"
+							id = "test"
+						}
+						any_action = true
+						resource_is = "Document"
+						resource_in = {
+							type = "Folder"
+							id = "example"
+						}
+					}
+				}
+
+				output "test" {
+					value = data.cedar_policyset.test.text
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("test", "permit (\n\tprincipal is User in Group::\"test\",\n\taction,\n\tresource is Document in Folder::\"example\"\n);\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_Findings(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						any_principal = true
+						action = {
+							type = "Action"
+							id = "Read"
+						}
+						any_resource = true
+					}
+
+					policy {
+						annotation {
+							name = "id"
+							value = "eng-read"
+						}
+
+						effect = "permit"
+						principal_in = {
+							type = "Group"
+							id = "eng"
+						}
+						action = {
+							type = "Action"
+							id = "Read"
+						}
+						any_resource = true
+					}
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "findings.#", "1"),
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "findings.0.kind", "redundant"),
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "findings.0.policy_id", "eng-read"),
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "findings.0.by_policy_id", "policy0"),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_Format(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					format = {
+						indent = "  "
+						line_width = 40
+					}
+
+					policy {
+						effect = "permit"
+						any_principal = true
+						action_in = [
+							{
+								type = "Action"
+								id = "Read"
+							},
+							{
+								type = "Action"
+								id = "List"
+							}
+						]
+						any_resource = true
+
+						when {
+							text = "context.mfa   &&  context.device.managed"
+						}
+					}
+				}
+
+				output "test" {
+					value = data.cedar_policyset.test.text
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("test", "permit (\n  principal,\n  action in [\n    Action::\"Read\",\n    Action::\"List\"\n  ],\n  resource\n)\nwhen {\n  context.mfa && context.device.managed\n};\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_ProviderConfig(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "cedar" {
+					default_namespace = "CF"
+					schema = jsonencode({
+						CF = {
+							entityTypes = {
+								User = {}
+								Document = {}
+							}
+							actions = {
+								Read = {
+									appliesTo = {
+										principalTypes = ["User"]
+										resourceTypes = ["Document"]
+									}
+								}
+							}
+						}
+					})
+				}
+
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						principal_is = "User"
+						action = { type = "Action", id = "Read" }
+						any_resource = true
+					}
+				}
+
+				output "test" {
+					value = data.cedar_policyset.test.text
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("test", "permit (\n\tprincipal is CF::User,\n\taction == CF::Action::\"Read\",\n\tresource\n);\n"),
+				),
+			},
+			{
+				Config: `
+				provider "cedar" {
+					default_namespace = "CF"
+					schema = jsonencode({
+						CF = {
+							entityTypes = {
+								User = {}
+							}
+							actions = {}
+						}
+					})
+				}
+
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						principal_is = "User"
+						action = { type = "Action", id = "Read" }
+						any_resource = true
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`unknown action\s+CF::Action::"Read"`),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_UnknownValues(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// the known parts of the policy are checked when planning,
+				// before the principal's ID is known.
+				Config: unknownValuesConfig(`
+					principal = { type = "User", id = terraform_data.user.output }
+					action = { type = "Action", id = "Write" }
+					any_resource = true
+				`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`unknown action\s+CF::Action::"Write"`),
+			},
+			{
+				Config: unknownValuesConfig(`
+					principal = { type = "User", id = terraform_data.user.output }
+					any_principal = true
+					action = { type = "Action", id = "Read" }
+					any_resource = true
+				`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`only one principal clause must be specified`),
+			},
+			{
+				Config: unknownValuesConfig(`
+					principal = { type = "User", id = terraform_data.user.output }
+					action = { type = "Action", id = "Read" }
+					any_resource = true
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "text", "permit (\n\tprincipal == CF::User::\"alice\",\n\taction == CF::Action::\"Read\",\n\tresource\n);\n"),
+				),
+			},
+		},
+	})
+}
+
+// unknownValuesConfig returns a configuration with a policy whose scope
+// may refer to the output of 'terraform_data.user', which is unknown
+// until it is created.
+func unknownValuesConfig(scope string) string {
+	return `
+	resource "terraform_data" "user" {
+		input = "alice"
+	}
+
+	data "cedar_policyset" "test" {
+		namespace = "CF"
+		schema = jsonencode({
+			CF = {
+				entityTypes = {
+					User = {}
+					Document = {}
+				}
+				actions = {
+					Read = {
+						appliesTo = {
+							principalTypes = ["User"]
+							resourceTypes = ["Document"]
+						}
+					}
+				}
+			}
+		})
+
+		policy {
+			effect = "permit"
+			` + scope + `
+		}
+	}
+	`
+}
+
+func TestPolicyDataSource_Strict(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "cedar" {
+					strict = true
+				}
+
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+					}
+
+					policy {
+						effect = "forbid"
+						any_principal = true
+						any_action = true
+						any_resource = true
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`overridden policy`),
+			},
+			{
+				// expired policies which are dropped are errors.
+				Config: `
+				provider "cedar" {
+					strict = true
+					drop_expired = true
+				}
+
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+						not_after = "2020-01-01T00:00:00Z"
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`expired policies dropped`),
+			},
+			{
+				// policy sets which can't be rendered as JSON are errors.
+				Config: `
+				provider "cedar" {
+					strict = true
+				}
+
+				data "cedar_policyset" "test" {
+					policy {
+						annotation {
+							name = "id"
+							value = "admins"
+						}
+						effect = "permit"
+						principal_in = { type = "Group", id = "admins" }
+						any_action = true
+						any_resource = true
+					}
+
+					policy {
+						annotation {
+							name = "id"
+							value = "admins"
+						}
+						effect = "permit"
+						principal_in = { type = "Group", id = "owners" }
+						any_action = true
+						any_resource = true
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`unable to render JSON`),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_TimeBounds(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "cedar" {
+					drop_expired = true
+				}
+
+				data "cedar_policyset" "test" {
+					time_attribute = "context.request_time"
+
+					policy {
+						effect = "permit"
+						principal = { uid = "User::\"alice\"" }
+						any_action = true
+						any_resource = true
+						not_before = "2024-10-15T09:00:00Z"
+						not_after = "2999-01-01T00:00:00+01:00"
+
+						when {
+							text = "resource.public"
+						}
+					}
+
+					policy {
+						annotation {
+							name = "id"
+							value = "expired"
+						}
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+						not_after = "2020-01-01T00:00:00Z"
+					}
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "text", `permit (
+	principal == User::"alice",
+	action,
+	resource
+)
+when {
+	context.request_time >= datetime("2024-10-15T09:00:00Z")
+}
+when {
+	context.request_time <= datetime("2998-12-31T23:00:00Z")
+}
+when {
+	resource.public
+};
+`),
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "policies.#", "1"),
+				),
+			},
+			{
+				// time bounds in 'policies_json' are handled in the same way as in 'policy' blocks.
+				Config: `
+				provider "cedar" {
+					drop_expired = true
+				}
+
+				data "cedar_policyset" "test" {
+					policies_json = jsonencode([
+						{
+							effect = "permit", any_principal = true, any_action = true, any_resource = true
+							not_before = "2024-10-15T09:00:00Z"
+						},
+						{
+							effect = "permit", any_principal = true, any_action = true, any_resource = true
+							not_after = "2020-01-01T00:00:00Z"
+						},
+					])
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "text", `permit (
+	principal,
+	action,
+	resource
+)
+when {
+	context.now >= datetime("2024-10-15T09:00:00Z")
+};
+`),
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "policies.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_CedarVersion(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					cedar_version = "2.4"
+
+					policy {
+						effect = "permit"
+						principal_is = "User"
+						any_action = true
+						any_resource = true
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`Cedar 2.4 does not support the 'is' operator\s+\(added in\s+Cedar 3.0\)`),
+			},
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					cedar_version = "3.4"
+
+					policy {
+						effect = "permit"
+						principal_is = "User"
+						any_action = true
+						any_resource = true
+					}
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "text", `permit (
+	principal is User,
+	action,
+	resource
+);
+`),
+				),
+			},
+			{
+				// conditions which can't be parsed are rendered as written.
+				Config: `
+				data "cedar_policyset" "test" {
+					cedar_version = "3.4"
+
+					policy {
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+						when {
+							text = "context has a.b"
+						}
+					}
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "text", `permit (
+	principal,
+	action,
+	resource
+)
+when {
+	context has a.b
+};
+`),
+					resource.TestCheckNoResourceAttr("data.cedar_policyset.test", "json"),
+					resource.TestCheckResourceAttrSet("data.cedar_policyset.test", "hash"),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_Macros(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					condition_macro {
+						name = "mfa_required"
+						text = "context.mfa == true && context.device.managed"
+					}
+
+					condition_macro {
+						name = "member_of"
+						text = "principal in $group"
+						params = ["group"]
+					}
+
+					policy {
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+
+						when {
+							macro = "mfa_required"
+						}
+
+						unless {
+							macro = "member_of"
+							params = {
+								group = { entity = { type = "Group", id = "suspended" } }
+							}
+						}
+					}
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "text", `permit (
+	principal,
+	action,
+	resource
+)
+when {
+	context.mfa == true && context.device.managed
+}
+unless {
+	principal in Group::"suspended"
+};
+`),
+				),
+			},
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+
+						when {
+							macro = "mfa_required"
+						}
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`macro "mfa_required" is not defined`),
+			},
+			{
+				// conditions in 'policies_json' may also use macros.
+				Config: `
+				data "cedar_policyset" "test" {
+					condition_macro {
+						name = "member_of"
+						text = "principal in $group"
+						params = ["group"]
+					}
+
+					policies_json = jsonencode([{
+						effect = "permit", any_principal = true, any_action = true, any_resource = true
+						when = [{
+							macro = "member_of"
+							params = { group = { entity = { uid = "Group::\"admins\"" } } }
+						}]
+					}])
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "text", `permit (
+	principal,
+	action,
+	resource
+)
+when {
+	principal in Group::"admins"
+};
+`),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_Params(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+
+						when {
+							text = "resource.owner == $owner"
+							params = {
+								net = { ip = "10.0.0.0/8" }
+							}
+						}
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`placeholder '\$owner' has no parameter`),
+			},
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+
+						when {
+							text = "resource.owner == $owner && context.ip.isInRange($net)"
+							params = {
+								owner = { entity = { type = "User", id = "alice\" || true || \"" } }
+								net = { ip = "10.0.0.0/8" }
+							}
+						}
+					}
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "text", `permit (
+	principal,
+	action,
+	resource
+)
+when {
+	resource.owner == User::"alice\" || true || \"" && context.ip.isInRange(ip("10.0.0.0/8"))
+};
+`),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_Namespace(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					namespace = "CF"
+
+					policy {
+						effect = "permit"
+						any_principal = true
+						action = { type = "User", id = "Read" }
+						any_resource = true
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`is not an action type`),
+			},
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					namespace = "CF"
+
+					policy {
+						effect = "permit"
+						principal_in = { type = "Group", id = "admins" }
+						action = { type = "Action", id = "Read" }
+						resource = { type = "Other::Document", id = "plan" }
+					}
+				}
+
+				output "text" {
+					value = data.cedar_policyset.test.text
+				}
+
+				output "principal" {
+					value = jsondecode(data.cedar_policyset.test.json).staticPolicies.policy0.principal.entity.type
+				}
+
+				output "action" {
+					value = jsondecode(data.cedar_policyset.test.json).staticPolicies.policy0.action.entity.type
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("text", "permit (\n\tprincipal in CF::Group::\"admins\",\n\taction == CF::Action::\"Read\",\n\tresource == Other::Document::\"plan\"\n);\n"),
+					resource.TestCheckOutput("principal", "CF::Group"),
+					resource.TestCheckOutput("action", "CF::Action"),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_EntityUIDStrings(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						principal = { uid = "CF::User" }
+						any_action = true
+						any_resource = true
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`principal: invalid entity UID`),
+			},
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "permit"
+						principal = { uid = "CF::User::\"alice\"" }
+						action_in = [
+							{ uid = "CF::Action::\"Read\"" },
+							{ type = "CF::Action", id = "List" },
+						]
+						resource_in = { uid = "CF::Folder::\"docs\"" }
+					}
+				}
+
+				output "test" {
+					value = data.cedar_policyset.test.text
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("test", "permit (\n\tprincipal == CF::User::\"alice\",\n\taction in [CF::Action::\"Read\", CF::Action::\"List\"],\n\tresource in CF::Folder::\"docs\"\n);\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_Hash(t *testing.T) {
+	config := func(condition string, reversed bool) string {
+		permit := fmt.Sprintf(`
+			policy {
+				effect = "permit"
+				any_principal = true
+				any_action = true
+				any_resource = true
+				when {
+					text = %q
+				}
+			}
+		`, condition)
+		forbid := `
+			policy {
+				effect = "forbid"
+				any_principal = true
+				any_action = true
+				any_resource = true
+				when {
+					text = "context.locked"
+				}
+			}
+		`
+		policies := permit + forbid
+		if reversed {
+			policies = forbid + permit
+		}
+
+		return fmt.Sprintf(`
+		data "cedar_policyset" "test" {
+			ignore_order = true
+			%s
+		}
+
+		output "hash" {
+			value = data.cedar_policyset.test.hash
+		}
+
+		output "policy_ids" {
+			value = join(",", data.cedar_policyset.test.policies[*].id)
+		}
+		`, policies)
+	}
+
+	var hash string
+
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("context.mfa && resource.public", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("policy_ids", "policy0,policy1"),
+					func(s *terraform.State) error {
+						hash = s.RootModule().Outputs["hash"].Value.(string)
+						return nil
+					},
+				),
+			},
+			{
+				// whitespace changes and reordering don't change the hash.
+				Config: config("context.mfa\n  &&   resource.public", true),
+				Check: func(s *terraform.State) error {
+					if got := s.RootModule().Outputs["hash"].Value.(string); got != hash {
+						return fmt.Errorf("expected hash %s, got %s", hash, got)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_Order(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					order = "by_effect_then_id"
+
+					policy {
+						effect = "permit"
+						annotation {
+							name = "id"
+							value = "b"
+						}
+						any_principal = true
+						any_action = true
+						any_resource = true
+					}
+
+					policy {
+						effect = "permit"
+						annotation {
+							name = "id"
+							value = "a"
+						}
+						any_principal = true
+						any_action = true
+						any_resource = true
+					}
+
+					policy {
+						effect = "forbid"
+						annotation {
+							name = "id"
+							value = "c"
+						}
+						any_principal = true
+						any_action = true
+						any_resource = true
+					}
+				}
+
+				output "policy_ids" {
+					value = join(",", data.cedar_policyset.test.policies[*].id)
+				}
+
+				output "json_ids" {
+					value = join(",", keys(jsondecode(data.cedar_policyset.test.json).staticPolicies))
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("policy_ids", "c,a,b"),
+					resource.TestCheckOutput("json_ids", "a,b,c"),
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "text", "@id(\"c\")\nforbid (\n\tprincipal,\n\taction,\n\tresource\n);\n\n@id(\"a\")\npermit (\n\tprincipal,\n\taction,\n\tresource\n);\n\n@id(\"b\")\npermit (\n\tprincipal,\n\taction,\n\tresource\n);\n"),
+				),
+			},
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					order = "random"
+
+					policy {
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`invalid order "random"`),
+			},
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					order = "by_id"
+
+					policy {
+						effect = "permit"
+						any_principal = true
+						any_action = true
+						any_resource = true
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`order "by_id" requires every policy to have an '@id' annotation`),
+			},
+		},
+	})
+}
+
+func TestPolicyDataSource_PoliciesJSON(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				locals {
+					grants = yamldecode(<<-EOT
+					admins:
+					  - Read
+					  - Write
+					readers:
+					  - Read
+					EOT
+					)
+				}
+
+				data "cedar_policyset" "test" {
+					policy {
+						effect = "forbid"
+						any_principal = true
+						any_action = true
+						any_resource = true
+						when {
+							text = "context.locked"
+						}
+					}
+
+					policies_json = jsonencode([
+						for group, actions in local.grants : {
+							effect       = "permit"
+							annotation   = [{ name = "id", value = group }]
+							principal_in = { type = "Group", id = group }
+							action_in    = [for action in actions : { uid = "Action::\"${action}\"" }]
+							any_resource = true
+						}
+					])
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "policies.#", "3"),
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "policies.1.id", "admins"),
+					resource.TestCheckResourceAttr("data.cedar_policyset.test", "policies.1.text", "@id(\"admins\")\npermit (\n\tprincipal in Group::\"admins\",\n\taction in [Action::\"Read\", Action::\"Write\"],\n\tresource\n);"),
+				),
+			},
+			{
+				Config: `
+				data "cedar_policyset" "test" {
+					policies_json = jsonencode([{ effect = "permit", principals = {} }])
+				}
+				`,
+				ExpectError: regexp.MustCompile(`unknown field\s+"principals"`),
+			},
+		},
+	})
+}
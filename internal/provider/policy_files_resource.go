@@ -0,0 +1,312 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// schemaFileName is the name of the file the schema is written to.
+	schemaFileName = "schema.cedarschema.json"
+
+	// entitiesFileName is the name of the file the entities are written to.
+	entitiesFileName = "entities.json"
+)
+
+var _ resource.Resource = &PolicyFilesResource{}
+var _ resource.ResourceWithModifyPlan = &PolicyFilesResource{}
+
+type PolicyFilesResource struct{}
+
+func NewPolicyFilesResource() resource.Resource {
+	return &PolicyFilesResource{}
+}
+
+type PolicyFilesResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Directory types.String `tfsdk:"directory"`
+	PolicySet types.String `tfsdk:"policy_set"`
+	Schema    types.String `tfsdk:"schema"`
+	Entities  types.String `tfsdk:"entities"`
+	Files     types.Map    `tfsdk:"files"`
+}
+
+func (r *PolicyFilesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policy_files"
+}
+
+func (r *PolicyFilesResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Writes each policy in a Cedar Policy Set to a separate file in a directory.",
+		MarkdownDescription: `Writes each policy in a Cedar Policy Set to a separate file in a directory, for use with authorizers which load a directory of '.cedar' files.
+
+Each policy is written to '<id>.cedar', where the ID is the policy's '@id' annotation, or 'policy' followed by its index if no ID annotation is present. Policies are written as they appear in the 'policy_set', keeping their formatting and the comments before them. The schema and entities, if provided, are written to '` + schemaFileName + `' and '` + entitiesFileName + `'.
+
+The resource tracks the SHA-256 hash of each file it writes. Files which are modified or deleted outside of Terraform are rewritten on the next apply. Only the files written by the resource are removed when it is destroyed.
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The directory the files are written to.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"directory": schema.StringAttribute{
+				MarkdownDescription: "The directory to write the files to. The directory is created if it does not exist.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"policy_set": schema.StringAttribute{
+				MarkdownDescription: "The Cedar PolicySet to write, as text. For example, the 'text' attribute of a 'cedar_policyset' data source.",
+				Required:            true,
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "A Cedar schema in JSON format. If provided, the policies are validated against the schema and the schema is written to '" + schemaFileName + "'.",
+				Optional:            true,
+			},
+			"entities": schema.StringAttribute{
+				MarkdownDescription: "Entities in the Cedar JSON entity format. If provided, the entities are written to '" + entitiesFileName + "'.",
+				Optional:            true,
+			},
+			"files": schema.MapAttribute{
+				MarkdownDescription: "The files written by the resource, mapping each file name to the SHA-256 hash of its contents.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (r *PolicyFilesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// the resource is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data PolicyFilesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.PolicySet.IsUnknown() || data.Schema.IsUnknown() || data.Entities.IsUnknown() {
+		data.Files = types.MapUnknown(types.StringType)
+	} else {
+		files, err := policyFiles(data)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to plan resource: Cedar Policy Files",
+				err.Error(),
+			)
+			return
+		}
+		data.Files = fileHashes(files)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &data)...)
+}
+
+func (r *PolicyFilesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PolicyFilesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	files, err := policyFiles(data)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create resource: Cedar Policy Files", err.Error())
+		return
+	}
+
+	if err := writeFiles(data.Directory.ValueString(), files, nil); err != nil {
+		resp.Diagnostics.AddError("Unable to Create resource: Cedar Policy Files", err.Error())
+		return
+	}
+
+	data.ID = data.Directory
+	data.Files = fileHashes(files)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyFilesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PolicyFilesResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// files which have been changed or removed outside of Terraform are
+	// recorded with their current hash, or removed from state, so that
+	// the difference is planned as an update.
+	hashes := map[string]attr.Value{}
+	for _, name := range ownedFiles(ctx, data) {
+		content, err := os.ReadFile(filepath.Join(data.Directory.ValueString(), name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Read resource: Cedar Policy Files", err.Error())
+			return
+		}
+		hashes[name] = types.StringValue(cedarpolicy.Hash(string(content)))
+	}
+	data.Files = types.MapValueMust(types.StringType, hashes)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyFilesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state PolicyFilesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	files, err := policyFiles(data)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update resource: Cedar Policy Files", err.Error())
+		return
+	}
+
+	if err := writeFiles(data.Directory.ValueString(), files, ownedFiles(ctx, state)); err != nil {
+		resp.Diagnostics.AddError("Unable to Update resource: Cedar Policy Files", err.Error())
+		return
+	}
+
+	data.ID = data.Directory
+	data.Files = fileHashes(files)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyFilesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PolicyFilesResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, name := range ownedFiles(ctx, data) {
+		err := os.Remove(filepath.Join(data.Directory.ValueString(), name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			resp.Diagnostics.AddError("Unable to Delete resource: Cedar Policy Files", err.Error())
+		}
+	}
+}
+
+// policyFiles returns the contents of each file to be written, keyed by file name.
+func policyFiles(data PolicyFilesResourceModel) (map[string]string, error) {
+	policies, err := cedarpolicy.ParsePolicySetSources(data.PolicySet.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse 'policy_set': %w", err)
+	}
+
+	files, err := cedarpolicy.PolicyFiles(policies)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Schema.ValueString() != "" {
+		schema, err := cedarpolicy.ParseSchema([]byte(data.Schema.ValueString()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse 'schema': %w", err)
+		}
+
+		var errs []error
+		for i, policy := range policies {
+			for _, err := range schema.ValidatePolicy(policy.Policy) {
+				errs = append(errs, fmt.Errorf("policy %q is not valid for the schema: %w", policy.ID(i), err))
+			}
+		}
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+
+		files[schemaFileName] = data.Schema.ValueString()
+	}
+
+	if data.Entities.ValueString() != "" {
+		if _, err := cedarpolicy.ParseEntities([]byte(data.Entities.ValueString())); err != nil {
+			return nil, fmt.Errorf("unable to parse 'entities': %w", err)
+		}
+		files[entitiesFileName] = data.Entities.ValueString()
+	}
+
+	return files, nil
+}
+
+func fileHashes(files map[string]string) types.Map {
+	hashes := map[string]attr.Value{}
+	for name, content := range files {
+		hashes[name] = types.StringValue(cedarpolicy.Hash(content))
+	}
+	return types.MapValueMust(types.StringType, hashes)
+}
+
+// ownedFiles returns the names of the files recorded in state, in sorted order.
+func ownedFiles(ctx context.Context, data PolicyFilesResourceModel) []string {
+	hashes := map[string]string{}
+	data.Files.ElementsAs(ctx, &hashes, false)
+
+	names := make([]string, 0, len(hashes))
+	for name := range hashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeFiles writes the files to the directory, and removes any of the previously
+// written files which are no longer required.
+func writeFiles(dir string, files map[string]string, previous []string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range previous {
+		if _, ok := files[name]; ok {
+			continue
+		}
+		err := os.Remove(filepath.Join(dir, name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestPolicyFilesResource(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "policies")
+
+	config := func(policySet string) string {
+		return fmt.Sprintf(`
+		resource "cedar_policy_files" "test" {
+			directory  = %q
+			policy_set = %q
+			entities   = "[]"
+		}
+		`, dir, policySet)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFilesNotExist(dir, "admins.cedar", "policy1.cedar", "entities.json"),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`@id("admins") permit (principal in Group::"admins", action, resource); forbid (principal, action, resource);`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cedar_policy_files.test", "files.%", "3"),
+					testCheckFileContent(filepath.Join(dir, "admins.cedar"), "@id(\"admins\") permit (principal in Group::\"admins\", action, resource);\n"),
+					testCheckFileContent(filepath.Join(dir, "entities.json"), "[]"),
+				),
+			},
+			{
+				// a file modified outside of Terraform is rewritten.
+				PreConfig: func() {
+					if err := os.WriteFile(filepath.Join(dir, "admins.cedar"), []byte("modified"), 0644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config(`@id("admins") permit (principal in Group::"admins", action, resource); forbid (principal, action, resource);`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckFileContent(filepath.Join(dir, "admins.cedar"), "@id(\"admins\") permit (principal in Group::\"admins\", action, resource);\n"),
+				),
+			},
+			{
+				// files for removed policies are deleted.
+				Config: config(`@id("admins") permit (principal in Group::"admins", action, resource);`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cedar_policy_files.test", "files.%", "2"),
+					testCheckFilesNotExist(dir, "policy1.cedar"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckFileContent(path, want string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if string(got) != want {
+			return fmt.Errorf("file %s: expected %q, got %q", path, want, string(got))
+		}
+		return nil
+	}
+}
+
+func testCheckFilesNotExist(dir string, names ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+				return fmt.Errorf("expected file %s to be removed", name)
+			}
+		}
+		return nil
+	}
+}
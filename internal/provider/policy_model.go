@@ -0,0 +1,709 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+	dataSchema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// errUnknown is returned when a policy can't be converted because
+// some of its values are not yet known, such as during a Terraform plan.
+var errUnknown = errors.New("policy contains values which are not yet known")
+
+// eidAttributes are the attributes of an entity in a resource schema.
+var eidAttributes = map[string]schema.Attribute{
+	"type": schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "The entity type. Required unless 'uid' is specified.",
+	},
+	"id": schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "The entity ID. Required unless 'uid' is specified.",
+	},
+	"uid": schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "The entity UID as a Cedar string, such as 'CF::User::\"alice\"'. May be specified instead of 'type' and 'id'.",
+	},
+}
+
+// eidDataSourceAttributes are the attributes of an entity in a data source schema.
+var eidDataSourceAttributes = map[string]dataSchema.Attribute{
+	"type": dataSchema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "The entity type. Required unless 'uid' is specified.",
+	},
+	"id": dataSchema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "The entity ID. Required unless 'uid' is specified.",
+	},
+	"uid": dataSchema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "The entity UID as a Cedar string, such as 'CF::User::\"alice\"'. May be specified instead of 'type' and 'id'.",
+	},
+}
+
+// EIDModel describes an entity, given either as a type and ID
+// or as a Cedar entity UID string.
+type EIDModel struct {
+	Type types.String `tfsdk:"type"`
+	ID   types.String `tfsdk:"id"`
+	UID  types.String `tfsdk:"uid"`
+}
+
+// newEIDModel converts an entity into its Terraform model.
+func newEIDModel(e *eid.EID) *EIDModel {
+	if e == nil {
+		return nil
+	}
+	return &EIDModel{
+		Type: types.StringValue(e.Type),
+		ID:   types.StringValue(e.ID),
+		UID:  types.StringNull(),
+	}
+}
+
+// EID returns the entity, with its 'uid' parsed into the type and ID if set.
+// It returns an error if both 'uid' and 'type' or 'id' are specified.
+func (m EIDModel) EID() (eid.EID, error) {
+	if m.UID.IsNull() {
+		return eid.New(m.Type.ValueString(), m.ID.ValueString()), nil
+	}
+	if !m.Type.IsNull() || !m.ID.IsNull() {
+		return eid.EID{}, fmt.Errorf("only one of 'uid' or 'type' and 'id' may be specified, got uid %q", m.UID.ValueString())
+	}
+	return eid.Parse(m.UID.ValueString())
+}
+
+// resolveEID returns the entity, if set, with the name of the
+// field added to any error.
+func resolveEID(field string, e *EIDModel) (*eid.EID, error) {
+	if e == nil {
+		return nil, nil
+	}
+	resolved, err := e.EID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	return &resolved, nil
+}
+
+// AnnotationModel describes an annotation on a policy.
+type AnnotationModel struct {
+	Name  types.String `tfsdk:"name"`
+	Value types.String `tfsdk:"value"`
+}
+
+// paramDescriptions are the descriptions of the attributes of a condition parameter.
+var paramDescriptions = map[string]string{
+	"entity":   "An entity, rendered as an entity UID such as 'User::\"alice\"'.",
+	"string":   "A string, rendered as an escaped Cedar string literal.",
+	"long":     "A 64-bit integer.",
+	"bool":     "A boolean.",
+	"ip":       "An IP address or CIDR range, rendered as 'ip(\"10.0.0.0/8\")'.",
+	"decimal":  "A decimal with up to four digits after the decimal point, rendered as 'decimal(\"1.5\")'.",
+	"datetime": "A date such as '2024-10-15', or a date and time such as '2024-10-15T09:00:00Z', rendered as 'datetime(\"2024-10-15\")'.",
+	"set":      "A set of values, each of which is given in the same way as a parameter.",
+}
+
+// paramAttributes returns the attributes of a condition parameter in a resource
+// schema. If set is true, the parameter may be a set of values.
+func paramAttributes(set bool) map[string]schema.Attribute {
+	attrs := map[string]schema.Attribute{
+		"entity":   schema.SingleNestedAttribute{Optional: true, Attributes: eidAttributes, MarkdownDescription: paramDescriptions["entity"]},
+		"string":   schema.StringAttribute{Optional: true, MarkdownDescription: paramDescriptions["string"]},
+		"long":     schema.Int64Attribute{Optional: true, MarkdownDescription: paramDescriptions["long"]},
+		"bool":     schema.BoolAttribute{Optional: true, MarkdownDescription: paramDescriptions["bool"]},
+		"ip":       schema.StringAttribute{Optional: true, MarkdownDescription: paramDescriptions["ip"]},
+		"decimal":  schema.StringAttribute{Optional: true, MarkdownDescription: paramDescriptions["decimal"]},
+		"datetime": schema.StringAttribute{Optional: true, MarkdownDescription: paramDescriptions["datetime"]},
+	}
+	if set {
+		attrs["set"] = schema.ListNestedAttribute{
+			Optional:            true,
+			MarkdownDescription: paramDescriptions["set"],
+			NestedObject:        schema.NestedAttributeObject{Attributes: paramAttributes(false)},
+		}
+	}
+	return attrs
+}
+
+// paramDataSourceAttributes returns the attributes of a condition parameter in a
+// data source schema. If set is true, the parameter may be a set of values.
+func paramDataSourceAttributes(set bool) map[string]dataSchema.Attribute {
+	attrs := map[string]dataSchema.Attribute{
+		"entity":   dataSchema.SingleNestedAttribute{Optional: true, Attributes: eidDataSourceAttributes, MarkdownDescription: paramDescriptions["entity"]},
+		"string":   dataSchema.StringAttribute{Optional: true, MarkdownDescription: paramDescriptions["string"]},
+		"long":     dataSchema.Int64Attribute{Optional: true, MarkdownDescription: paramDescriptions["long"]},
+		"bool":     dataSchema.BoolAttribute{Optional: true, MarkdownDescription: paramDescriptions["bool"]},
+		"ip":       dataSchema.StringAttribute{Optional: true, MarkdownDescription: paramDescriptions["ip"]},
+		"decimal":  dataSchema.StringAttribute{Optional: true, MarkdownDescription: paramDescriptions["decimal"]},
+		"datetime": dataSchema.StringAttribute{Optional: true, MarkdownDescription: paramDescriptions["datetime"]},
+	}
+	if set {
+		attrs["set"] = dataSchema.ListNestedAttribute{
+			Optional:            true,
+			MarkdownDescription: paramDescriptions["set"],
+			NestedObject:        dataSchema.NestedAttributeObject{Attributes: paramDataSourceAttributes(false)},
+		}
+	}
+	return attrs
+}
+
+// paramsDescription is the description of the 'params' attribute of a condition.
+const paramsDescription = "Values for '$name' placeholders in the condition text, which are rendered as typed and correctly escaped Cedar literals. Exactly one of the attributes of each parameter must be set. Every placeholder must have a parameter, and every parameter must be used. Placeholders within string literals and '//' comments are not replaced."
+
+// paramType is the type of the elements of the 'params' attribute.
+var paramType = schema.MapNestedAttribute{
+	NestedObject: schema.NestedAttributeObject{Attributes: paramAttributes(true)},
+}.GetType().(types.MapType).ElemType
+
+// macroDescription is the description of the 'macro' attribute of a condition.
+const macroDescription = "The name of a 'condition_macro' whose text is used as the condition, instead of specifying 'text'. Values for the macro's parameters are given in 'params'."
+
+// ConditionModel describes a 'when' or 'unless' condition of a policy.
+type ConditionModel struct {
+	Text   types.String `tfsdk:"text"`
+	Macro  types.String `tfsdk:"macro"`
+	Params types.Map    `tfsdk:"params"`
+}
+
+// MacroModel describes a 'condition_macro' block.
+type MacroModel struct {
+	Name   types.String `tfsdk:"name"`
+	Text   types.String `tfsdk:"text"`
+	Params types.List   `tfsdk:"params"`
+}
+
+// macroDescriptions are the descriptions of the attributes of a 'condition_macro' block.
+var macroDescriptions = map[string]string{
+	"block":  "A named condition which 'when' and 'unless' conditions can refer to with 'macro', so that a condition used by many policies is defined once. Macros are expanded when policies are rendered.",
+	"name":   "The name of the macro, such as 'mfa_required'.",
+	"text":   "The condition as a Cedar expression, which may contain '$name' placeholders for the macro's parameters. For example, 'context.mfa == true && context.device.managed'.",
+	"params": "The names of the macro's parameters. Each must be used as a placeholder in the text, and conditions using the macro must give a value for each.",
+}
+
+// isKnown returns true if all of the macro's values are known.
+func (m MacroModel) isKnown() bool {
+	return !m.Name.IsUnknown() && !m.Text.IsUnknown() && (m.Params.IsNull() || isFullyKnown(context.Background(), m.Params))
+}
+
+// newMacros converts the 'condition_macro' blocks into macros, checking that
+// their names are unique and their placeholders match their parameters.
+func newMacros(models []MacroModel) (cedarpolicy.Macros, error) {
+	macros := make([]cedarpolicy.Macro, len(models))
+	for i, model := range models {
+		macros[i] = cedarpolicy.Macro{Name: model.Name.ValueString(), Text: model.Text.ValueString()}
+		if !model.Params.IsNull() {
+			if diags := model.Params.ElementsAs(context.Background(), &macros[i].Params, false); diags.HasError() {
+				return nil, fmt.Errorf("macro %q: params: %s", macros[i].Name, diags.Errors()[0].Detail())
+			}
+		}
+	}
+	return cedarpolicy.NewMacros(macros)
+}
+
+// ParamValueModel describes a value of a condition parameter.
+type ParamValueModel struct {
+	Entity   *EIDModel    `tfsdk:"entity"`
+	String   types.String `tfsdk:"string"`
+	Long     types.Int64  `tfsdk:"long"`
+	Bool     types.Bool   `tfsdk:"bool"`
+	IP       types.String `tfsdk:"ip"`
+	Decimal  types.String `tfsdk:"decimal"`
+	Datetime types.String `tfsdk:"datetime"`
+}
+
+// ParamModel describes a condition parameter, which is either
+// a single value or a set of values.
+type ParamModel struct {
+	Entity   *EIDModel          `tfsdk:"entity"`
+	String   types.String       `tfsdk:"string"`
+	Long     types.Int64        `tfsdk:"long"`
+	Bool     types.Bool         `tfsdk:"bool"`
+	IP       types.String       `tfsdk:"ip"`
+	Decimal  types.String       `tfsdk:"decimal"`
+	Datetime types.String       `tfsdk:"datetime"`
+	Set      *[]ParamValueModel `tfsdk:"set"`
+}
+
+// errParamValue is returned unless exactly one attribute of a parameter is set.
+var errParamValue = errors.New("exactly one of 'entity', 'string', 'long', 'bool', 'set', 'ip', 'decimal' or 'datetime' must be specified")
+
+// values returns the Cedar values of each of the attributes which are set.
+func (m ParamValueModel) values() ([]cedarpolicy.Value, error) {
+	var values []cedarpolicy.Value
+	if m.Entity != nil {
+		e, err := m.Entity.EID()
+		if err != nil {
+			return nil, fmt.Errorf("entity: %w", err)
+		}
+		values = append(values, cedarpolicy.EntityUID{Type: e.Type, ID: e.ID})
+	}
+	if !m.String.IsNull() {
+		values = append(values, cedarpolicy.String(m.String.ValueString()))
+	}
+	if !m.Long.IsNull() {
+		values = append(values, cedarpolicy.Long(m.Long.ValueInt64()))
+	}
+	if !m.Bool.IsNull() {
+		values = append(values, cedarpolicy.Bool(m.Bool.ValueBool()))
+	}
+	if !m.IP.IsNull() {
+		v, err := cedarpolicy.ParseIPAddr(m.IP.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("ip: %w", err)
+		}
+		values = append(values, v)
+	}
+	if !m.Decimal.IsNull() {
+		v, err := cedarpolicy.ParseDecimal(m.Decimal.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("decimal: %w", err)
+		}
+		values = append(values, v)
+	}
+	if !m.Datetime.IsNull() {
+		v, err := cedarpolicy.ParseDatetime(m.Datetime.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("datetime: %w", err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// Value returns the Cedar value of the parameter.
+func (m ParamValueModel) Value() (cedarpolicy.Value, error) {
+	values, err := m.values()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, errParamValue
+	}
+	return values[0], nil
+}
+
+// Value returns the Cedar value of the parameter.
+func (m ParamModel) Value() (cedarpolicy.Value, error) {
+	values, err := ParamValueModel{
+		Entity:   m.Entity,
+		String:   m.String,
+		Long:     m.Long,
+		Bool:     m.Bool,
+		IP:       m.IP,
+		Decimal:  m.Decimal,
+		Datetime: m.Datetime,
+	}.values()
+	if err != nil {
+		return nil, err
+	}
+	if m.Set != nil {
+		set := make(cedarpolicy.Set, len(*m.Set))
+		for i, elem := range *m.Set {
+			if set[i], err = elem.Value(); err != nil {
+				return nil, fmt.Errorf("set[%d]: %w", i, err)
+			}
+		}
+		values = append(values, set)
+	}
+	if len(values) != 1 {
+		return nil, errParamValue
+	}
+	return values[0], nil
+}
+
+// Condition returns the condition, with the values of its parameters
+// substituted for the placeholders in its text. If the condition refers
+// to a macro, the macro's text is used.
+func (m ConditionModel) Condition(macros cedarpolicy.Macros) (cedarpolicy.Condition, error) {
+	switch {
+	case !m.Text.IsNull() && !m.Macro.IsNull():
+		return cedarpolicy.Condition{}, fmt.Errorf("only one of 'text' or 'macro' may be specified")
+	case m.Text.IsNull() && m.Macro.IsNull():
+		return cedarpolicy.Condition{}, fmt.Errorf("one of 'text' or 'macro' must be specified")
+	}
+
+	var params map[string]ParamModel
+	if !m.Params.IsNull() {
+		if diags := m.Params.ElementsAs(context.Background(), &params, false); diags.HasError() {
+			return cedarpolicy.Condition{}, fmt.Errorf("params: %s", diags.Errors()[0].Detail())
+		}
+	}
+	values := make(map[string]cedarpolicy.Value, len(params))
+	for name, param := range params {
+		v, err := param.Value()
+		if err != nil {
+			return cedarpolicy.Condition{}, fmt.Errorf("params.%s: %w", name, err)
+		}
+		values[name] = v
+	}
+	var text string
+	var err error
+	if !m.Macro.IsNull() {
+		text, err = macros.Expand(m.Macro.ValueString(), values)
+	} else {
+		text, err = cedarpolicy.BindParams(m.Text.ValueString(), values)
+	}
+	if err != nil {
+		return cedarpolicy.Condition{}, err
+	}
+	return cedarpolicy.Condition{Text: text}, nil
+}
+
+// paramsKnown returns true if the condition's parameters are null or fully known.
+func (m ConditionModel) paramsKnown() bool {
+	return m.Params.IsNull() || isFullyKnown(context.Background(), m.Params)
+}
+
+// PolicyModel describes a Cedar policy, as configured in a 'policy' block of
+// the 'cedar_policyset' data source or the 'policy' attribute of the
+// 'cedar_policy' resource. It is converted to a cedarpolicy.Policy by Policy.
+type PolicyModel struct {
+	Effect      types.String      `tfsdk:"effect"`
+	Annotations []AnnotationModel `tfsdk:"annotation"`
+
+	AnyPrincipal types.Bool   `tfsdk:"any_principal"`
+	Principal    *EIDModel    `tfsdk:"principal"`
+	PrincipalIn  *EIDModel    `tfsdk:"principal_in"`
+	PrincipalIs  types.String `tfsdk:"principal_is"`
+
+	AnyAction types.Bool  `tfsdk:"any_action"`
+	Action    *EIDModel   `tfsdk:"action"`
+	ActionIn  *[]EIDModel `tfsdk:"action_in"`
+
+	AnyResource types.Bool   `tfsdk:"any_resource"`
+	Resource    *EIDModel    `tfsdk:"resource"`
+	ResourceIn  *EIDModel    `tfsdk:"resource_in"`
+	ResourceIs  types.String `tfsdk:"resource_is"`
+
+	When   []ConditionModel `tfsdk:"when"`
+	Unless []ConditionModel `tfsdk:"unless"`
+
+	NotBefore types.String `tfsdk:"not_before"`
+	NotAfter  types.String `tfsdk:"not_after"`
+}
+
+// newPolicyModel converts a policy into its Terraform model.
+// Unset fields of the policy are null in the model.
+func newPolicyModel(p cedarpolicy.Policy) PolicyModel {
+	optionalBool := func(v bool) types.Bool {
+		if !v {
+			return types.BoolNull()
+		}
+		return types.BoolValue(true)
+	}
+
+	m := PolicyModel{
+		Effect:       types.StringValue(p.Effect),
+		AnyPrincipal: optionalBool(p.AnyPrincipal),
+		Principal:    newEIDModel(p.Principal),
+		PrincipalIn:  newEIDModel(p.PrincipalIn),
+		PrincipalIs:  optionalString(p.PrincipalIs),
+		AnyAction:    optionalBool(p.AnyAction),
+		Action:       newEIDModel(p.Action),
+		AnyResource:  optionalBool(p.AnyResource),
+		Resource:     newEIDModel(p.Resource),
+		ResourceIn:   newEIDModel(p.ResourceIn),
+		ResourceIs:   optionalString(p.ResourceIs),
+		NotBefore:    types.StringNull(),
+		NotAfter:     types.StringNull(),
+	}
+	// 'is ... in ...' scopes are configured with both the '_is' and '_in' attributes.
+	if p.PrincipalIsIn != nil {
+		m.PrincipalIn = newEIDModel(p.PrincipalIsIn)
+	}
+	if p.ResourceIsIn != nil {
+		m.ResourceIn = newEIDModel(p.ResourceIsIn)
+	}
+	for _, anno := range p.Annotations {
+		m.Annotations = append(m.Annotations, AnnotationModel{
+			Name:  types.StringValue(anno.Name),
+			Value: types.StringValue(anno.Value),
+		})
+	}
+	if p.ActionIn != nil {
+		actions := make([]EIDModel, len(p.ActionIn))
+		for i := range p.ActionIn {
+			actions[i] = *newEIDModel(&p.ActionIn[i])
+		}
+		m.ActionIn = &actions
+	}
+	for _, c := range p.When {
+		m.When = append(m.When, ConditionModel{Text: types.StringValue(c.Text), Macro: types.StringNull(), Params: types.MapNull(paramType)})
+	}
+	for _, c := range p.Unless {
+		m.Unless = append(m.Unless, ConditionModel{Text: types.StringValue(c.Text), Macro: types.StringNull(), Params: types.MapNull(paramType)})
+	}
+	return m
+}
+
+// newDocumentPolicyModel converts a policy from a policy document into a model,
+// so that its time window is rendered in the same way as a 'policy' block.
+func newDocumentPolicyModel(p cedarpolicy.DocumentPolicy) PolicyModel {
+	m := newPolicyModel(p.Policy)
+	if !p.Validity.NotBefore.IsZero() {
+		m.NotBefore = types.StringValue(p.Validity.NotBefore.Format(time.RFC3339Nano))
+	}
+	if !p.Validity.NotAfter.IsZero() {
+		m.NotAfter = types.StringValue(p.Validity.NotAfter.Format(time.RFC3339Nano))
+	}
+	return m
+}
+
+// Policy converts the model into a policy, parsing any entity UID strings
+// such as 'CF::User::"alice"' and expanding any condition macros. It returns
+// an error wrapping errUnknown if any of the policy's values are unknown.
+func (m PolicyModel) Policy(macros cedarpolicy.Macros) (cedarpolicy.Policy, error) {
+	if fields := m.UnknownFields(); len(fields) > 0 {
+		return cedarpolicy.Policy{}, fmt.Errorf("%w: %s", errUnknown, strings.Join(fields, ", "))
+	}
+
+	p := cedarpolicy.Policy{
+		Effect:       m.Effect.ValueString(),
+		AnyPrincipal: m.AnyPrincipal.ValueBool(),
+		PrincipalIs:  m.PrincipalIs.ValueString(),
+		AnyAction:    m.AnyAction.ValueBool(),
+		AnyResource:  m.AnyResource.ValueBool(),
+		ResourceIs:   m.ResourceIs.ValueString(),
+	}
+
+	var err error
+	if p.Principal, err = resolveEID("principal", m.Principal); err != nil {
+		return cedarpolicy.Policy{}, err
+	}
+	if p.PrincipalIn, err = resolveEID("principal_in", m.PrincipalIn); err != nil {
+		return cedarpolicy.Policy{}, err
+	}
+	if p.Action, err = resolveEID("action", m.Action); err != nil {
+		return cedarpolicy.Policy{}, err
+	}
+	if m.ActionIn != nil {
+		p.ActionIn = make([]eid.EID, len(*m.ActionIn))
+		for i, e := range *m.ActionIn {
+			resolved, err := resolveEID(fmt.Sprintf("action_in[%d]", i), &e)
+			if err != nil {
+				return cedarpolicy.Policy{}, err
+			}
+			p.ActionIn[i] = *resolved
+		}
+	}
+	if p.Resource, err = resolveEID("resource", m.Resource); err != nil {
+		return cedarpolicy.Policy{}, err
+	}
+	if p.ResourceIn, err = resolveEID("resource_in", m.ResourceIn); err != nil {
+		return cedarpolicy.Policy{}, err
+	}
+	p.CombineIsIn()
+
+	for _, anno := range m.Annotations {
+		p.Annotations = append(p.Annotations, cedarpolicy.Annotation{
+			Name:  anno.Name.ValueString(),
+			Value: anno.Value.ValueString(),
+		})
+	}
+	for _, c := range []struct {
+		keyword    string
+		conditions []ConditionModel
+		dst        *[]cedarpolicy.Condition
+	}{{"when", m.When, &p.When}, {"unless", m.Unless, &p.Unless}} {
+		for i, model := range c.conditions {
+			cond, err := model.Condition(macros)
+			if err != nil {
+				return cedarpolicy.Policy{}, fmt.Errorf("%s[%d]: %w", c.keyword, i, err)
+			}
+			*c.dst = append(*c.dst, cond)
+		}
+	}
+	return p, nil
+}
+
+// Validity returns the time window in which the policy applies,
+// parsed from its 'not_before' and 'not_after' timestamps.
+func (m PolicyModel) Validity() (cedarpolicy.Validity, error) {
+	var v cedarpolicy.Validity
+	for _, bound := range []struct {
+		name  string
+		value types.String
+		dst   *time.Time
+	}{
+		{"not_before", m.NotBefore, &v.NotBefore},
+		{"not_after", m.NotAfter, &v.NotAfter},
+	} {
+		if bound.value.IsNull() || bound.value.IsUnknown() {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.value.ValueString())
+		if err != nil {
+			return cedarpolicy.Validity{}, fmt.Errorf("%s: %q is not an RFC3339 timestamp, such as '2024-10-15T09:00:00Z'", bound.name, bound.value.ValueString())
+		}
+		*bound.dst = t
+	}
+	if err := v.Check(); err != nil {
+		return cedarpolicy.Validity{}, err
+	}
+	return v, nil
+}
+
+// IsKnown reports whether all of the values in the policy are known.
+// Null values are known.
+func (m PolicyModel) IsKnown() bool {
+	return len(m.UnknownFields()) == 0
+}
+
+// UnknownFields returns the names of the fields of the policy whose values are
+// not yet known, such as 'principal.id' or 'when[0]'.
+func (m PolicyModel) UnknownFields() []string {
+	var fields []string
+	str := func(name string, v types.String) {
+		if v.IsUnknown() {
+			fields = append(fields, name)
+		}
+	}
+	boolean := func(name string, v types.Bool) {
+		if v.IsUnknown() {
+			fields = append(fields, name)
+		}
+	}
+	entity := func(name string, e *EIDModel) {
+		if e == nil {
+			return
+		}
+		str(name+".type", e.Type)
+		str(name+".id", e.ID)
+		str(name+".uid", e.UID)
+	}
+
+	str("effect", m.Effect)
+	for i, anno := range m.Annotations {
+		str(fmt.Sprintf("annotation[%d].name", i), anno.Name)
+		str(fmt.Sprintf("annotation[%d].value", i), anno.Value)
+	}
+
+	boolean("any_principal", m.AnyPrincipal)
+	entity("principal", m.Principal)
+	entity("principal_in", m.PrincipalIn)
+	str("principal_is", m.PrincipalIs)
+
+	boolean("any_action", m.AnyAction)
+	entity("action", m.Action)
+	if m.ActionIn != nil {
+		for i, e := range *m.ActionIn {
+			entity(fmt.Sprintf("action_in[%d]", i), &e)
+		}
+	}
+
+	boolean("any_resource", m.AnyResource)
+	entity("resource", m.Resource)
+	entity("resource_in", m.ResourceIn)
+	str("resource_is", m.ResourceIs)
+
+	for _, c := range []struct {
+		keyword    string
+		conditions []ConditionModel
+	}{{"when", m.When}, {"unless", m.Unless}} {
+		for i, cond := range c.conditions {
+			str(fmt.Sprintf("%s[%d]", c.keyword, i), cond.Text)
+			str(fmt.Sprintf("%s[%d].macro", c.keyword, i), cond.Macro)
+			if !cond.paramsKnown() {
+				fields = append(fields, fmt.Sprintf("%s[%d].params", c.keyword, i))
+			}
+		}
+	}
+
+	str("not_before", m.NotBefore)
+	str("not_after", m.NotAfter)
+
+	return fields
+}
+
+// withoutUnknowns returns a copy of the model with its unknown values removed,
+// so that the known parts of the policy can be checked. Scope constraints on an
+// entity whose type is unknown are replaced by an unconstrained scope, and
+// conditions and annotations with unknown values are left out. Entities with a
+// known type and an unknown ID are kept with an empty ID, as only their type
+// is checked. Unknown time bounds are left out.
+func (m PolicyModel) withoutUnknowns() PolicyModel {
+	entityKnown := func(e *EIDModel) bool {
+		return e == nil || !(e.Type.IsUnknown() || e.UID.IsUnknown())
+	}
+	knownID := func(e *EIDModel) *EIDModel {
+		if e == nil || !e.ID.IsUnknown() {
+			return e
+		}
+		return &EIDModel{Type: e.Type, ID: types.StringValue(""), UID: e.UID}
+	}
+
+	if m.Effect.IsUnknown() {
+		m.Effect = types.StringNull()
+	}
+	var annotations []AnnotationModel
+	for _, anno := range m.Annotations {
+		if !anno.Name.IsUnknown() && !anno.Value.IsUnknown() {
+			annotations = append(annotations, anno)
+		}
+	}
+	m.Annotations = annotations
+
+	if !entityKnown(m.Principal) || !entityKnown(m.PrincipalIn) || m.PrincipalIs.IsUnknown() || m.AnyPrincipal.IsUnknown() {
+		m.Principal, m.PrincipalIn, m.PrincipalIs = nil, nil, types.StringNull()
+		m.AnyPrincipal = types.BoolValue(true)
+	}
+	m.Principal, m.PrincipalIn = knownID(m.Principal), knownID(m.PrincipalIn)
+
+	// the principal and resource types are checked against the policy's
+	// actions, so a partially known action list can't be checked.
+	actionsKnown := entityKnown(m.Action)
+	if m.ActionIn != nil {
+		for _, e := range *m.ActionIn {
+			actionsKnown = actionsKnown && entityKnown(&e)
+		}
+	}
+	if !actionsKnown || m.AnyAction.IsUnknown() {
+		m.Action, m.ActionIn = nil, nil
+		m.AnyAction = types.BoolValue(true)
+	}
+	m.Action = knownID(m.Action)
+	if m.ActionIn != nil {
+		actions := make([]EIDModel, len(*m.ActionIn))
+		for i := range *m.ActionIn {
+			actions[i] = *knownID(&(*m.ActionIn)[i])
+		}
+		m.ActionIn = &actions
+	}
+
+	if !entityKnown(m.Resource) || !entityKnown(m.ResourceIn) || m.ResourceIs.IsUnknown() || m.AnyResource.IsUnknown() {
+		m.Resource, m.ResourceIn, m.ResourceIs = nil, nil, types.StringNull()
+		m.AnyResource = types.BoolValue(true)
+	}
+	m.Resource, m.ResourceIn = knownID(m.Resource), knownID(m.ResourceIn)
+
+	m.When = knownConditions(m.When)
+	m.Unless = knownConditions(m.Unless)
+
+	if m.NotBefore.IsUnknown() {
+		m.NotBefore = types.StringNull()
+	}
+	if m.NotAfter.IsUnknown() {
+		m.NotAfter = types.StringNull()
+	}
+	return m
+}
+
+func knownConditions(conditions []ConditionModel) []ConditionModel {
+	var known []ConditionModel
+	for _, c := range conditions {
+		if !c.Text.IsUnknown() && !c.Macro.IsUnknown() && c.paramsKnown() {
+			known = append(known, c)
+		}
+	}
+	return known
+}
@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEIDModel(t *testing.T) {
+	got, err := EIDModel{UID: types.StringValue(`CF::User::"alice"`)}.EID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, eid.New("CF::User", "alice"), got)
+
+	_, err = EIDModel{Type: types.StringValue("CF::User"), UID: types.StringValue(`CF::User::"alice"`)}.EID()
+	assert.EqualError(t, err, `only one of 'uid' or 'type' and 'id' may be specified, got uid "CF::User::\"alice\""`)
+}
+
+func TestPolicyModel_RoundTrip(t *testing.T) {
+	policy, err := cedarpolicy.ParsePolicy(`@id("admins")
+permit (principal in Group::"admins", action in [Action::"Read", Action::"Write"], resource is Document)
+when { resource.public };`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := newPolicyModel(policy).Policy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, policy, got)
+}
+
+func TestPolicyModel_Validity(t *testing.T) {
+	model := PolicyModel{
+		NotBefore: types.StringValue("2024-10-15T09:00:00+01:00"),
+		NotAfter:  types.StringNull(),
+	}
+	got, err := model.Validity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []cedarpolicy.Condition{{Text: `context.now >= datetime("2024-10-15T08:00:00Z")`}}, got.Conditions(cedarpolicy.DefaultTimeAttribute))
+
+	model.NotAfter = types.StringValue("2024-10-15")
+	_, err = model.Validity()
+	assert.EqualError(t, err, `not_after: "2024-10-15" is not an RFC3339 timestamp, such as '2024-10-15T09:00:00Z'`)
+
+	model.NotAfter = types.StringValue("2024-10-15T07:00:00Z")
+	_, err = model.Validity()
+	assert.EqualError(t, err, "not_after 2024-10-15T07:00:00Z is before not_before 2024-10-15T09:00:00+01:00")
+}
+
+func TestConditionModel(t *testing.T) {
+	ctx := context.Background()
+	params, diags := types.MapValueFrom(ctx, paramType, map[string]ParamModel{
+		"owner": {Entity: &EIDModel{Type: types.StringValue("User"), ID: types.StringValue(`alice"`), UID: types.StringNull()}},
+		"net":   {IP: types.StringValue("10.0.0.0/8")},
+		"groups": {Set: &[]ParamValueModel{
+			{Entity: &EIDModel{UID: types.StringValue(`Group::"eng"`)}},
+			{String: types.StringValue("ops")},
+		}},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	got, err := ConditionModel{
+		Text:   types.StringValue(`resource.owner == $owner && context.ip.isInRange($net) && principal.groups.containsAny($groups)`),
+		Params: params,
+	}.Condition(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `resource.owner == User::"alice\"" && context.ip.isInRange(ip("10.0.0.0/8")) && principal.groups.containsAny([Group::"eng", "ops"])`, got.Text)
+
+	_, err = ConditionModel{Text: types.StringValue(`resource.owner == $owner`), Params: types.MapNull(paramType)}.Condition(nil)
+	assert.EqualError(t, err, "placeholder '$owner' has no parameter")
+
+	params, diags = types.MapValueFrom(ctx, paramType, map[string]ParamModel{
+		"level": {Long: types.Int64Value(1), String: types.StringValue("1")},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	_, err = ConditionModel{Text: types.StringValue(`context.level > $level`), Params: params}.Condition(nil)
+	assert.EqualError(t, err, "params.level: exactly one of 'entity', 'string', 'long', 'bool', 'set', 'ip', 'decimal' or 'datetime' must be specified")
+
+	macros := cedarpolicy.Macros{
+		"member_of": {Name: "member_of", Text: `principal in $group`, Params: []string{"group"}},
+	}
+	params, diags = types.MapValueFrom(ctx, paramType, map[string]ParamModel{
+		"group": {Entity: &EIDModel{Type: types.StringValue("Group"), ID: types.StringValue("admins"), UID: types.StringNull()}},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	got, err = ConditionModel{Macro: types.StringValue("member_of"), Params: params}.Condition(macros)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `principal in Group::"admins"`, got.Text)
+
+	_, err = ConditionModel{Macro: types.StringValue("mfa_required"), Params: types.MapNull(paramType)}.Condition(macros)
+	assert.EqualError(t, err, `macro "mfa_required" is not defined`)
+
+	_, err = ConditionModel{Text: types.StringValue("context.mfa"), Macro: types.StringValue("member_of"), Params: params}.Condition(macros)
+	assert.EqualError(t, err, "only one of 'text' or 'macro' may be specified")
+
+	// conditions with unknown parameters are not known.
+	model := PolicyModel{
+		Effect: types.StringValue("permit"),
+		When:   []ConditionModel{{Text: types.StringValue("context.level > $level"), Params: types.MapUnknown(paramType)}},
+	}
+	assert.Equal(t, []string{"when[0].params"}, model.UnknownFields())
+	assert.Empty(t, model.withoutUnknowns().When)
+}
+
+func TestPolicyModel_UnknownFields(t *testing.T) {
+	model := PolicyModel{
+		Effect:    types.StringValue("permit"),
+		Principal: &EIDModel{Type: types.StringValue("CF::User"), ID: types.StringUnknown()},
+		ActionIn: &[]EIDModel{
+			{Type: types.StringValue("CF::Action"), ID: types.StringValue("Read")},
+			{UID: types.StringUnknown()},
+		},
+		AnyResource: types.BoolValue(true),
+		When:        []ConditionModel{{Text: types.StringValue("true")}, {Text: types.StringUnknown()}},
+	}
+
+	assert.False(t, model.IsKnown())
+	assert.Equal(t, []string{"principal.id", "action_in[1].uid", "when[1]"}, model.UnknownFields())
+
+	_, err := model.Policy(nil)
+	assert.True(t, errors.Is(err, errUnknown))
+	assert.EqualError(t, err, "policy contains values which are not yet known: principal.id, action_in[1].uid, when[1]")
+
+	// null values are known.
+	model = PolicyModel{
+		Effect:       types.StringValue("permit"),
+		AnyPrincipal: types.BoolValue(true),
+		PrincipalIs:  types.StringNull(),
+		AnyAction:    types.BoolNull(),
+		Action:       &EIDModel{Type: types.StringValue("Action"), ID: types.StringValue("Read"), UID: types.StringNull()},
+		AnyResource:  types.BoolValue(true),
+	}
+	assert.True(t, model.IsKnown())
+}
+
+func TestPolicyModel_WithoutUnknowns(t *testing.T) {
+	schema, err := cedarpolicy.ParseSchema([]byte(`{
+		"CF": {
+			"entityTypes": {"User": {}, "Document": {}},
+			"actions": {"Read": {"appliesTo": {"principalTypes": ["User"], "resourceTypes": ["Document"]}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		model PolicyModel
+		want  []string
+	}{
+		{
+			name: "unknown_id",
+			model: PolicyModel{
+				Effect:      types.StringValue("permit"),
+				Principal:   &EIDModel{Type: types.StringValue("CF::Usr"), ID: types.StringUnknown()},
+				Action:      &EIDModel{Type: types.StringValue("CF::Action"), ID: types.StringValue("Read")},
+				AnyResource: types.BoolValue(true),
+			},
+			want: []string{`principal: unknown entity type "CF::Usr"`},
+		},
+		{
+			name: "unknown_type",
+			model: PolicyModel{
+				Effect:     types.StringValue("permit"),
+				Principal:  &EIDModel{Type: types.StringUnknown(), ID: types.StringValue("alice")},
+				Action:     &EIDModel{Type: types.StringValue("CF::Action"), ID: types.StringValue("Read")},
+				ResourceIs: types.StringValue("CF::User"),
+			},
+			want: []string{`resource: entity type "CF::User" is not a resource type for the policy's actions, expected one of ["CF::Document"]`},
+		},
+		{
+			name: "unknown_action",
+			model: PolicyModel{
+				Effect:      types.StringValue("permit"),
+				PrincipalIs: types.StringValue("CF::Document"),
+				ActionIn: &[]EIDModel{
+					{Type: types.StringValue("CF::Action"), ID: types.StringValue("Read")},
+					{UID: types.StringUnknown()},
+				},
+				ResourceIs: types.StringValue("CF::Document"),
+			},
+		},
+		{
+			name: "unknown_condition",
+			model: PolicyModel{
+				Effect:       types.StringValue("permit"),
+				AnyPrincipal: types.BoolValue(true),
+				AnyAction:    types.BoolValue(true),
+				AnyResource:  types.BoolValue(true),
+				When:         []ConditionModel{{Text: types.StringUnknown()}, {Text: types.StringValue(`principal is CF::Grp`)}},
+			},
+			want: []string{`when condition index 0: unknown entity type "CF::Grp"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := tt.model.withoutUnknowns().Policy(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, err := range schema.ValidatePolicy(policy) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,714 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+	"github.com/common-fate/terraform-provider-cedar/pkg/policystore"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var _ resource.Resource = &PolicyResource{}
+var _ resource.ResourceWithImportState = &PolicyResource{}
+var _ resource.ResourceWithValidateConfig = &PolicyResource{}
+var _ resource.ResourceWithModifyPlan = &PolicyResource{}
+var _ resource.ResourceWithConfigure = &PolicyResource{}
+
+type PolicyResource struct {
+	provider *ProviderData
+}
+
+func NewPolicyResource() resource.Resource {
+	return &PolicyResource{}
+}
+
+type PolicyResourceModel struct {
+	ID             types.String         `tfsdk:"id"`
+	PolicyStoreID  types.String         `tfsdk:"policy_store_id"`
+	PolicyID       types.String         `tfsdk:"policy_id"`
+	Description    types.String         `tfsdk:"description"`
+	Statement      PolicyStatement      `tfsdk:"statement"`
+	Policy         types.Object         `tfsdk:"policy"`
+	TemplateLinked *TemplateLinkedModel `tfsdk:"template_linked"`
+	Version        types.Int64          `tfsdk:"version"`
+}
+
+// TemplateLinkedModel describes a policy created from a policy template.
+type TemplateLinkedModel struct {
+	TemplateID types.String `tfsdk:"template_id"`
+	Principal  *EIDModel    `tfsdk:"principal"`
+	Resource   *EIDModel    `tfsdk:"resource"`
+}
+
+func (r *PolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policy"
+}
+
+func (r *PolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A Cedar policy in a 'cedar_policy_store'.",
+		MarkdownDescription: `A Cedar policy in a 'cedar_policy_store'.
+
+A policy is either a static policy, given by its Cedar 'statement' or as a structured 'policy', or a template-linked policy, created from a 'cedar_policy_template' by filling its '?principal' and '?resource' slots. Template-linked policies are updated when their template changes.
+
+The 'statement' and 'policy' attributes of a static policy are kept in sync: whichever is configured, the other is computed from it. Statements which differ only in formatting, such as whitespace, line breaks and comments, are treated as the same policy, so reformatting a policy doesn't change its 'version'.
+
+If the store has a schema, the policy is validated against it when it is written. Every change to the policy is recorded as a new version in the store.
+
+Policies can be imported using the policy store ID and the policy ID, separated by a comma, such as 'terraform import cedar_policy.example ./store,admins'. They can also be imported using the policy store ID and the Cedar text of the policy, separated by '//', such as an import ID of './store//permit (principal in CF::Group::"admins", action, resource);'. The text starts after the first '//', so the policy store ID must not contain '//' when importing by Cedar text. A policy given by its Cedar text is found by comparing it with the statements in the store, ignoring formatting. The Cedar text of the existing policy is parsed to fill in both 'statement' and 'policy', so an existing policy can be adopted with either attribute. The entities in 'policy' are given both as 'type' and 'id' and as 'uid', so either form can be used in the configuration.
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The policy store ID and the policy ID, separated by a comma.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"policy_store_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the 'cedar_policy_store' to add the policy to.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"policy_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the policy in the store. May only contain letters, digits, '-' and '_'. A random ID is generated if not provided.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "A description of the policy.",
+				Optional:            true,
+			},
+			"statement": schema.StringAttribute{
+				MarkdownDescription: "The Cedar text of a static policy. Exactly one of 'statement', 'policy' or 'template_linked' must be provided. Computed from 'policy' if not provided.",
+				CustomType:          PolicyStatementType{},
+				Optional:            true,
+				Computed:            true,
+			},
+			"policy": schema.SingleNestedAttribute{
+				MarkdownDescription: "A static policy given as structured attributes, in the same format as the 'policy' blocks of the 'cedar_policyset' data source. Exactly one of 'statement', 'policy' or 'template_linked' must be provided. Computed from 'statement' if not provided.",
+				Optional:            true,
+				Computed:            true,
+				Attributes:          policyAttributes,
+			},
+			"template_linked": schema.SingleNestedAttribute{
+				MarkdownDescription: "Creates the policy from a policy template. Exactly one of 'statement', 'policy' or 'template_linked' must be provided.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"template_id": schema.StringAttribute{
+						MarkdownDescription: "The ID of the template in the policy store.",
+						Required:            true,
+					},
+					"principal": schema.SingleNestedAttribute{
+						MarkdownDescription: "The entity for the template's '?principal' slot. Required if the template has a '?principal' slot.",
+						Optional:            true,
+						Attributes:          eidAttributes,
+					},
+					"resource": schema.SingleNestedAttribute{
+						MarkdownDescription: "The entity for the template's '?resource' slot. Required if the template has a '?resource' slot.",
+						Optional:            true,
+						Attributes:          eidAttributes,
+					},
+				},
+			},
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "The current version of the policy in the store.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *PolicyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.provider = providerData
+}
+
+func (r *PolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data PolicyResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Statement.IsUnknown() || data.Policy.IsUnknown() {
+		return
+	}
+
+	var count int
+	if !data.Statement.IsNull() {
+		count++
+	}
+	if !data.Policy.IsNull() {
+		count++
+	}
+	if data.TemplateLinked != nil {
+		count++
+	}
+
+	if count != 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("statement"),
+			"Invalid Cedar Policy",
+			"Exactly one of 'statement', 'policy' or 'template_linked' must be provided.",
+		)
+	}
+}
+
+func (r *PolicyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to do when the resource is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan PolicyResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state *PolicyResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &PolicyResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	switch {
+	case config.TemplateLinked != nil:
+		plan.Statement = PolicyStatement{StringValue: types.StringNull()}
+		plan.Policy = types.ObjectNull(policyAttrTypes)
+
+	case !config.Statement.IsNull() && !config.Statement.IsUnknown():
+		// formatting-only changes to the statement keep the structured
+		// policy which is in the state.
+		if state != nil && !state.Policy.IsNull() && cedarpolicy.Equivalent(state.Statement.ValueString(), config.Statement.ValueString()) {
+			plan.Policy = state.Policy
+			break
+		}
+		policy, err := r.provider.cedarVersion().ParsePolicy(config.Statement.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("statement"), "Invalid Cedar Policy", err.Error())
+			return
+		}
+		obj, diags := policyObject(ctx, policy)
+		resp.Diagnostics.Append(diags...)
+		plan.Policy = obj
+
+	case !config.Policy.IsNull() && isFullyKnown(ctx, config.Policy):
+		var policy PolicyModel
+		resp.Diagnostics.Append(config.Policy.As(ctx, &policy, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		statement, err := renderPolicy(policy, r.provider)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("policy"), "Invalid Cedar Policy", err.Error())
+			return
+		}
+		obj, diags := policyModelObject(ctx, policy)
+		resp.Diagnostics.Append(diags...)
+		plan.Policy = obj
+		// the statement in the state is kept if it only differs in formatting,
+		// such as after the policy was imported.
+		if state != nil && cedarpolicy.Equivalent(state.Statement.ValueString(), statement) {
+			plan.Statement = state.Statement
+		} else {
+			plan.Statement = NewPolicyStatementValue(statement)
+		}
+	}
+
+	// the version is unchanged if the policy in the store won't change,
+	// as formatting-only changes are not recorded as a new version.
+	if state != nil && !plan.Statement.IsUnknown() &&
+		cedarpolicy.Equivalent(state.Statement.ValueString(), plan.Statement.ValueString()) &&
+		state.Description.Equal(plan.Description) &&
+		templateLinkedEqual(state.TemplateLinked, plan.TemplateLinked) {
+		plan.Version = state.Version
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *PolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PolicyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.PolicyID.IsUnknown() || data.PolicyID.IsNull() {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Create resource: Cedar Policy", err.Error())
+			return
+		}
+		data.PolicyID = types.StringValue(id)
+	}
+
+	if err := r.put(&data); err != nil {
+		resp.Diagnostics.AddError("Unable to Create resource: Cedar Policy", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PolicyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	store, err := policystore.Open(data.PolicyStoreID.ValueString()).Read()
+	if errors.Is(err, policystore.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read resource: Cedar Policy", err.Error())
+		return
+	}
+
+	// entries whose file has been removed from a directory store are
+	// recreated, keeping their version history.
+	entry, ok := store.Policies[data.PolicyID.ValueString()]
+	if !ok || entry.Missing {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.ID = types.StringValue(storeEntryID(data.PolicyStoreID.ValueString(), entry.ID))
+	data.Description = optionalString(entry.Description)
+	data.Version = types.Int64Value(int64(entry.Version()))
+
+	if entry.Link == nil {
+		// the structured policy is only read from the store when importing
+		// or if the policy has changed, so that the configured attributes
+		// are kept when the statement only differs in formatting.
+		if data.Policy.IsNull() || data.Policy.IsUnknown() || !cedarpolicy.Equivalent(data.Statement.ValueString(), entry.Statement) {
+			data.Policy = types.ObjectNull(policyAttrTypes)
+			if policy, err := cedarpolicy.ParsePolicy(entry.Statement); err == nil {
+				obj, diags := policyObject(ctx, policy)
+				resp.Diagnostics.Append(diags...)
+				data.Policy = obj
+			}
+		}
+		data.Statement = NewPolicyStatementValue(entry.Statement)
+		data.TemplateLinked = nil
+	} else {
+		data.Statement = PolicyStatement{StringValue: types.StringNull()}
+		data.Policy = types.ObjectNull(policyAttrTypes)
+		if data.TemplateLinked == nil {
+			data.TemplateLinked = &TemplateLinkedModel{}
+		}
+		data.TemplateLinked.TemplateID = types.StringValue(entry.Link.TemplateID)
+
+		// the slot entities are only read from the store when importing, so
+		// that entities configured using 'uid' or 'type' and 'id' are kept.
+		if data.TemplateLinked.Principal == nil && entry.Link.Principal != "" {
+			principal, err := eid.Parse(entry.Link.Principal)
+			if err == nil {
+				data.TemplateLinked.Principal = newEIDModel(&principal)
+			}
+		}
+		if data.TemplateLinked.Resource == nil && entry.Link.Resource != "" {
+			resource, err := eid.Parse(entry.Link.Resource)
+			if err == nil {
+				data.TemplateLinked.Resource = newEIDModel(&resource)
+			}
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PolicyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.put(&data); err != nil {
+		resp.Diagnostics.AddError("Unable to Update resource: Cedar Policy", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PolicyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := policystore.Open(data.PolicyStoreID.ValueString()).Update(func(store *policystore.Data) error {
+		return store.DeletePolicy(data.PolicyID.ValueString())
+	})
+	if err != nil && !errors.Is(err, policystore.ErrNotFound) {
+		resp.Diagnostics.AddError("Unable to Delete resource: Cedar Policy", err.Error())
+	}
+}
+
+func (r *PolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	storeID, policyID, statement, err := parsePolicyImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Import resource: Cedar Policy", err.Error())
+		return
+	}
+
+	// policies given by their Cedar text are found by comparing it with
+	// the statements of the policies in the store.
+	if statement != "" {
+		store, err := policystore.Open(storeID).Read()
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Import resource: Cedar Policy", err.Error())
+			return
+		}
+		entry, err := store.FindPolicy(statement)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Import resource: Cedar Policy", err.Error())
+			return
+		}
+		policyID = entry.ID
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), storeEntryID(storeID, policyID))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("policy_store_id"), storeID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("policy_id"), policyID)...)
+}
+
+// parsePolicyImportID splits the import ID of a policy into the policy store
+// ID and either the policy ID or the Cedar text of the policy. The Cedar text
+// follows the first '//', as it may contain commas, and store IDs may too.
+// Policy IDs can't contain commas, so they follow the last comma.
+func parsePolicyImportID(id string) (storeID, policyID, statement string, err error) {
+	if storeID, statement, ok := strings.Cut(id, "//"); ok {
+		if storeID == "" || strings.TrimSpace(statement) == "" {
+			return "", "", "", fmt.Errorf("expected an ID in the format '<policy_store_id>//<cedar policy>', got %q", id)
+		}
+		return storeID, "", statement, nil
+	}
+	storeID, policyID, err = parseStoreEntryID(id)
+	if err != nil {
+		return "", "", "", fmt.Errorf("%w, or '<policy_store_id>//<cedar policy>'", err)
+	}
+	return storeID, policyID, "", nil
+}
+
+// renderPolicy renders a structured policy as Cedar text, expanding the
+// provider's condition macros. Its time bounds are rendered as conditions
+// on the provider's time attribute, and the policy is checked against the
+// provider's Cedar version. It is safe to call with a nil ProviderData.
+func renderPolicy(model PolicyModel, provider *ProviderData) (string, error) {
+	policy, err := model.Policy(provider.macros())
+	if err != nil {
+		return "", err
+	}
+	validity, err := model.Validity()
+	if err != nil {
+		return "", err
+	}
+	policy = policy.WithValidity(provider.timeAttribute(), validity)
+	if err := provider.cedarVersion().CheckPolicy(policy); err != nil {
+		return "", err
+	}
+	return policy.Format(cedarpolicy.DefaultFormatOptions())
+}
+
+// policyObject converts a policy into the value of the 'policy' attribute.
+func policyObject(ctx context.Context, policy cedarpolicy.Policy) (types.Object, diag.Diagnostics) {
+	return policyModelObject(ctx, newPolicyModel(policy))
+}
+
+// policyModelObject converts a structured policy into the value of the 'policy'
+// attribute. Its entities are given both as a 'type' and 'id' and as a 'uid',
+// so that the attribute is the same whichever way the entities were configured,
+// and when the policy is read from its statement.
+func policyModelObject(ctx context.Context, model PolicyModel) (types.Object, diag.Diagnostics) {
+	for _, e := range []*EIDModel{model.Principal, model.PrincipalIn, model.Action, model.Resource, model.ResourceIn} {
+		completeEID(e)
+	}
+	if model.ActionIn != nil {
+		for i := range *model.ActionIn {
+			completeEID(&(*model.ActionIn)[i])
+		}
+	}
+	return types.ObjectValueFrom(ctx, policyAttrTypes, model)
+}
+
+// completeEID sets the 'type', 'id' and 'uid' of the entity. It is left
+// unchanged if it isn't valid.
+func completeEID(e *EIDModel) {
+	if e == nil {
+		return
+	}
+	resolved, err := e.EID()
+	if err != nil {
+		return
+	}
+	*e = EIDModel{
+		Type: types.StringValue(resolved.Type),
+		ID:   types.StringValue(resolved.ID),
+		UID:  types.StringValue(resolved.String()),
+	}
+}
+
+// isFullyKnown returns true if the value and all of its nested values are known.
+func isFullyKnown(ctx context.Context, v attr.Value) bool {
+	tfValue, err := v.ToTerraformValue(ctx)
+	return err == nil && tfValue.IsFullyKnown()
+}
+
+// templateLinkedEqual returns true if both template links are the same.
+func templateLinkedEqual(a, b *TemplateLinkedModel) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.TemplateID.Equal(b.TemplateID) && eidEqual(a.Principal, b.Principal) && eidEqual(a.Resource, b.Resource)
+}
+
+func eidEqual(a, b *EIDModel) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Type.Equal(b.Type) && a.ID.Equal(b.ID) && a.UID.Equal(b.UID)
+}
+
+// put writes the policy to the store, and updates the ID and version.
+func (r *PolicyResource) put(data *PolicyResourceModel) error {
+	var entry *policystore.Entry
+
+	err := policystore.Open(data.PolicyStoreID.ValueString()).Update(func(store *policystore.Data) error {
+		var err error
+		if data.TemplateLinked != nil {
+			principal, err := resolveEID("principal", data.TemplateLinked.Principal)
+			if err != nil {
+				return err
+			}
+			resource, err := resolveEID("resource", data.TemplateLinked.Resource)
+			if err != nil {
+				return err
+			}
+			entry, err = store.PutTemplateLinkedPolicy(
+				data.PolicyID.ValueString(),
+				data.Description.ValueString(),
+				data.TemplateLinked.TemplateID.ValueString(),
+				principal,
+				resource,
+			)
+		} else {
+			entry, err = store.PutPolicy(data.PolicyID.ValueString(), data.Description.ValueString(), data.Statement.ValueString())
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	data.ID = types.StringValue(storeEntryID(data.PolicyStoreID.ValueString(), entry.ID))
+	data.Version = types.Int64Value(int64(entry.Version()))
+	return nil
+}
+
+// policyEIDAttributes are the attributes of an entity in the scope of a
+// structured policy. Whichever of 'uid' or 'type' and 'id' is configured,
+// the others are computed from it.
+var policyEIDAttributes = map[string]schema.Attribute{
+	"type": schema.StringAttribute{
+		Optional:            true,
+		Computed:            true,
+		MarkdownDescription: "The entity type. Required unless 'uid' is specified, in which case it is computed from 'uid'.",
+	},
+	"id": schema.StringAttribute{
+		Optional:            true,
+		Computed:            true,
+		MarkdownDescription: "The entity ID. Required unless 'uid' is specified, in which case it is computed from 'uid'.",
+	},
+	"uid": schema.StringAttribute{
+		Optional:            true,
+		Computed:            true,
+		MarkdownDescription: "The entity UID as a Cedar string, such as 'CF::User::\"alice\"'. May be specified instead of 'type' and 'id', and is otherwise computed from them.",
+	},
+}
+
+// policyAttributes are the attributes of a structured Cedar policy,
+// matching the fields of PolicyModel.
+var policyAttributes = map[string]schema.Attribute{
+	"effect": schema.StringAttribute{
+		MarkdownDescription: "Must be either 'permit' or 'forbid'.",
+		Required:            true,
+	},
+	"annotation": schema.ListNestedAttribute{
+		MarkdownDescription: "Additional application-specific metadata attached to the policy.",
+		Optional:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					MarkdownDescription: "The name of the annotation.",
+					Required:            true,
+				},
+				"value": schema.StringAttribute{
+					MarkdownDescription: "The value of the annotation.",
+					Required:            true,
+				},
+			},
+		},
+	},
+	"any_principal": schema.BoolAttribute{
+		MarkdownDescription: "Matches all principals. Equivalent to writing 'principal'.",
+		Optional:            true,
+	},
+	"principal": schema.SingleNestedAttribute{
+		MarkdownDescription: "Equivalent to writing 'principal =='.",
+		Optional:            true,
+		Attributes:          policyEIDAttributes,
+	},
+	"principal_in": schema.SingleNestedAttribute{
+		MarkdownDescription: "Equivalent to writing 'principal in'.",
+		Optional:            true,
+		Attributes:          policyEIDAttributes,
+	},
+	"principal_is": schema.StringAttribute{
+		MarkdownDescription: "Equivalent to writing 'principal is'. Set together with 'principal_in' for 'principal is <type> in <entity>'.",
+		Optional:            true,
+	},
+	"any_action": schema.BoolAttribute{
+		MarkdownDescription: "Matches all actions. Equivalent to writing 'action'.",
+		Optional:            true,
+	},
+	"action": schema.SingleNestedAttribute{
+		MarkdownDescription: "Equivalent to writing 'action =='.",
+		Optional:            true,
+		Attributes:          policyEIDAttributes,
+	},
+	"action_in": schema.ListNestedAttribute{
+		MarkdownDescription: "Equivalent to writing 'action in'.",
+		Optional:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: policyEIDAttributes,
+		},
+	},
+	"any_resource": schema.BoolAttribute{
+		MarkdownDescription: "Matches all resources. Equivalent to writing 'resource'.",
+		Optional:            true,
+	},
+	"resource": schema.SingleNestedAttribute{
+		MarkdownDescription: "Equivalent to writing 'resource =='.",
+		Optional:            true,
+		Attributes:          policyEIDAttributes,
+	},
+	"resource_in": schema.SingleNestedAttribute{
+		MarkdownDescription: "Equivalent to writing 'resource in'.",
+		Optional:            true,
+		Attributes:          policyEIDAttributes,
+	},
+	"resource_is": schema.StringAttribute{
+		MarkdownDescription: "Equivalent to writing 'resource is'. Set together with 'resource_in' for 'resource is <type> in <entity>'.",
+		Optional:            true,
+	},
+	"when": schema.ListNestedAttribute{
+		MarkdownDescription: "Conditions which must evaluate to true for the policy to apply.",
+		Optional:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"text": schema.StringAttribute{
+					MarkdownDescription: "The condition as a Cedar expression, for example 'resource.is_public'. Required unless 'macro' is specified.",
+					Optional:            true,
+				},
+				"macro": schema.StringAttribute{
+					MarkdownDescription: macroDescription,
+					Optional:            true,
+				},
+				"params": schema.MapNestedAttribute{
+					MarkdownDescription: paramsDescription,
+					Optional:            true,
+					NestedObject:        schema.NestedAttributeObject{Attributes: paramAttributes(true)},
+				},
+			},
+		},
+	},
+	"unless": schema.ListNestedAttribute{
+		MarkdownDescription: "Conditions which must evaluate to false for the policy to apply.",
+		Optional:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"text": schema.StringAttribute{
+					MarkdownDescription: "The condition as a Cedar expression, for example 'resource.is_private'. Required unless 'macro' is specified.",
+					Optional:            true,
+				},
+				"macro": schema.StringAttribute{
+					MarkdownDescription: macroDescription,
+					Optional:            true,
+				},
+				"params": schema.MapNestedAttribute{
+					MarkdownDescription: paramsDescription,
+					Optional:            true,
+					NestedObject:        schema.NestedAttributeObject{Attributes: paramAttributes(true)},
+				},
+			},
+		},
+	},
+	"not_before": schema.StringAttribute{
+		MarkdownDescription: "An RFC3339 timestamp before which the policy does not apply. Rendered as a 'when' condition comparing the provider's 'time_attribute' with a Cedar 'datetime'. Not set when the policy is read from a 'statement'.",
+		Optional:            true,
+	},
+	"not_after": schema.StringAttribute{
+		MarkdownDescription: "An RFC3339 timestamp after which the policy does not apply. Rendered as a 'when' condition comparing the provider's 'time_attribute' with a Cedar 'datetime'. Not set when the policy is read from a 'statement'.",
+		Optional:            true,
+	},
+}
+
+// policyAttrTypes are the types of the 'policy' attribute.
+var policyAttrTypes = schema.SingleNestedAttribute{Attributes: policyAttributes}.GetType().(types.ObjectType).AttrTypes
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestPolicyResource_Structured(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "cedar_policy_store" "test" {
+					path = %q
+				}
+
+				resource "cedar_policy" "test" {
+					policy_store_id = cedar_policy_store.test.id
+					policy_id       = "read"
+					policy = {
+						effect       = "permit"
+						principal_in = { uid = "CF::Group::\"admins\"" }
+						action       = { type = "CF::Action", id = "Read" }
+						any_resource = true
+						when         = [{ text = "context.mfa" }]
+					}
+				}
+				`, dir),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cedar_policy.test", "statement", "permit (\n\tprincipal in CF::Group::\"admins\",\n\taction == CF::Action::\"Read\",\n\tresource\n)\nwhen {\n\tcontext.mfa\n};"),
+					resource.TestCheckResourceAttr("cedar_policy.test", "version", "1"),
+					resource.TestCheckResourceAttr("cedar_policy.test", "policy.principal_in.type", "CF::Group"),
+					resource.TestCheckResourceAttr("cedar_policy.test", "policy.principal_in.id", "admins"),
+					resource.TestCheckResourceAttr("cedar_policy.test", "policy.action.uid", "CF::Action::\"Read\""),
+				),
+			},
+			{
+				ResourceName:      "cedar_policy.test",
+				ImportState:       true,
+				ImportStateId:     dir + ",read",
+				ImportStateVerify: true,
+			},
+			{
+				// policies can be imported using their Cedar text.
+				ResourceName:      "cedar_policy.test",
+				ImportState:       true,
+				ImportStateId:     dir + `//permit (principal in CF::Group::"admins", action == CF::Action::"Read", resource) when { context.mfa };`,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestPolicyResource_FormattingOnly(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+
+	config := func(statement string) string {
+		return fmt.Sprintf(`
+		resource "cedar_policy_store" "test" {
+			path = %q
+		}
+
+		resource "cedar_policy" "test" {
+			policy_store_id = cedar_policy_store.test.id
+			policy_id       = "read"
+			statement       = %q
+		}
+		`, dir, statement)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`permit (principal == CF::User::"alice", action, resource) when { context.mfa };`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cedar_policy.test", "version", "1"),
+					resource.TestCheckResourceAttr("cedar_policy.test", "policy.effect", "permit"),
+					resource.TestCheckResourceAttr("cedar_policy.test", "policy.principal.type", "CF::User"),
+					resource.TestCheckResourceAttr("cedar_policy.test", "policy.principal.id", "alice"),
+					resource.TestCheckResourceAttr("cedar_policy.test", "policy.any_action", "true"),
+					resource.TestCheckResourceAttr("cedar_policy.test", "policy.when.0.text", "context.mfa"),
+				),
+			},
+			{
+				// formatting-only changes don't record a new version.
+				Config: config("// alice may access everything\npermit (\n  principal == CF::User::\"alice\",\n  action,\n  resource\n)\nwhen { context.mfa };"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cedar_policy.test", "version", "1"),
+				),
+			},
+			{
+				ResourceName:      "cedar_policy.test",
+				ImportState:       true,
+				ImportStateId:     dir + ",read",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParsePolicyImportID(t *testing.T) {
+	storeID, policyID, statement, err := parsePolicyImportID("./store,read")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"./store", "read", ""}, []string{storeID, policyID, statement})
+
+	// store IDs may contain commas.
+	storeID, policyID, statement, err = parsePolicyImportID("./a,b,read")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"./a,b", "read", ""}, []string{storeID, policyID, statement})
+
+	storeID, policyID, statement, err = parsePolicyImportID(`./a,b//permit (principal, action, resource) when { context.url == "http://example.com" }; // read`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"./a,b", "", `permit (principal, action, resource) when { context.url == "http://example.com" }; // read`}, []string{storeID, policyID, statement})
+
+	_, _, _, err = parsePolicyImportID("read")
+	assert.Error(t, err)
+
+	_, _, _, err = parsePolicyImportID("./store//")
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ basetypes.StringTypable = PolicyStatementType{}
+var _ basetypes.StringValuableWithSemanticEquals = PolicyStatement{}
+
+// PolicyStatementType is a string type for Cedar policy text. Statements which
+// differ only in formatting, such as whitespace and comments, are semantically
+// equal, so reformatting a policy doesn't cause drift.
+type PolicyStatementType struct {
+	basetypes.StringType
+}
+
+func (t PolicyStatementType) Equal(o attr.Type) bool {
+	other, ok := o.(PolicyStatementType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t PolicyStatementType) String() string {
+	return "PolicyStatementType"
+}
+
+func (t PolicyStatementType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return PolicyStatement{StringValue: in}, nil
+}
+
+func (t PolicyStatementType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return PolicyStatement{StringValue: stringValue}, nil
+}
+
+func (t PolicyStatementType) ValueType(ctx context.Context) attr.Value {
+	return PolicyStatement{}
+}
+
+// PolicyStatement is a Cedar policy text value.
+type PolicyStatement struct {
+	basetypes.StringValue
+}
+
+// NewPolicyStatementValue returns a known PolicyStatement.
+func NewPolicyStatementValue(s string) PolicyStatement {
+	return PolicyStatement{StringValue: basetypes.NewStringValue(s)}
+}
+
+func (v PolicyStatement) Equal(o attr.Value) bool {
+	other, ok := o.(PolicyStatement)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v PolicyStatement) Type(ctx context.Context) attr.Type {
+	return PolicyStatementType{}
+}
+
+func (v PolicyStatement) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(PolicyStatement)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	return cedarpolicy.Equivalent(v.ValueString(), newValue.ValueString()), diags
+}
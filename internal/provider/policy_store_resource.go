@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/policystore"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &PolicyStoreResource{}
+var _ resource.ResourceWithImportState = &PolicyStoreResource{}
+
+type PolicyStoreResource struct{}
+
+func NewPolicyStoreResource() resource.Resource {
+	return &PolicyStoreResource{}
+}
+
+type PolicyStoreResourceModel struct {
+	ID     types.String `tfsdk:"id"`
+	Path   types.String `tfsdk:"path"`
+	Schema types.String `tfsdk:"schema"`
+}
+
+func (r *PolicyStoreResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policy_store"
+}
+
+func (r *PolicyStoreResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A local, file-backed Cedar policy store.",
+		MarkdownDescription: `A local, file-backed Cedar policy store, for use in local development and CI in place of a hosted policy store.
+
+Policies and templates are added to the store with the 'cedar_policy' and 'cedar_policy_template' resources. If the store has a schema, policies and templates are validated against it when they are written. Every change to a policy or template is recorded as a new version in the store.
+
+If the path ends in '.json', the store is kept in a single JSON file. Otherwise the store is kept in a directory, with its metadata in 'store.json', each policy in 'policies/<id>.cedar' and each template in 'templates/<id>.cedar'.
+
+Stores are kept as JSON, either in a single file or in a directory; other storage formats, such as bbolt databases, are not supported. Changes to a store are serialized within a single Terraform run, but not across processes, so two Terraform runs must not change the same store at the same time.
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The path of the policy store. Use this as the 'policy_store_id' of policies and templates.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The directory or '.json' file to keep the policy store in.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "A Cedar schema in JSON format, used to validate policies and templates when they are written.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *PolicyStoreResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PolicyStoreResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := policystore.Open(data.Path.ValueString()).Create(data.Schema.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Unable to Create resource: Cedar Policy Store", err.Error())
+		return
+	}
+
+	data.ID = data.Path
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyStoreResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PolicyStoreResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	store, err := policystore.Open(data.ID.ValueString()).Read()
+	if errors.Is(err, policystore.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read resource: Cedar Policy Store", err.Error())
+		return
+	}
+
+	data.Path = data.ID
+	data.Schema = optionalString(store.Schema)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyStoreResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PolicyStoreResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := policystore.Open(data.ID.ValueString()).Update(func(store *policystore.Data) error {
+		return store.SetSchema(data.Schema.ValueString())
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update resource: Cedar Policy Store", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyStoreResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PolicyStoreResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := policystore.Open(data.ID.ValueString()).Destroy(); err != nil {
+		resp.Diagnostics.AddError("Unable to Delete resource: Cedar Policy Store", err.Error())
+	}
+}
+
+func (r *PolicyStoreResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// storeEntryID returns the ID of a policy or template resource, which
+// is the policy store ID and the policy or template ID separated by a comma.
+func storeEntryID(storeID, id string) string {
+	return storeID + "," + id
+}
+
+// parseStoreEntryID splits the ID of a policy or template resource into
+// the policy store ID and the policy or template ID.
+func parseStoreEntryID(id string) (string, string, error) {
+	i := strings.LastIndex(id, ",")
+	if i <= 0 || i == len(id)-1 {
+		return "", "", fmt.Errorf("expected an ID in the format '<policy_store_id>,<id>', got %q", id)
+	}
+	return id[:i], id[i+1:], nil
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestPolicyStoreResource(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+
+	config := func(statement string) string {
+		return fmt.Sprintf(`
+		resource "cedar_policy_store" "test" {
+			path = %q
+			schema = jsonencode({
+				CF = {
+					entityTypes = {
+						User = {}
+						Document = {}
+					}
+					actions = {
+						Read = {
+							appliesTo = {
+								principalTypes = ["User"]
+								resourceTypes = ["Document"]
+							}
+						}
+					}
+				}
+			})
+		}
+
+		resource "cedar_policy" "static" {
+			policy_store_id = cedar_policy_store.test.id
+			policy_id       = "read"
+			statement       = %q
+		}
+
+		resource "cedar_policy_template" "owner" {
+			policy_store_id = cedar_policy_store.test.id
+			template_id     = "owner"
+			statement       = "permit (principal == ?principal, action == CF::Action::\"Read\", resource == ?resource);"
+		}
+
+		resource "cedar_policy" "linked" {
+			policy_store_id = cedar_policy_store.test.id
+			policy_id       = "alice-plan"
+			template_linked = {
+				template_id = cedar_policy_template.owner.template_id
+				principal   = { uid = "CF::User::\"alice\"" }
+				resource    = { type = "CF::Document", id = "plan" }
+			}
+		}
+		`, dir, statement)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFilesNotExist(dir, "store.json"),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`permit (principal, action == CF::Action::"Read", resource);`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cedar_policy.static", "version", "1"),
+					resource.TestCheckResourceAttr("cedar_policy.linked", "version", "1"),
+					testCheckFileContent(filepath.Join(dir, "policies", "alice-plan.cedar"), "permit (\n\tprincipal == CF::User::\"alice\",\n\taction == CF::Action::\"Read\",\n\tresource == CF::Document::\"plan\"\n);\n"),
+				),
+			},
+			{
+				Config: config(`permit (principal is CF::User, action == CF::Action::"Read", resource);`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cedar_policy.static", "version", "2"),
+				),
+			},
+			{
+				ResourceName:      "cedar_policy.static",
+				ImportState:       true,
+				ImportStateId:     dir + ",read",
+				ImportStateVerify: true,
+			},
+			{
+				Config:      config(`permit (principal, action == CF::Action::"Write", resource);`),
+				ExpectError: regexp.MustCompile(`unknown action\s+CF::Action::"Write"`),
+			},
+		},
+	})
+}
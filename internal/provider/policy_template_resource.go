@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/policystore"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &PolicyTemplateResource{}
+var _ resource.ResourceWithImportState = &PolicyTemplateResource{}
+
+type PolicyTemplateResource struct{}
+
+func NewPolicyTemplateResource() resource.Resource {
+	return &PolicyTemplateResource{}
+}
+
+type PolicyTemplateResourceModel struct {
+	ID            types.String    `tfsdk:"id"`
+	PolicyStoreID types.String    `tfsdk:"policy_store_id"`
+	TemplateID    types.String    `tfsdk:"template_id"`
+	Description   types.String    `tfsdk:"description"`
+	Statement     PolicyStatement `tfsdk:"statement"`
+	Version       types.Int64     `tfsdk:"version"`
+}
+
+func (r *PolicyTemplateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policy_template"
+}
+
+func (r *PolicyTemplateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A Cedar policy template in a 'cedar_policy_store'.",
+		MarkdownDescription: `A Cedar policy template in a 'cedar_policy_store'. Policies are created from the template using the 'template_linked' attribute of 'cedar_policy'.
+
+If the store has a schema, the template is validated against it when it is written. Every change to the template is recorded as a new version in the store, and policies linked to the template are updated to match. Changes which only affect formatting, such as whitespace, line breaks and comments, are not recorded as a new version.
+
+Templates can be imported using the policy store ID and the template ID separated by a comma, for example 'terraform import cedar_policy_template.example ./store,owner'.
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The policy store ID and the template ID, separated by a comma.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"policy_store_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the 'cedar_policy_store' to add the template to.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"template_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the template in the store. May only contain letters, digits, '-' and '_'. A random ID is generated if not provided.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "A description of the template.",
+				Optional:            true,
+			},
+			"statement": schema.StringAttribute{
+				MarkdownDescription: "The Cedar text of the template. The scope must refer to the '?principal' slot, the '?resource' slot, or both, for example 'permit (principal == ?principal, action, resource in ?resource);'.",
+				CustomType:          PolicyStatementType{},
+				Required:            true,
+			},
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "The current version of the template in the store.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *PolicyTemplateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PolicyTemplateResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.TemplateID.IsUnknown() || data.TemplateID.IsNull() {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Create resource: Cedar Policy Template", err.Error())
+			return
+		}
+		data.TemplateID = types.StringValue(id)
+	}
+
+	if err := r.put(&data); err != nil {
+		resp.Diagnostics.AddError("Unable to Create resource: Cedar Policy Template", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyTemplateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PolicyTemplateResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	store, err := policystore.Open(data.PolicyStoreID.ValueString()).Read()
+	if errors.Is(err, policystore.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read resource: Cedar Policy Template", err.Error())
+		return
+	}
+
+	// entries whose file has been removed from a directory store are
+	// recreated, keeping their version history.
+	entry, ok := store.Templates[data.TemplateID.ValueString()]
+	if !ok || entry.Missing {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.ID = types.StringValue(storeEntryID(data.PolicyStoreID.ValueString(), entry.ID))
+	data.Description = optionalString(entry.Description)
+	data.Statement = NewPolicyStatementValue(entry.Statement)
+	data.Version = types.Int64Value(int64(entry.Version()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyTemplateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PolicyTemplateResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.put(&data); err != nil {
+		resp.Diagnostics.AddError("Unable to Update resource: Cedar Policy Template", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyTemplateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PolicyTemplateResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := policystore.Open(data.PolicyStoreID.ValueString()).Update(func(store *policystore.Data) error {
+		return store.DeleteTemplate(data.TemplateID.ValueString())
+	})
+	if err != nil && !errors.Is(err, policystore.ErrNotFound) {
+		resp.Diagnostics.AddError("Unable to Delete resource: Cedar Policy Template", err.Error())
+	}
+}
+
+func (r *PolicyTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	storeID, templateID, err := parseStoreEntryID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Import resource: Cedar Policy Template", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("policy_store_id"), storeID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("template_id"), templateID)...)
+}
+
+// put writes the template to the store, and updates the ID and version.
+func (r *PolicyTemplateResource) put(data *PolicyTemplateResourceModel) error {
+	var entry *policystore.Entry
+
+	err := policystore.Open(data.PolicyStoreID.ValueString()).Update(func(store *policystore.Data) error {
+		var err error
+		entry, err = store.PutTemplate(data.TemplateID.ValueString(), data.Description.ValueString(), data.Statement.ValueString())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	data.ID = types.StringValue(storeEntryID(data.PolicyStoreID.ValueString(), entry.ID))
+	data.Version = types.Int64Value(int64(entry.Version()))
+	return nil
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &PolicySetDiffDataSource{}
+var _ datasource.DataSourceWithConfigure = &PolicySetDiffDataSource{}
+
+type PolicySetDiffDataSource struct {
+	provider *ProviderData
+}
+
+func NewPolicySetDiffDataSource() datasource.DataSource {
+	return &PolicySetDiffDataSource{}
+}
+
+type PolicySetDiffDataSourceModel struct {
+	Old        types.String          `tfsdk:"old"`
+	New        types.String          `tfsdk:"new"`
+	Added      []types.String        `tfsdk:"added"`
+	Removed    []types.String        `tfsdk:"removed"`
+	Modified   []ModifiedPolicyModel `tfsdk:"modified"`
+	HasChanges types.Bool            `tfsdk:"has_changes"`
+	Summary    types.String          `tfsdk:"summary"`
+}
+
+type ModifiedPolicyModel struct {
+	PolicyID types.String       `tfsdk:"policy_id"`
+	Changes  []FieldChangeModel `tfsdk:"changes"`
+}
+
+type FieldChangeModel struct {
+	Field types.String `tfsdk:"field"`
+	Old   types.String `tfsdk:"old"`
+	New   types.String `tfsdk:"new"`
+}
+
+func (d *PolicySetDiffDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policyset_diff"
+}
+
+func (d *PolicySetDiffDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Compares two Cedar Policy Sets and reports the policies which were added, removed or modified.",
+		MarkdownDescription: `Compares two Cedar Policy Sets and reports the policies which were added, removed or modified.
+
+Policies with an '@id' annotation are matched by ID. Policies without an ID are matched by content, so reordering or reformatting policies is not reported as a change. Any remaining policies without an ID are matched by their position in the set, and are identified as 'policy' followed by their index.
+
+Modified policies report field-level changes to the effect, the 'principal', 'action' and 'resource' scope, annotations (for example 'annotation.advice'), and conditions (for example 'when[0]').
+`,
+		Attributes: map[string]schema.Attribute{
+			"old": schema.StringAttribute{
+				MarkdownDescription: "The original Cedar PolicySet, as text.",
+				Required:            true,
+			},
+			"new": schema.StringAttribute{
+				MarkdownDescription: "The updated Cedar PolicySet, as text.",
+				Required:            true,
+			},
+			"added": schema.ListAttribute{
+				MarkdownDescription: "The IDs of policies present in 'new' but not in 'old'.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"removed": schema.ListAttribute{
+				MarkdownDescription: "The IDs of policies present in 'old' but not in 'new'.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"modified": schema.ListNestedAttribute{
+				MarkdownDescription: "The policies present in both PolicySets which were changed.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"policy_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the modified policy.",
+							Computed:            true,
+						},
+						"changes": schema.ListNestedAttribute{
+							MarkdownDescription: "The fields of the policy which were changed.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"field": schema.StringAttribute{
+										MarkdownDescription: "The name of the changed field, for example 'principal', 'annotation.advice' or 'when[0]'.",
+										Computed:            true,
+									},
+									"old": schema.StringAttribute{
+										MarkdownDescription: "The original value of the field. Null if the field was added.",
+										Computed:            true,
+									},
+									"new": schema.StringAttribute{
+										MarkdownDescription: "The updated value of the field. Null if the field was removed.",
+										Computed:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"has_changes": schema.BoolAttribute{
+				MarkdownDescription: "True if any policies were added, removed or modified.",
+				Computed:            true,
+			},
+			"summary": schema.StringAttribute{
+				MarkdownDescription: "A human-readable summary of the changes, suitable for outputs or pull request comments.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *PolicySetDiffDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.provider = providerData
+}
+
+func (d *PolicySetDiffDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data PolicySetDiffDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	oldPolicies, err := cedarpolicy.ParsePolicySet(data.Old.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create data source: Cedar PolicySet Diff",
+			"Unable to parse 'old': "+err.Error(),
+		)
+		return
+	}
+
+	newPolicies, err := cedarpolicy.ParsePolicySet(data.New.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create data source: Cedar PolicySet Diff",
+			"Unable to parse 'new': "+err.Error(),
+		)
+		return
+	}
+
+	changes := cedarpolicy.Diff(withNamespace(oldPolicies, d.provider.defaultNamespace()), withNamespace(newPolicies, d.provider.defaultNamespace()))
+
+	data.Added = []types.String{}
+	data.Removed = []types.String{}
+	data.Modified = []ModifiedPolicyModel{}
+
+	for _, change := range changes {
+		switch change.Kind {
+		case cedarpolicy.PolicyAdded:
+			data.Added = append(data.Added, types.StringValue(change.PolicyID))
+		case cedarpolicy.PolicyRemoved:
+			data.Removed = append(data.Removed, types.StringValue(change.PolicyID))
+		case cedarpolicy.PolicyModified:
+			modified := ModifiedPolicyModel{PolicyID: types.StringValue(change.PolicyID)}
+			for _, f := range change.Fields {
+				modified.Changes = append(modified.Changes, FieldChangeModel{
+					Field: types.StringValue(f.Field),
+					Old:   optionalString(f.Old),
+					New:   optionalString(f.New),
+				})
+			}
+			data.Modified = append(data.Modified, modified)
+		}
+	}
+
+	data.HasChanges = types.BoolValue(len(changes) > 0)
+	data.Summary = types.StringValue(cedarpolicy.DiffSummary(changes))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// optionalString returns a null string if s is empty.
+func optionalString(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
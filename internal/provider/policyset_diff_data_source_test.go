@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestPolicySetDiffDataSource_Simple(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset_diff" "test" {
+					old = <<-EOT
+					@id("admins")
+					permit (principal in Group::"admins", action, resource);
+
+					permit (principal, action == Action::"Read", resource);
+					EOT
+
+					new = <<-EOT
+					permit (
+						principal,
+						action == Action::"Read",
+						resource
+					);
+
+					@id("admins")
+					permit (principal in Group::"owners", action, resource);
+					EOT
+				}
+
+				output "test" {
+					value = data.cedar_policyset_diff.test.summary
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset_diff.test", "added.#", "0"),
+					resource.TestCheckResourceAttr("data.cedar_policyset_diff.test", "removed.#", "0"),
+					resource.TestCheckResourceAttr("data.cedar_policyset_diff.test", "modified.0.policy_id", "admins"),
+					resource.TestCheckResourceAttr("data.cedar_policyset_diff.test", "modified.0.changes.0.field", "principal"),
+					resource.TestCheckOutput("test", "0 added, 0 removed, 1 modified.\n\n~ admins\n    principal: principal in Group::\"admins\" -> principal in Group::\"owners\"\n"),
+				),
+			},
+		},
+	})
+}
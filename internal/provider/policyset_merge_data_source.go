@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &PolicySetMergeDataSource{}
+var _ datasource.DataSourceWithConfigure = &PolicySetMergeDataSource{}
+
+type PolicySetMergeDataSource struct {
+	provider *ProviderData
+}
+
+func NewPolicySetMergeDataSource() datasource.DataSource {
+	return &PolicySetMergeDataSource{}
+}
+
+type PolicySetMergeDataSourceModel struct {
+	Sources   []MergeSourceModel `tfsdk:"source"`
+	PrefixIDs types.Bool         `tfsdk:"prefix_ids"`
+	Schema    types.String       `tfsdk:"schema"`
+	Text      types.String       `tfsdk:"text"`
+	JSON      types.String       `tfsdk:"json"`
+	PolicyIDs []types.String     `tfsdk:"policy_ids"`
+}
+
+// MergeSourceModel describes a policy set to be merged.
+type MergeSourceModel struct {
+	Name types.String `tfsdk:"name"`
+	Text types.String `tfsdk:"text"`
+	JSON types.String `tfsdk:"json"`
+}
+
+func (d *PolicySetMergeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policyset_merge"
+}
+
+func (d *PolicySetMergeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Merges several Cedar Policy Sets into one, detecting conflicting and duplicate policies.",
+		MarkdownDescription: `Merges several Cedar Policy Sets into one, detecting conflicting and duplicate policies.
+
+Each source is given either as Cedar text or in the Cedar JSON policy set format, such as the 'text' or 'json' attributes of the 'cedar_policyset' data source. Policies are merged in the order of the sources.
+
+Policies which are identical to a policy from an earlier source, ignoring their '@id' annotation, are left out of the merged PolicySet and reported as warnings, or as errors if the provider's 'strict' is set. Two different policies with the same ID are reported as an error. Use 'prefix_ids' to keep the policies from each source apart.
+
+Policies are identified by their '@id' annotation, or by 'policy' followed by their index within their source if no ID annotation is present. Policies without an ID annotation, and policies from JSON sources, are given an '@id' annotation with their ID, so that their ID doesn't change when they are merged.
+`,
+		Attributes: map[string]schema.Attribute{
+			"source": schema.ListNestedAttribute{
+				MarkdownDescription: "The Cedar PolicySets to merge.",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the source, used in messages and as the prefix of policy IDs if 'prefix_ids' is set.",
+							Required:            true,
+						},
+						"text": schema.StringAttribute{
+							MarkdownDescription: "The Cedar PolicySet, as text. Exactly one of 'text' or 'json' must be provided.",
+							Optional:            true,
+						},
+						"json": schema.StringAttribute{
+							MarkdownDescription: "The Cedar PolicySet, in the Cedar JSON policy set format. Exactly one of 'text' or 'json' must be provided.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"prefix_ids": schema.BoolAttribute{
+				MarkdownDescription: "If true, the ID of each policy is prefixed with the name of its source and a '.', for example 'platform.admins', and set as the policy's '@id' annotation. Defaults to false.",
+				Optional:            true,
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "A Cedar schema in JSON format, or the path to a file containing one. If provided, the merged policies are validated against the schema. Overrides the provider's 'schema'.",
+				Optional:            true,
+			},
+			"text": schema.StringAttribute{
+				MarkdownDescription: "The merged Cedar PolicySet, rendered as a string.",
+				Computed:            true,
+			},
+			"json": schema.StringAttribute{
+				MarkdownDescription: "The merged Cedar PolicySet, rendered in the Cedar JSON policy set format with each policy keyed by its ID.",
+				Computed:            true,
+			},
+			"policy_ids": schema.ListAttribute{
+				MarkdownDescription: "The IDs of the policies in the merged PolicySet.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *PolicySetMergeDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.provider = providerData
+}
+
+func (d *PolicySetMergeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data PolicySetMergeDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sources := make([]cedarpolicy.Source, len(data.Sources))
+	for i, source := range data.Sources {
+		name := source.Name.ValueString()
+		if source.Text.IsNull() == source.JSON.IsNull() {
+			resp.Diagnostics.AddError(
+				"Unable to Create data source: Cedar PolicySet Merge",
+				fmt.Sprintf("source %q: exactly one of 'text' or 'json' must be provided", name),
+			)
+			continue
+		}
+
+		var policies []cedarpolicy.Policy
+		var err error
+		if !source.Text.IsNull() {
+			policies, err = d.provider.cedarVersion().ParsePolicySet(source.Text.ValueString())
+		} else {
+			policies, err = cedarpolicy.ParsePolicySetJSON([]byte(source.JSON.ValueString()))
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create data source: Cedar PolicySet Merge",
+				fmt.Sprintf("Unable to parse source %q: %s", name, err),
+			)
+			continue
+		}
+		sources[i] = cedarpolicy.Source{Name: name, Policies: policies}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policies, duplicates, err := cedarpolicy.Merge(sources, data.PrefixIDs.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet Merge", err.Error())
+		return
+	}
+	for _, duplicate := range duplicates {
+		d.provider.lint(&resp.Diagnostics, "Cedar PolicySet Merge: duplicate policy", duplicate.Message())
+	}
+
+	schema, err := d.schema(data)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet Merge", err.Error())
+		return
+	}
+	if schema != nil {
+		for i, policy := range policies {
+			for _, err := range schema.ValidatePolicy(policy) {
+				resp.Diagnostics.AddError(
+					"Unable to Create data source: Cedar PolicySet Merge",
+					fmt.Sprintf("Policy %q is not valid for the schema: %s", policy.ID(i), err),
+				)
+			}
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var text string
+	if d.provider != nil && d.provider.Format != nil {
+		text, err = cedarpolicy.FormatPolicySet(policies, *d.provider.Format)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet Merge", "Unable to format the PolicySet: "+err.Error())
+			return
+		}
+	} else {
+		rendered := make([]string, len(policies))
+		for i, policy := range policies {
+			rendered[i], err = policy.RenderString()
+			if err != nil {
+				resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet Merge", err.Error())
+				return
+			}
+		}
+		text = strings.Join(rendered, "\n\n") + "\n"
+	}
+	data.Text = types.StringValue(text)
+
+	policySetJSON, err := cedarpolicy.RenderPolicySetJSON(policies)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create data source: Cedar PolicySet Merge", "Unable to render the PolicySet as JSON: "+err.Error())
+		return
+	}
+	data.JSON = types.StringValue(string(policySetJSON))
+
+	data.PolicyIDs = make([]types.String, len(policies))
+	for i, policy := range policies {
+		data.PolicyIDs[i] = types.StringValue(policy.ID(i))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// schema returns the schema used to validate the merged policies, which is the
+// data source's 'schema' if set, otherwise the provider's default schema.
+func (d *PolicySetMergeDataSource) schema(data PolicySetMergeDataSourceModel) (*cedarpolicy.Schema, error) {
+	if data.Schema.ValueString() == "" {
+		if d.provider != nil {
+			return d.provider.Schema, nil
+		}
+		return nil, nil
+	}
+
+	schemaJSON, err := readSchema(data.Schema.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read 'schema': %w", err)
+	}
+	schema, err := cedarpolicy.ParseSchema(schemaJSON)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse 'schema': %w", err)
+	}
+	return schema, nil
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestPolicySetMergeDataSource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_policyset" "product" {
+					policy {
+						effect = "permit"
+						annotation {
+							name = "id"
+							value = "readers"
+						}
+						principal_in = { type = "Group", id = "readers" }
+						action = { type = "Action", id = "Read" }
+						any_resource = true
+					}
+				}
+
+				data "cedar_policyset_merge" "test" {
+					prefix_ids = true
+
+					source = [
+						{
+							name = "platform"
+							text = <<-EOT
+							@id("admins")
+							permit (principal in Group::"admins", action, resource);
+							EOT
+						},
+						{
+							name = "product"
+							json = data.cedar_policyset.product.json
+						},
+					]
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_policyset_merge.test", "policy_ids.#", "2"),
+					resource.TestCheckResourceAttr("data.cedar_policyset_merge.test", "policy_ids.0", "platform.admins"),
+					resource.TestCheckResourceAttr("data.cedar_policyset_merge.test", "policy_ids.1", "product.readers"),
+					resource.TestCheckResourceAttr("data.cedar_policyset_merge.test", "text", "@id(\"platform.admins\")\npermit (\n\tprincipal in Group::\"admins\",\n\taction,\n\tresource\n);\n\n@id(\"product.readers\")\npermit (\n\tprincipal in Group::\"readers\",\n\taction == Action::\"Read\",\n\tresource\n);\n"),
+				),
+			},
+			{
+				Config: `
+				data "cedar_policyset_merge" "test" {
+					source = [
+						{
+							name = "platform"
+							text = "@id(\"admins\") permit (principal in Group::\"admins\", action, resource);"
+						},
+						{
+							name = "product"
+							text = "@id(\"admins\") permit (principal, action, resource);"
+						},
+					]
+				}
+				`,
+				ExpectError: regexp.MustCompile(`duplicate policy IDs in the merged PolicySet: "admins"`),
+			},
+			{
+				// duplicate policies are errors if the provider is strict.
+				Config: `
+				provider "cedar" {
+					strict = true
+				}
+
+				data "cedar_policyset_merge" "test" {
+					source = [
+						{
+							name = "platform"
+							text = "permit (principal, action, resource);"
+						},
+						{
+							name = "product"
+							text = "permit (principal, action, resource);"
+						},
+					]
+				}
+				`,
+				ExpectError: regexp.MustCompile(`Cedar PolicySet Merge: duplicate policy`),
+			},
+		},
+	})
+}
@@ -2,12 +2,19 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure CedarProvider satisfies various provider interfaces.
@@ -24,6 +31,42 @@ type CedarProvider struct {
 
 // CedarProviderModel describes the provider data model.
 type CedarProviderModel struct {
+	DefaultNamespace types.String `tfsdk:"default_namespace"`
+	Schema           types.String `tfsdk:"schema"`
+	Strict           types.Bool   `tfsdk:"strict"`
+	Format           *FormatModel `tfsdk:"format"`
+	TimeAttribute    types.String `tfsdk:"time_attribute"`
+	DropExpired      types.Bool   `tfsdk:"drop_expired"`
+	CedarVersion     types.String `tfsdk:"cedar_version"`
+	Macros           []MacroModel `tfsdk:"condition_macro"`
+}
+
+// ProviderData is the provider configuration which is passed to data sources and resources.
+type ProviderData struct {
+	// DefaultNamespace is prepended to unqualified entity types in policy scopes.
+	DefaultNamespace string
+
+	// Schema is used to validate policies, if set.
+	Schema *cedarpolicy.Schema
+
+	// Strict causes lint warnings to be reported as errors.
+	Strict bool
+
+	// Format is used to render policies if the data source doesn't specify its own formatting options.
+	Format *cedarpolicy.FormatOptions
+
+	// TimeAttribute is the attribute which the 'not_before' and 'not_after' bounds of policies are compared with.
+	TimeAttribute string
+
+	// DropExpired causes policies whose 'not_after' time has passed to be left out of policy sets.
+	DropExpired bool
+
+	// Macros are the condition macros which policies may refer to by name.
+	Macros cedarpolicy.Macros
+
+	// CedarVersion is the Cedar language version which policies must be supported by.
+	// The zero Version permits the latest syntax.
+	CedarVersion cedarpolicy.Version
 }
 
 func (p *CedarProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -33,7 +76,58 @@ func (p *CedarProvider) Metadata(ctx context.Context, req provider.MetadataReque
 
 func (p *CedarProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Attributes: map[string]schema.Attribute{},
+		Attributes: map[string]schema.Attribute{
+			"default_namespace": schema.StringAttribute{
+				MarkdownDescription: "A namespace which is prepended to unqualified entity types in policy scopes. For example, with a default namespace of 'CF', 'User' is rendered as 'CF::User'. Entity types which already contain '::' are not changed. Condition text is not modified.",
+				Optional:            true,
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "A Cedar schema in JSON format, or the path to a file containing one. If provided, the policies in each 'cedar_policyset' are validated against the schema, and the schema is used by 'cedar_permission_matrix' when it doesn't specify its own.",
+				Optional:            true,
+			},
+			"strict": schema.BoolAttribute{
+				MarkdownDescription: "If true, lint warnings are reported as errors: redundant and overridden policies, expired policies which are dropped, policy sets which can't be rendered as JSON, and duplicate policies in 'cedar_policyset_merge'.",
+				Optional:            true,
+			},
+			"format": schema.SingleNestedAttribute{
+				MarkdownDescription: "Default formatting options for 'cedar_policyset' data sources which don't specify a 'format' of their own. If provided, policies are rendered using the canonical Cedar formatter.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"indent": schema.StringAttribute{
+						MarkdownDescription: "The string used for each level of indentation. Defaults to a tab.",
+						Optional:            true,
+					},
+					"line_width": schema.Int64Attribute{
+						MarkdownDescription: "The maximum line width before action lists and '&&'/'||' chains are wrapped onto multiple lines. Tabs count as four characters. Set to 0 to disable wrapping. Defaults to 80.",
+						Optional:            true,
+					},
+				},
+			},
+			"time_attribute": schema.StringAttribute{
+				MarkdownDescription: "The attribute which the 'not_before' and 'not_after' bounds of policies are compared with, which must be a Cedar 'datetime' in authorization requests. Defaults to 'context.now'.",
+				Optional:            true,
+			},
+			"drop_expired": schema.BoolAttribute{
+				MarkdownDescription: "If true, policies in a 'cedar_policyset' whose 'not_after' time has passed are left out of the PolicySet when it is rendered, and a warning listing them is reported.",
+				Optional:            true,
+			},
+			"cedar_version": schema.StringAttribute{
+				MarkdownDescription: cedarVersionDescription,
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"condition_macro": schema.ListNestedBlock{
+				MarkdownDescription: macroDescriptions["block"] + " Provider macros can be used by every 'cedar_policyset' data source and 'cedar_policy' resource.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name":   schema.StringAttribute{Required: true, MarkdownDescription: macroDescriptions["name"]},
+						"text":   schema.StringAttribute{Required: true, MarkdownDescription: macroDescriptions["text"]},
+						"params": schema.ListAttribute{Optional: true, ElementType: types.StringType, MarkdownDescription: macroDescriptions["params"]},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -45,20 +139,226 @@ func (p *CedarProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	providerData := &ProviderData{
+		DefaultNamespace: data.DefaultNamespace.ValueString(),
+		Strict:           data.Strict.ValueBool(),
+		TimeAttribute:    data.TimeAttribute.ValueString(),
+		DropExpired:      data.DropExpired.ValueBool(),
+	}
+
+	if err := checkTimeAttribute(providerData.TimeAttribute); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("time_attribute"),
+			"Unable to Configure provider: Cedar",
+			err.Error(),
+		)
+		return
+	}
+
+	macros, err := newMacros(data.Macros)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("condition_macro"),
+			"Unable to Configure provider: Cedar",
+			err.Error(),
+		)
+		return
+	}
+	providerData.Macros = macros
+
+	if !data.CedarVersion.IsNull() {
+		providerData.CedarVersion, err = cedarpolicy.ParseVersion(data.CedarVersion.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("cedar_version"),
+				"Unable to Configure provider: Cedar",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	if data.Schema.ValueString() != "" {
+		schemaJSON, err := readSchema(data.Schema.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("schema"),
+				"Unable to Configure provider: Cedar",
+				"Unable to read 'schema': "+err.Error(),
+			)
+			return
+		}
+
+		providerData.Schema, err = cedarpolicy.ParseSchema(schemaJSON)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("schema"),
+				"Unable to Configure provider: Cedar",
+				"Unable to parse 'schema': "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if data.Format != nil {
+		opts := data.Format.Options()
+		providerData.Format = &opts
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+}
+
+// defaultNamespace returns the provider's default namespace.
+// It is safe to call on a nil ProviderData.
+func (p *ProviderData) defaultNamespace() string {
+	if p == nil {
+		return ""
+	}
+	return p.DefaultNamespace
+}
+
+// timeAttribute returns the attribute which the time bounds of policies are
+// compared with. It is safe to call on a nil ProviderData.
+func (p *ProviderData) timeAttribute() string {
+	if p == nil || p.TimeAttribute == "" {
+		return cedarpolicy.DefaultTimeAttribute
+	}
+	return p.TimeAttribute
+}
+
+// macros returns the provider's condition macros.
+// It is safe to call on a nil ProviderData.
+func (p *ProviderData) macros() cedarpolicy.Macros {
+	if p == nil {
+		return nil
+	}
+	return p.Macros
+}
+
+// cedarVersion returns the Cedar version which policies must be supported by.
+// It is safe to call on a nil ProviderData.
+func (p *ProviderData) cedarVersion() cedarpolicy.Version {
+	if p == nil {
+		return cedarpolicy.Version{}
+	}
+	return p.CedarVersion
+}
+
+// cedarVersionDescription is the description of the 'cedar_version' attribute.
+const cedarVersionDescription = "The version of the Cedar language which policies are written for, such as '2.4' or '3.4'. Policy text is parsed, and rendered policies are checked, according to the syntax of that version, so that policies can be generated for older Cedar runtimes. For example, 'principal_is' and the 'is' operator are rejected before Cedar 3.0, entity tags before 4.0, annotations without a value before 4.2, and the 'datetime' and 'duration' extensions, which 'not_before' and 'not_after' are rendered with, before 4.3. Defaults to the latest version."
+
+// checkTimeAttribute returns an error if the time attribute is set and is not a Cedar expression.
+func checkTimeAttribute(attr string) error {
+	if attr == "" {
+		return nil
+	}
+	if _, err := cedarpolicy.ParseExpr(attr); err != nil {
+		return fmt.Errorf("'time_attribute' must be a Cedar expression such as 'context.now': %w", err)
+	}
+	return nil
+}
+
+// withNamespace returns the policies with the namespace prepended to unqualified entity types.
+func withNamespace(policies []cedarpolicy.Policy, namespace string) []cedarpolicy.Policy {
+	out := make([]cedarpolicy.Policy, len(policies))
+	for i, policy := range policies {
+		out[i] = policy.WithNamespace(namespace)
+	}
+	return out
+}
+
+// readSchema returns the schema JSON, reading it from a file
+// unless the value is an inline JSON object.
+func readSchema(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// lint adds a lint warning to the diagnostics, which is an error if the
+// provider is strict. It is safe to call on a nil ProviderData.
+func (p *ProviderData) lint(diags *diag.Diagnostics, summary, detail string) {
+	if p != nil && p.Strict {
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddWarning(summary, detail)
+}
+
+// schema returns the schema used to validate policies, which is read from the
+// value if set, otherwise the provider's default schema. It is safe to call
+// on a nil ProviderData.
+func (p *ProviderData) schema(value string) (*cedarpolicy.Schema, error) {
+	if value == "" {
+		if p == nil {
+			return nil, nil
+		}
+		return p.Schema, nil
+	}
+
+	schemaJSON, err := readSchema(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read 'schema': %w", err)
+	}
+	schema, err := cedarpolicy.ParseSchema(schemaJSON)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse 'schema': %w", err)
+	}
+	return schema, nil
+}
+
+// renderPolicySet renders the policies as text, using the provider's formatting
+// options if set. It is safe to call on a nil ProviderData.
+func (p *ProviderData) renderPolicySet(policies []cedarpolicy.Policy) (string, error) {
+	if p != nil && p.Format != nil {
+		formatted, err := cedarpolicy.FormatPolicySet(policies, *p.Format)
+		if err != nil {
+			return "", fmt.Errorf("unable to format the PolicySet: %w", err)
+		}
+		return formatted, nil
+	}
+
+	rendered := make([]string, len(policies))
+	for i, policy := range policies {
+		text, err := policy.RenderString()
+		if err != nil {
+			return "", fmt.Errorf("policy %q: %w", policy.ID(i), err)
+		}
+		rendered[i] = text
+	}
+	return strings.Join(rendered, "\n\n") + "\n", nil
 }
 
 func (p *CedarProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewPolicyFilesResource,
+		NewPolicyStoreResource,
+		NewPolicyResource,
+		NewPolicyTemplateResource,
+	}
 }
 
 func (p *CedarProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewPolicyDataSource,
+		NewPermissionMatrixDataSource,
+		NewPolicySetDiffDataSource,
+		NewPolicySetMergeDataSource,
+		NewRBACDataSource,
 	}
 }
 
 func (p *CedarProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewFormatFunction,
+		NewIPFunction,
+		NewDecimalFunction,
+		NewDatetimeFunction,
+		NewDurationFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
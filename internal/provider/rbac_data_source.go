@@ -0,0 +1,337 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &RBACDataSource{}
+var _ datasource.DataSourceWithConfigure = &RBACDataSource{}
+
+type RBACDataSource struct {
+	provider *ProviderData
+}
+
+func NewRBACDataSource() datasource.DataSource {
+	return &RBACDataSource{}
+}
+
+type RBACDataSourceModel struct {
+	Namespace      types.String         `tfsdk:"namespace"`
+	GroupType      types.String         `tfsdk:"group_type"`
+	Schema         types.String         `tfsdk:"schema"`
+	PermissionSets []PermissionSetModel `tfsdk:"permission_set"`
+	Roles          []RoleModel          `tfsdk:"role"`
+	Owners         []OwnerGrantModel    `tfsdk:"owner"`
+
+	Text     types.String          `tfsdk:"text"`
+	JSON     types.String          `tfsdk:"json"`
+	Rendered []RenderedPolicyModel `tfsdk:"policies"`
+}
+
+// PermissionSetModel describes a named list of actions.
+type PermissionSetModel struct {
+	Name    types.String   `tfsdk:"name"`
+	Actions []types.String `tfsdk:"actions"`
+}
+
+// RoleModel describes a role which grants permission sets to groups.
+type RoleModel struct {
+	Name           types.String   `tfsdk:"name"`
+	Groups         []types.String `tfsdk:"groups"`
+	PermissionSets []types.String `tfsdk:"permission_sets"`
+	ResourceIn     *EIDModel      `tfsdk:"resource_in"`
+	ResourceIs     types.String   `tfsdk:"resource_is"`
+}
+
+// OwnerGrantModel describes a grant to the owner of a resource.
+type OwnerGrantModel struct {
+	Name       types.String   `tfsdk:"name"`
+	Attribute  types.String   `tfsdk:"attribute"`
+	Actions    []types.String `tfsdk:"actions"`
+	ResourceIs types.String   `tfsdk:"resource_is"`
+}
+
+func (d *RBACDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rbac"
+}
+
+func (d *RBACDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates a Cedar PolicySet from roles, groups and permission sets.",
+		MarkdownDescription: `Generates a Cedar PolicySet from roles, groups and permission sets.
+
+Each 'role' grants the actions in its permission sets to the members of its groups. A policy is generated for each group of each role, in the form 'permit (principal in Group::"<group>", action in [...], resource)', with the ID '<role>.<group>' and a '@role' annotation. The resource scope can be limited with 'resource_in' or 'resource_is'.
+
+Each 'owner' grant generates a policy which permits the principal referred to by an attribute of the resource, such as 'resource.owner', to perform the grant's actions on that resource. The name of the grant is used as the policy ID.
+
+Actions are given by their ID and have the type 'Action'. Entity types are qualified with the 'namespace', or the provider's 'default_namespace'.
+`,
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "A namespace which is prepended to the group, action and resource types, so that 'Group' is rendered as 'CF::Group' in the 'CF' namespace. Overrides the provider's 'default_namespace'.",
+				Optional:            true,
+			},
+			"group_type": schema.StringAttribute{
+				MarkdownDescription: "The entity type of groups. Defaults to 'Group'.",
+				Optional:            true,
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "A Cedar schema in JSON format, or the path to a file containing one. If provided, the generated policies are validated against the schema. Overrides the provider's 'schema'.",
+				Optional:            true,
+			},
+			"permission_set": schema.ListNestedAttribute{
+				MarkdownDescription: "Named lists of actions which can be granted to roles.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the permission set.",
+							Required:            true,
+						},
+						"actions": schema.ListAttribute{
+							MarkdownDescription: "The IDs of the actions in the permission set, for example 'Read'.",
+							Required:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+			"role": schema.ListNestedAttribute{
+				MarkdownDescription: "Roles which grant permission sets to groups.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the role, used in the policy IDs and the '@role' annotation.",
+							Required:            true,
+						},
+						"groups": schema.ListAttribute{
+							MarkdownDescription: "The IDs of the groups which are members of the role.",
+							Required:            true,
+							ElementType:         types.StringType,
+						},
+						"permission_sets": schema.ListAttribute{
+							MarkdownDescription: "The names of the permission sets granted by the role.",
+							Required:            true,
+							ElementType:         types.StringType,
+						},
+						"resource_in": schema.SingleNestedAttribute{
+							MarkdownDescription: "Limits the role to resources in this entity. Equivalent to writing 'resource in'.",
+							Optional:            true,
+							Attributes:          eidDataSourceAttributes,
+						},
+						"resource_is": schema.StringAttribute{
+							MarkdownDescription: "Limits the role to resources of this type. Equivalent to writing 'resource is'.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"owner": schema.ListNestedAttribute{
+				MarkdownDescription: "Grants to the owner of a resource.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the grant, used as the policy ID.",
+							Required:            true,
+						},
+						"attribute": schema.StringAttribute{
+							MarkdownDescription: "The attribute of the resource which refers to its owner. Defaults to 'owner'.",
+							Optional:            true,
+						},
+						"actions": schema.ListAttribute{
+							MarkdownDescription: "The IDs of the actions the owner may perform. If not provided, the owner may perform any action.",
+							Optional:            true,
+							ElementType:         types.StringType,
+						},
+						"resource_is": schema.StringAttribute{
+							MarkdownDescription: "Limits the grant to resources of this type.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"text": schema.StringAttribute{
+				MarkdownDescription: "The generated Cedar PolicySet, rendered as a string.",
+				Computed:            true,
+			},
+			"json": schema.StringAttribute{
+				MarkdownDescription: "The generated Cedar PolicySet, rendered in the Cedar JSON policy set format with each policy keyed by its ID.",
+				Computed:            true,
+			},
+			"policies": schema.ListNestedAttribute{
+				MarkdownDescription: "Each generated policy, rendered separately.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the policy.",
+							Computed:            true,
+						},
+						"text": schema.StringAttribute{
+							MarkdownDescription: "The policy, rendered as a string.",
+							Computed:            true,
+						},
+						"hash": schema.StringAttribute{
+							MarkdownDescription: "A hash of the policy which only changes when the meaning of the policy changes.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RBACDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.provider = providerData
+}
+
+func (d *RBACDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RBACDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rbac, err := data.rbac()
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create data source: Cedar RBAC", err.Error())
+		return
+	}
+
+	policies, err := rbac.Policies()
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create data source: Cedar RBAC", err.Error())
+		return
+	}
+
+	namespace := d.provider.defaultNamespace()
+	if !data.Namespace.IsNull() {
+		namespace = data.Namespace.ValueString()
+	}
+	policies = withNamespace(policies, namespace)
+
+	schema, err := d.provider.schema(data.Schema.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create data source: Cedar RBAC", err.Error())
+		return
+	}
+	if schema != nil {
+		for i, policy := range policies {
+			for _, err := range schema.ValidatePolicy(policy) {
+				resp.Diagnostics.AddError(
+					"Unable to Create data source: Cedar RBAC",
+					fmt.Sprintf("Policy %q is not valid for the schema: %s", policy.ID(i), err),
+				)
+			}
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	text, err := d.provider.renderPolicySet(policies)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create data source: Cedar RBAC", err.Error())
+		return
+	}
+	data.Text = types.StringValue(text)
+
+	policySetJSON, err := cedarpolicy.RenderPolicySetJSON(policies)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create data source: Cedar RBAC", "Unable to render the PolicySet as JSON: "+err.Error())
+		return
+	}
+	data.JSON = types.StringValue(string(policySetJSON))
+
+	data.Rendered = make([]RenderedPolicyModel, len(policies))
+	for i, policy := range policies {
+		rendered, err := d.provider.renderPolicySet(policies[i : i+1])
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Create data source: Cedar RBAC", err.Error())
+			return
+		}
+		hash, err := policy.SemanticHash()
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Create data source: Cedar RBAC", err.Error())
+			return
+		}
+		data.Rendered[i] = RenderedPolicyModel{
+			ID:   types.StringValue(policy.ID(i)),
+			Text: types.StringValue(strings.TrimSuffix(rendered, "\n")),
+			Hash: types.StringValue(hash),
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// rbac converts the data source configuration into RBAC grants.
+func (m RBACDataSourceModel) rbac() (cedarpolicy.RBAC, error) {
+	rbac := cedarpolicy.RBAC{GroupType: m.GroupType.ValueString()}
+
+	for _, set := range m.PermissionSets {
+		rbac.PermissionSets = append(rbac.PermissionSets, cedarpolicy.PermissionSet{
+			Name:    set.Name.ValueString(),
+			Actions: stringValues(set.Actions),
+		})
+	}
+
+	for _, role := range m.Roles {
+		r := cedarpolicy.Role{
+			Name:           role.Name.ValueString(),
+			Groups:         stringValues(role.Groups),
+			PermissionSets: stringValues(role.PermissionSets),
+			ResourceIs:     role.ResourceIs.ValueString(),
+		}
+		if role.ResourceIn != nil {
+			resolved, err := role.ResourceIn.EID()
+			if err != nil {
+				return rbac, fmt.Errorf("role %q: resource_in: %w", r.Name, err)
+			}
+			r.ResourceIn = &cedarpolicy.EntityUID{Type: resolved.Type, ID: resolved.ID}
+		}
+		rbac.Roles = append(rbac.Roles, r)
+	}
+
+	for _, owner := range m.Owners {
+		attribute := owner.Attribute.ValueString()
+		if owner.Attribute.IsNull() {
+			attribute = "owner"
+		}
+		rbac.Owners = append(rbac.Owners, cedarpolicy.OwnerGrant{
+			Name:       owner.Name.ValueString(),
+			Attribute:  attribute,
+			Actions:    stringValues(owner.Actions),
+			ResourceIs: owner.ResourceIs.ValueString(),
+		})
+	}
+
+	return rbac, nil
+}
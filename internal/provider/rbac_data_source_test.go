@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestRBACDataSource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "cedar_rbac" "test" {
+					namespace = "CF"
+
+					permission_set = [
+						{ name = "viewer", actions = ["Read", "List"] },
+					]
+
+					role = [
+						{
+							name            = "viewers"
+							groups          = ["engineering"]
+							permission_sets = ["viewer"]
+							resource_in     = { type = "Folder", id = "shared" }
+						},
+					]
+
+					owner = [
+						{ name = "owner", resource_is = "Document" },
+					]
+				}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cedar_rbac.test", "policies.#", "2"),
+					resource.TestCheckResourceAttr("data.cedar_rbac.test", "policies.0.id", "viewers.engineering"),
+					resource.TestCheckResourceAttr("data.cedar_rbac.test", "policies.1.id", "owner"),
+					resource.TestCheckResourceAttr("data.cedar_rbac.test", "text", "@id(\"viewers.engineering\")\n@role(\"viewers\")\npermit (\n\tprincipal in CF::Group::\"engineering\",\n\taction in [CF::Action::\"Read\", CF::Action::\"List\"],\n\tresource in CF::Folder::\"shared\"\n);\n\n@id(\"owner\")\npermit (\n\tprincipal,\n\taction,\n\tresource is CF::Document\n)\nwhen {\n\tresource has owner && resource.owner == principal\n};\n"),
+				),
+			},
+			{
+				Config: `
+				data "cedar_rbac" "test" {
+					role = [
+						{ name = "viewers", groups = ["engineering"], permission_sets = ["viewer"] },
+					]
+				}
+				`,
+				ExpectError: regexp.MustCompile(`unknown permission set "viewer"`),
+			},
+		},
+	})
+}
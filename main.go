@@ -4,7 +4,9 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
+	"github.com/common-fate/terraform-provider-cedar/internal/cli"
 	"github.com/common-fate/terraform-provider-cedar/internal/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
@@ -29,6 +31,12 @@ var (
 )
 
 func main() {
+	// The binary can also be used as a command line tool to render, validate,
+	// format and evaluate policies without a Terraform configuration.
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -0,0 +1,291 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// FindingKind describes the type of issue found by Analyze.
+type FindingKind string
+
+const (
+	// FindingRedundant indicates that every request matched by a policy
+	// is also matched by another policy with the same effect.
+	FindingRedundant FindingKind = "redundant"
+
+	// FindingOverridden indicates that a 'permit' policy can never grant
+	// access, because an unconditional 'forbid' policy matches every
+	// request that it matches.
+	FindingOverridden FindingKind = "overridden"
+)
+
+// Finding is an issue found when analyzing a policy set.
+type Finding struct {
+	Kind FindingKind
+
+	// Index is the position of the affected policy in the policy set.
+	Index int
+	// PolicyID is the ID of the affected policy.
+	PolicyID string
+
+	// ByIndex is the position of the policy which covers the affected policy.
+	ByIndex int
+	// ByPolicyID is the ID of the policy which covers the affected policy.
+	ByPolicyID string
+}
+
+// Message returns a human-readable description of the finding.
+func (f Finding) Message() string {
+	switch f.Kind {
+	case FindingOverridden:
+		return fmt.Sprintf("permit policy %q is overridden by unconditional forbid policy %q and will never grant access", f.PolicyID, f.ByPolicyID)
+	default:
+		return fmt.Sprintf("policy %q is redundant as it is fully covered by policy %q", f.PolicyID, f.ByPolicyID)
+	}
+}
+
+// Analyze performs a scope-level subsumption analysis of a policy set.
+//
+// A policy is reported as redundant if another policy with the same effect
+// matches a superset of its scope, and the other policy's conditions are a
+// subset of its own conditions. Policies with identical scopes and conditions
+// are reported once, against the earliest matching policy.
+//
+// A permit policy is reported as overridden if a forbid policy without any
+// conditions matches a superset of its scope.
+//
+// The analysis does not consider the entity hierarchy, so 'principal in Group::"eng"'
+// is only known to be covered by scopes such as 'principal in Group::"eng"' or 'principal'.
+func Analyze(policies []Policy) []Finding {
+	var findings []Finding
+
+	for i, p := range policies {
+		// overridden permits are reported in preference to redundant ones,
+		// as a redundant permit which is also overridden is most likely a mistake.
+		if p.Effect == "permit" {
+			if j, ok := findCovering(policies, i, overrides); ok {
+				findings = append(findings, newFinding(FindingOverridden, policies, i, j))
+				continue
+			}
+		}
+
+		if j, ok := findCovering(policies, i, subsumes); ok {
+			findings = append(findings, newFinding(FindingRedundant, policies, i, j))
+		}
+	}
+
+	return findings
+}
+
+func newFinding(kind FindingKind, policies []Policy, i, j int) Finding {
+	return Finding{
+		Kind:       kind,
+		Index:      i,
+		PolicyID:   policies[i].ID(i),
+		ByIndex:    j,
+		ByPolicyID: policies[j].ID(j),
+	}
+}
+
+// overrides returns true if q is an unconditional forbid policy covering the scope of p.
+func overrides(q, p Policy) bool {
+	return q.Effect == "forbid" && len(q.When) == 0 && len(q.Unless) == 0 && scopeCovers(q, p)
+}
+
+// subsumes returns true if q has the same effect as p, covers the scope of p,
+// and has no conditions other than those of p.
+func subsumes(q, p Policy) bool {
+	return q.Effect == p.Effect &&
+		conditionsSubset(q.When, p.When) &&
+		conditionsSubset(q.Unless, p.Unless) &&
+		scopeCovers(q, p)
+}
+
+// findCovering returns the index of the first policy other than policies[i]
+// which covers policies[i] according to the covers relation.
+func findCovering(policies []Policy, i int, covers func(q, p Policy) bool) (int, bool) {
+	p := policies[i]
+
+	for j, q := range policies {
+		if i == j || !covers(q, p) {
+			continue
+		}
+		// where two policies cover each other, only report the later one.
+		if j > i && covers(p, q) {
+			continue
+		}
+		return j, true
+	}
+
+	return 0, false
+}
+
+// scopeCovers returns true if every request matched by the scope of policy a
+// is also matched by the scope of policy b.
+func scopeCovers(b, a Policy) bool {
+	return b.principalScope().covers(a.principalScope()) &&
+		b.actionScope().covers(a.actionScope()) &&
+		b.resourceScope().covers(a.resourceScope())
+}
+
+// conditionsSubset returns true if every condition in sub is present in conditions.
+// Conditions are compared after normalizing whitespace.
+func conditionsSubset(sub, conditions []Condition) bool {
+	for _, s := range sub {
+		found := false
+		for _, c := range conditions {
+			if normalizeWhitespace(s.Text) == normalizeWhitespace(c.Text) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+type scopeOp int
+
+const (
+	scopeAny scopeOp = iota
+	scopeEq
+	scopeIn
+	scopeIs
+	// scopeSlot is a template slot, such as 'principal == ?principal'.
+	scopeSlot
+)
+
+// scopeConstraint is a normalized representation of a principal, action
+// or resource constraint in a policy scope.
+type scopeConstraint struct {
+	op scopeOp
+	// entities holds the entity UIDs for 'scopeEq' and 'scopeIn' constraints,
+	// and the entity of 'is ... in ...' constraints.
+	entities []EntityUID
+	// typ holds the entity type for 'scopeIs' constraints.
+	typ string
+	// slotOp holds the operator, either '==' or 'in', for 'scopeSlot' constraints.
+	slotOp string
+}
+
+func newEntityConstraint(op scopeOp, entities ...eid.EID) scopeConstraint {
+	c := scopeConstraint{op: op}
+	for _, e := range entities {
+		c.entities = append(c.entities, EntityUID{Type: e.Type, ID: e.ID})
+	}
+	return c
+}
+
+// newIsConstraint returns an 'is' constraint, which is also limited to
+// descendants of the entity if in is set.
+func newIsConstraint(typ string, in *eid.EID) scopeConstraint {
+	if in == nil {
+		return scopeConstraint{op: scopeIs, typ: typ}
+	}
+	c := newEntityConstraint(scopeIs, *in)
+	c.typ = typ
+	return c
+}
+
+func (p Policy) principalScope() scopeConstraint {
+	switch {
+	case p.PrincipalSlot != "":
+		return scopeConstraint{op: scopeSlot, slotOp: p.PrincipalSlot}
+	case p.Principal != nil:
+		return newEntityConstraint(scopeEq, *p.Principal)
+	case p.PrincipalIn != nil:
+		return newEntityConstraint(scopeIn, *p.PrincipalIn)
+	case p.PrincipalIs != "":
+		return newIsConstraint(p.PrincipalIs, p.PrincipalIsIn)
+	}
+	return scopeConstraint{op: scopeAny}
+}
+
+func (p Policy) actionScope() scopeConstraint {
+	switch {
+	case p.Action != nil:
+		return newEntityConstraint(scopeEq, *p.Action)
+	case p.ActionIn != nil:
+		return newEntityConstraint(scopeIn, p.ActionIn...)
+	}
+	return scopeConstraint{op: scopeAny}
+}
+
+func (p Policy) resourceScope() scopeConstraint {
+	switch {
+	case p.ResourceSlot != "":
+		return scopeConstraint{op: scopeSlot, slotOp: p.ResourceSlot}
+	case p.Resource != nil:
+		return newEntityConstraint(scopeEq, *p.Resource)
+	case p.ResourceIn != nil:
+		return newEntityConstraint(scopeIn, *p.ResourceIn)
+	case p.ResourceIs != "":
+		return newIsConstraint(p.ResourceIs, p.ResourceIsIn)
+	}
+	return scopeConstraint{op: scopeAny}
+}
+
+// covers returns true if every entity matched by constraint a is also matched by c.
+func (c scopeConstraint) covers(a scopeConstraint) bool {
+	switch c.op {
+	case scopeAny:
+		return true
+
+	case scopeEq:
+		return a.op == scopeEq && a.entities[0] == c.entities[0]
+
+	case scopeIn:
+		// 'principal is T in X' is covered by 'principal in X'.
+		if a.op == scopeIs {
+			return len(a.entities) == 1 && contains(c.entities, a.entities[0])
+		}
+		// 'in' is reflexive, so 'principal == X' is covered by 'principal in X'.
+		if a.op != scopeEq && a.op != scopeIn {
+			return false
+		}
+		for _, e := range a.entities {
+			if !contains(c.entities, e) {
+				return false
+			}
+		}
+		return true
+
+	case scopeIs:
+		// 'principal is T in X' covers only the same constraint, or
+		// 'principal == X' as 'in' is reflexive.
+		if len(c.entities) > 0 {
+			switch a.op {
+			case scopeIs:
+				return a.typ == c.typ && len(a.entities) == 1 && a.entities[0] == c.entities[0]
+			case scopeEq:
+				return a.entities[0] == c.entities[0] && a.entities[0].Type == c.typ
+			}
+			return false
+		}
+		switch a.op {
+		case scopeIs:
+			return a.typ == c.typ
+		case scopeEq:
+			return a.entities[0].Type == c.typ
+		}
+	}
+
+	return false
+}
+
+func contains(list []EntityUID, uid EntityUID) bool {
+	for _, item := range list {
+		if item == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	allowAll := Policy{
+		Effect:       "permit",
+		AnyPrincipal: true,
+		Action: &eid.EID{
+			Type: "Action",
+			ID:   "Read",
+		},
+		AnyResource: true,
+	}
+
+	groupRead := Policy{
+		Effect: "permit",
+		PrincipalIn: &eid.EID{
+			Type: "Group",
+			ID:   "eng",
+		},
+		Action: &eid.EID{
+			Type: "Action",
+			ID:   "Read",
+		},
+		ResourceIs: "Document",
+	}
+
+	groupReadWrite := Policy{
+		Effect: "permit",
+		PrincipalIn: &eid.EID{
+			Type: "Group",
+			ID:   "eng",
+		},
+		ActionIn: []eid.EID{
+			{
+				Type: "Action",
+				ID:   "Read",
+			},
+			{
+				Type: "Action",
+				ID:   "Write",
+			},
+		},
+		AnyResource: true,
+	}
+
+	conditionalForbid := Policy{
+		Effect:       "forbid",
+		AnyPrincipal: true,
+		AnyAction:    true,
+		AnyResource:  true,
+		When: []Condition{
+			{Text: "context.locked"},
+		},
+	}
+
+	forbidAll := Policy{
+		Effect:       "forbid",
+		Annotations:  []Annotation{{Name: "id", Value: "lockdown"}},
+		AnyPrincipal: true,
+		AnyAction:    true,
+		AnyResource:  true,
+	}
+
+	tests := []struct {
+		name     string
+		policies []Policy
+		want     []Finding
+	}{
+		{
+			name:     "no_findings",
+			policies: []Policy{groupRead, conditionalForbid},
+		},
+		{
+			name:     "covered_by_any_principal",
+			policies: []Policy{allowAll, groupRead},
+			want: []Finding{
+				{Kind: FindingRedundant, Index: 1, PolicyID: "policy1", ByIndex: 0, ByPolicyID: "policy0"},
+			},
+		},
+		{
+			name:     "covered_by_action_list",
+			policies: []Policy{groupRead, groupReadWrite},
+			want: []Finding{
+				{Kind: FindingRedundant, Index: 0, PolicyID: "policy0", ByIndex: 1, ByPolicyID: "policy1"},
+			},
+		},
+		{
+			name:     "duplicates_reported_once",
+			policies: []Policy{groupRead, groupRead},
+			want: []Finding{
+				{Kind: FindingRedundant, Index: 1, PolicyID: "policy1", ByIndex: 0, ByPolicyID: "policy0"},
+			},
+		},
+		{
+			name: "conditions_must_be_a_subset",
+			policies: []Policy{
+				{
+					Effect:       "permit",
+					AnyPrincipal: true,
+					AnyAction:    true,
+					AnyResource:  true,
+					When:         []Condition{{Text: "resource.public"}},
+				},
+				groupRead,
+				{
+					Effect:      "permit",
+					PrincipalIs: "User",
+					AnyAction:   true,
+					AnyResource: true,
+					When: []Condition{
+						{Text: "resource.public "},
+						{Text: "context.mfa"},
+					},
+				},
+			},
+			want: []Finding{
+				{Kind: FindingRedundant, Index: 2, PolicyID: "policy2", ByIndex: 0, ByPolicyID: "policy0"},
+			},
+		},
+		{
+			// 'principal is User in Group::"eng"' is covered by 'principal in Group::"eng"',
+			// but not by an 'is' scope for another group.
+			name: "is_in_scope",
+			policies: []Policy{
+				groupRead,
+				{
+					Effect:        "permit",
+					PrincipalIs:   "User",
+					PrincipalIsIn: &eid.EID{Type: "Group", ID: "eng"},
+					Action:        &eid.EID{Type: "Action", ID: "Read"},
+					ResourceIs:    "Document",
+				},
+				{
+					Effect:        "permit",
+					PrincipalIs:   "User",
+					PrincipalIsIn: &eid.EID{Type: "Group", ID: "ops"},
+					Action:        &eid.EID{Type: "Action", ID: "Read"},
+					ResourceIs:    "Document",
+				},
+			},
+			want: []Finding{
+				{Kind: FindingRedundant, Index: 1, PolicyID: "policy1", ByIndex: 0, ByPolicyID: "policy0"},
+			},
+		},
+		{
+			name:     "overridden_by_unconditional_forbid",
+			policies: []Policy{groupRead, conditionalForbid, forbidAll},
+			want: []Finding{
+				{Kind: FindingOverridden, Index: 0, PolicyID: "policy0", ByIndex: 2, ByPolicyID: "lockdown"},
+				{Kind: FindingRedundant, Index: 1, PolicyID: "policy1", ByIndex: 2, ByPolicyID: "lockdown"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyze(tt.policies)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package cedarpolicy
+
+import (
+	"fmt"
+)
+
+// Decision is the result of an authorization request.
+type Decision string
+
+const (
+	Allow Decision = "allow"
+	Deny  Decision = "deny"
+)
+
+// PolicyError describes a policy which could not be evaluated for a request.
+// Policies which produce an error are ignored when making the decision.
+type PolicyError struct {
+	PolicyID string
+	Message  string
+}
+
+// Response is the result of evaluating a policy set for a request.
+type Response struct {
+	Decision Decision
+
+	// Reasons contains the IDs of the policies which determined the decision:
+	// the satisfied permit policies for an 'allow' decision, or the satisfied
+	// forbid policies for a 'deny' decision. Reasons is empty if the request
+	// was denied because no permit policies were satisfied.
+	Reasons []string
+
+	Errors []PolicyError
+}
+
+type compiledPolicy struct {
+	id     string
+	policy Policy
+	when   []Expr
+	unless []Expr
+}
+
+// Authorizer evaluates authorization requests against a policy set.
+type Authorizer struct {
+	policies []compiledPolicy
+}
+
+// NewAuthorizer parses the conditions of the policies and returns an Authorizer
+// which evaluates requests against them. It returns an error if a condition
+// uses a method which is not supported in evaluation, such as the datetime
+// and duration methods or the entity tag methods.
+func NewAuthorizer(policies []Policy) (*Authorizer, error) {
+	a := &Authorizer{}
+
+	for i, p := range policies {
+		c := compiledPolicy{id: p.ID(i), policy: p}
+
+		for j, when := range p.When {
+			expr, err := ParseExpr(when.Text)
+			if err == nil {
+				err = checkSupported(expr)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("policy %q: when condition index %v: %w", c.id, j, err)
+			}
+			c.when = append(c.when, expr)
+		}
+
+		for j, unless := range p.Unless {
+			expr, err := ParseExpr(unless.Text)
+			if err == nil {
+				err = checkSupported(expr)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("policy %q: unless condition index %v: %w", c.id, j, err)
+			}
+			c.unless = append(c.unless, expr)
+		}
+
+		a.policies = append(a.policies, c)
+	}
+
+	return a, nil
+}
+
+// IsAuthorized evaluates a request. The request is allowed if at least one
+// permit policy is satisfied and no forbid policies are satisfied.
+func (a *Authorizer) IsAuthorized(entities Entities, req Request) Response {
+	var permits, forbids []string
+	var errs []PolicyError
+
+	for _, c := range a.policies {
+		if !c.policy.scopeMatches(entities, req) {
+			continue
+		}
+
+		satisfied, err := c.conditionsSatisfied(entities, req)
+		if err != nil {
+			errs = append(errs, PolicyError{PolicyID: c.id, Message: err.Error()})
+			continue
+		}
+		if !satisfied {
+			continue
+		}
+
+		if c.policy.Effect == "forbid" {
+			forbids = append(forbids, c.id)
+		} else {
+			permits = append(permits, c.id)
+		}
+	}
+
+	if len(forbids) > 0 {
+		return Response{Decision: Deny, Reasons: forbids, Errors: errs}
+	}
+	if len(permits) > 0 {
+		return Response{Decision: Allow, Reasons: permits, Errors: errs}
+	}
+	return Response{Decision: Deny, Errors: errs}
+}
+
+func (c compiledPolicy) conditionsSatisfied(entities Entities, req Request) (bool, error) {
+	e := evaluator{entities: entities, req: req}
+
+	for _, when := range c.when {
+		ok, err := e.evalBool(when)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	for _, unless := range c.unless {
+		ok, err := e.evalBool(unless)
+		if err != nil || ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// scopeMatches returns true if the request matches the policy scope.
+func (p Policy) scopeMatches(entities Entities, req Request) bool {
+	return p.principalScope().matches(entities, req.Principal) &&
+		p.actionScope().matches(entities, req.Action) &&
+		p.resourceScope().matches(entities, req.Resource)
+}
+
+func (c scopeConstraint) matches(entities Entities, uid EntityUID) bool {
+	switch c.op {
+	case scopeAny:
+		return true
+
+	case scopeIs:
+		if len(c.entities) > 0 && !entities.In(uid, c.entities[0]) {
+			return false
+		}
+		return uid.Type == c.typ
+
+	case scopeEq, scopeIn:
+		for _, target := range c.entities {
+			if c.op == scopeEq && uid == target {
+				return true
+			}
+			if c.op == scopeIn && entities.In(uid, target) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
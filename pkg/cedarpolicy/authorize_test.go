@@ -0,0 +1,279 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testEntities = `[
+	{"uid": {"type": "User", "id": "alice"}, "attrs": {"level": 5}, "parents": [{"type": "Group", "id": "eng"}]},
+	{"uid": {"type": "User", "id": "bob"}, "attrs": {"level": 1}, "parents": []},
+	{"uid": {"type": "Group", "id": "eng"}, "parents": [{"type": "Group", "id": "all"}]},
+	{"uid": {"type": "Group", "id": "all"}},
+	{"uid": {"type": "Document", "id": "plan"}, "attrs": {"owner": {"__entity": {"type": "User", "id": "bob"}}, "tags": ["internal"]}},
+	{"uid": {"type": "Action", "id": "Read"}},
+	{"uid": {"type": "Action", "id": "Delete"}}
+]`
+
+const testPolicies = `
+@id("all-read")
+permit (principal in Group::"all", action == Action::"Read", resource)
+when { resource.tags.contains("internal") };
+
+@id("owner")
+permit (principal, action, resource is Document)
+when { resource.owner == principal };
+
+@id("no-delete")
+forbid (principal, action == Action::"Delete", resource)
+unless { principal.level > 3 };
+`
+
+func TestIsAuthorized(t *testing.T) {
+	entities, err := ParseEntities([]byte(testEntities))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies, err := ParsePolicySet(testPolicies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizer, err := NewAuthorizer(policies)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := func(id string) EntityUID { return EntityUID{Type: "User", ID: id} }
+	action := func(id string) EntityUID { return EntityUID{Type: "Action", ID: id} }
+	plan := EntityUID{Type: "Document", ID: "plan"}
+
+	tests := []struct {
+		name string
+		req  Request
+		want Response
+	}{
+		{
+			name: "group_member_can_read",
+			req:  Request{Principal: user("alice"), Action: action("Read"), Resource: plan},
+			want: Response{Decision: Allow, Reasons: []string{"all-read"}},
+		},
+		{
+			name: "owner_can_read",
+			req:  Request{Principal: user("bob"), Action: action("Read"), Resource: plan},
+			want: Response{Decision: Allow, Reasons: []string{"owner"}},
+		},
+		{
+			name: "owner_cannot_delete",
+			req:  Request{Principal: user("bob"), Action: action("Delete"), Resource: plan},
+			want: Response{Decision: Deny, Reasons: []string{"no-delete"}},
+		},
+		{
+			name: "errors_are_reported",
+			req:  Request{Principal: user("carol"), Action: action("Delete"), Resource: plan},
+			want: Response{Decision: Deny, Errors: []PolicyError{
+				{PolicyID: "no-delete", Message: `entity User::"carol" does not exist`},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := authorizer.IsAuthorized(entities, tt.req)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsAuthorized_IsIn(t *testing.T) {
+	entities, err := ParseEntities([]byte(testEntities))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies, err := ParsePolicySet(`@id("users") permit (principal is User in Group::"all", action, resource);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizer, err := NewAuthorizer(policies)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		principal EntityUID
+		want      Decision
+	}{
+		{EntityUID{Type: "User", ID: "alice"}, Allow},
+		// bob is a user, but not a member of the group.
+		{EntityUID{Type: "User", ID: "bob"}, Deny},
+		// the group is a member of the group, but isn't a user.
+		{EntityUID{Type: "Group", ID: "eng"}, Deny},
+	} {
+		resp := authorizer.IsAuthorized(entities, Request{
+			Principal: tt.principal,
+			Action:    EntityUID{Type: "Action", ID: "Read"},
+			Resource:  EntityUID{Type: "Document", ID: "plan"},
+		})
+		assert.Equal(t, tt.want, resp.Decision, tt.principal.String())
+	}
+}
+
+func TestNewAuthorizer_UnsupportedMethods(t *testing.T) {
+	for _, cond := range []string{
+		`context.now.toDate() == datetime("2024-10-15")`,
+		`context.now.durationSince(datetime("2024-10-15")) > duration("1d")`,
+		`resource.hasTag("owner") && resource.getTag("owner") == principal`,
+	} {
+		_, err := NewAuthorizer([]Policy{{
+			Effect:       "permit",
+			AnyPrincipal: true,
+			AnyAction:    true,
+			AnyResource:  true,
+			When:         []Condition{{Text: cond}},
+		}})
+		assert.ErrorContains(t, err, `policy "policy0": when condition index 0: method`, cond)
+		assert.ErrorContains(t, err, "is not supported in evaluation", cond)
+	}
+
+	// methods are also reported when an expression is evaluated directly.
+	expr, err := ParseExpr(`context.d.toHours() > 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Evaluate(expr, nil, Request{Context: Record{"d": Duration(3600000)}})
+	assert.EqualError(t, err, `method "toHours" is not supported in evaluation`)
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    Value
+		wantErr string
+	}{
+		{expr: `[1, 2] == [2, 1, 1]`, want: Bool(true)},
+		{expr: `"a*b" like "a\*b" && "axxb" like "a*b"`, want: Bool(true)},
+		{expr: `ip("10.1.2.3").isInRange(ip("10.0.0.0/8"))`, want: Bool(true)},
+		{expr: `decimal("1.5").lessThan(decimal("1.55"))`, want: Bool(true)},
+		{expr: `if context has x then context.x else 2 * 3 - 1`, want: Long(5)},
+		{expr: `9223372036854775807 + 1`, wantErr: "integer overflow when adding 9223372036854775807 and 1"},
+		// invalid literal arguments are reported by the parser, so only
+		// arguments which are evaluated can fail at evaluation time.
+		{expr: `ip(if true then "10.0.0.300" else "")`, wantErr: `invalid IP address "10.0.0.300"`},
+		{expr: `decimal(if true then "1.23456" else "")`, wantErr: `invalid decimal "1.23456": must have between one and four digits after the decimal point`},
+		{expr: `1 < "a"`, wantErr: "'<' expects long operands, got long and string"},
+		{expr: `datetime("2024-10-15T11:35:00+0100") < datetime("2024-10-15T11:00:00Z")`, want: Bool(true)},
+		{expr: `datetime("2024-10-15") == datetime("2024-10-15T00:00:00.000Z")`, want: Bool(true)},
+		{expr: `datetime(if true then "2024-10-15T11:35:00+01:00" else "")`, wantErr: `invalid datetime "2024-10-15T11:35:00+01:00": expected a date such as '2024-10-15', or a date and time such as '2024-10-15T11:35:00Z' or '2024-10-15T11:35:00.000+0100'`},
+		{expr: `datetime("2024-10-15") < 1`, wantErr: "'<' expects datetime operands, got datetime and long"},
+		{expr: `duration("1h30m") < duration("1d") && duration("-1ms") < duration("0ms")`, want: Bool(true)},
+		{expr: `duration("90m") == duration("1h30m")`, want: Bool(true)},
+		{expr: `duration(if true then "1m1h" else "")`, wantErr: `invalid duration "1m1h": each number must be followed by one of the units 'd', 'h', 'm', 's' or 'ms', in that order`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseExpr(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Evaluate(expr, Entities{}, Request{})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPermissionMatrix(t *testing.T) {
+	entities, err := ParseEntities([]byte(testEntities))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies, err := ParsePolicySet(testPolicies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizer, err := NewAuthorizer(policies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := ParseSchema([]byte(`{
+		"": {
+			"entityTypes": {"User": {"memberOfTypes": ["Group"]}, "Group": {}, "Document": {}},
+			"actions": {"Read": {"appliesTo": {"principalTypes": ["User"], "resourceTypes": ["Document"]}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, skipped := PermissionMatrix(authorizer, entities, MatrixOptions{Schema: schema})
+	assert.Empty(t, skipped)
+
+	csv, err := RenderMatrixCSV(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `principal,action,resource,decision,policies
+"User::""alice""","Action::""Read""","Document::""plan""",allow,all-read
+"User::""bob""","Action::""Read""","Document::""plan""",allow,owner
+`, csv)
+
+	assert.Equal(t, "| principal | action | resource | decision | policies |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `User::\"alice\"` | `Action::\"Read\"` | `Document::\"plan\"` | allow | all-read |\n"+
+		"| `User::\"bob\"` | `Action::\"Read\"` | `Document::\"plan\"` | allow | owner |\n",
+		RenderMatrixMarkdown(entries))
+}
+
+func TestPermissionMatrix_ActionGroups(t *testing.T) {
+	entities, err := ParseEntities([]byte(testEntities))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies, err := ParsePolicySet(`@id("read-only")
+permit (principal, action in Action::"ReadOnly", resource);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizer, err := NewAuthorizer(policies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := ParseSchema([]byte(`{
+		"": {
+			"entityTypes": {"User": {}, "Document": {}},
+			"actions": {
+				"ReadOnly": {},
+				"Read": {"memberOf": [{"id": "ReadOnly"}], "appliesTo": {"principalTypes": ["User"], "resourceTypes": ["Document"]}},
+				"Delete": {"appliesTo": {"principalTypes": ["User"], "resourceTypes": ["Document"]}},
+				"Archive": {}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, skipped := PermissionMatrix(authorizer, entities, MatrixOptions{Schema: schema, PrincipalTypes: []string{"User"}})
+
+	// actions without 'appliesTo' are skipped, but action groups are not reported.
+	assert.Equal(t, []EntityUID{{Type: "Action", ID: "Archive"}}, skipped)
+
+	decisions := map[string]Decision{}
+	for _, e := range entries {
+		decisions[e.Principal.String()+" "+e.Action.String()] = e.Decision
+	}
+	assert.Equal(t, map[string]Decision{
+		`User::"alice" Action::"Delete"`: Deny,
+		`User::"alice" Action::"Read"`:   Allow,
+		`User::"bob" Action::"Delete"`:   Deny,
+		`User::"bob" Action::"Read"`:     Allow,
+	}, decisions)
+
+	// The entities passed in are not modified.
+	assert.Empty(t, entities[EntityUID{Type: "Action", ID: "Read"}].Parents)
+}
@@ -0,0 +1,299 @@
+package cedarpolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// scopeClause is a constraint on a variable in the policy scope.
+type scopeClause struct {
+	op       string
+	entities []eid.EID
+	typ      string
+}
+
+// Scope constrains the principal or resource of a policy.
+// It is created by Any, Eq, In or Is.
+type Scope interface {
+	entityClause() scopeClause
+}
+
+// ActionScope constrains the action of a policy.
+// It is created by Any, Eq, In or InList.
+type ActionScope interface {
+	actionClause() scopeClause
+}
+
+// Constraint constrains the principal, action or resource of a policy.
+type Constraint interface {
+	Scope
+	ActionScope
+}
+
+type anyScope struct{}
+
+func (anyScope) entityClause() scopeClause { return scopeClause{op: "any"} }
+func (anyScope) actionClause() scopeClause { return scopeClause{op: "any"} }
+
+type eqScope struct{ entity eid.EID }
+
+func (s eqScope) entityClause() scopeClause {
+	return scopeClause{op: "==", entities: []eid.EID{s.entity}}
+}
+func (s eqScope) actionClause() scopeClause {
+	return scopeClause{op: "==", entities: []eid.EID{s.entity}}
+}
+
+type inScope struct{ entity eid.EID }
+
+func (s inScope) entityClause() scopeClause {
+	return scopeClause{op: "in", entities: []eid.EID{s.entity}}
+}
+func (s inScope) actionClause() scopeClause {
+	return scopeClause{op: "in", entities: []eid.EID{s.entity}}
+}
+
+type isScope struct{ typ string }
+
+func (s isScope) entityClause() scopeClause { return scopeClause{op: "is", typ: s.typ} }
+
+type inListScope struct{ entities []eid.EID }
+
+func (s inListScope) actionClause() scopeClause { return scopeClause{op: "in", entities: s.entities} }
+
+// Any matches any principal, action or resource.
+func Any() Constraint {
+	return anyScope{}
+}
+
+// Eq matches the entity, as in 'principal == User::"alice"'.
+func Eq(e eid.EID) Constraint {
+	return eqScope{entity: e}
+}
+
+// In matches the entity and its descendants, as in 'principal in Group::"eng"'.
+func In(e eid.EID) Constraint {
+	return inScope{entity: e}
+}
+
+// Is matches entities of the type, as in 'resource is Document'.
+// It can't be used for the action.
+func Is(typ string) Scope {
+	return isScope{typ: typ}
+}
+
+// InList matches any of the actions and their members, as in
+// 'action in [Action::"Read", Action::"Write"]'.
+// It can only be used for the action.
+func InList(actions ...eid.EID) ActionScope {
+	return inListScope{entities: actions}
+}
+
+// Builder builds a policy. It is created by Permit or Forbid, for example:
+//
+//	policy, err := Permit().
+//		Principal(In(eid.New("Group", "eng"))).
+//		Action(InList(eid.New("Action", "Read"), eid.New("Action", "Write"))).
+//		Resource(Is("Document")).
+//		When("resource.public").
+//		Build()
+//
+// Parts of the scope which are not constrained match anything. Mistakes such
+// as constraining the principal twice or invalid condition text are reported
+// by Build.
+type Builder struct {
+	effect      string
+	annotations []Annotation
+	principal   *scopeClause
+	action      *scopeClause
+	resource    *scopeClause
+	when        []Condition
+	unless      []Condition
+	errs        []error
+}
+
+// Permit returns a builder for a 'permit' policy.
+func Permit() *Builder {
+	return &Builder{effect: "permit"}
+}
+
+// Forbid returns a builder for a 'forbid' policy.
+func Forbid() *Builder {
+	return &Builder{effect: "forbid"}
+}
+
+// Annotate adds an annotation to the policy, such as '@id("admins")'.
+func (b *Builder) Annotate(name, value string) *Builder {
+	b.annotations = append(b.annotations, Annotation{Name: name, Value: value})
+	return b
+}
+
+// ID sets the '@id' annotation of the policy.
+func (b *Builder) ID(id string) *Builder {
+	return b.Annotate("id", id)
+}
+
+// Principal constrains the principal of the policy.
+func (b *Builder) Principal(s Scope) *Builder {
+	b.setScope("principal", &b.principal, s.entityClause())
+	return b
+}
+
+// Action constrains the action of the policy.
+func (b *Builder) Action(s ActionScope) *Builder {
+	b.setScope("action", &b.action, s.actionClause())
+	return b
+}
+
+// Resource constrains the resource of the policy.
+func (b *Builder) Resource(s Scope) *Builder {
+	b.setScope("resource", &b.resource, s.entityClause())
+	return b
+}
+
+// When adds a 'when' condition to the policy.
+func (b *Builder) When(text string) *Builder {
+	b.when = append(b.when, Condition{Text: text})
+	return b
+}
+
+// Unless adds an 'unless' condition to the policy.
+func (b *Builder) Unless(text string) *Builder {
+	b.unless = append(b.unless, Condition{Text: text})
+	return b
+}
+
+func (b *Builder) setScope(variable string, dst **scopeClause, clause scopeClause) {
+	if *dst != nil {
+		b.errs = append(b.errs, fmt.Errorf("%s: the %s is already constrained", variable, variable))
+		return
+	}
+	*dst = &clause
+}
+
+// Build returns the policy, or an error describing every
+// mistake in the policy if it is not valid.
+func (b *Builder) Build() (Policy, error) {
+	errs := append([]error{}, b.errs...)
+
+	p := Policy{Effect: b.effect}
+
+	names := map[string]bool{}
+	for _, anno := range b.annotations {
+		if !isIdentifier(anno.Name) {
+			errs = append(errs, fmt.Errorf("annotation: invalid name %q", anno.Name))
+		} else if names[anno.Name] {
+			errs = append(errs, fmt.Errorf("annotation: duplicate annotation %q", anno.Name))
+		}
+		if anno.Value == "" {
+			errs = append(errs, fmt.Errorf("annotation %q: the value must not be empty", anno.Name))
+		}
+		names[anno.Name] = true
+		p.Annotations = append(p.Annotations, anno)
+	}
+
+	for _, scope := range []struct {
+		variable string
+		clause   *scopeClause
+		any      *bool
+		eq, in   **eid.EID
+		is       *string
+	}{
+		{"principal", b.principal, &p.AnyPrincipal, &p.Principal, &p.PrincipalIn, &p.PrincipalIs},
+		{"resource", b.resource, &p.AnyResource, &p.Resource, &p.ResourceIn, &p.ResourceIs},
+	} {
+		if scope.clause == nil {
+			*scope.any = true
+			continue
+		}
+		errs = append(errs, checkScopeClause(scope.variable, *scope.clause)...)
+
+		switch scope.clause.op {
+		case "any":
+			*scope.any = true
+		case "==":
+			e := scope.clause.entities[0]
+			*scope.eq = &e
+		case "in":
+			e := scope.clause.entities[0]
+			*scope.in = &e
+		case "is":
+			*scope.is = scope.clause.typ
+		}
+	}
+
+	switch {
+	case b.action == nil || b.action.op == "any":
+		p.AnyAction = true
+	case b.action.op == "==":
+		errs = append(errs, checkScopeClause("action", *b.action)...)
+		e := b.action.entities[0]
+		p.Action = &e
+	default:
+		errs = append(errs, checkScopeClause("action", *b.action)...)
+		p.ActionIn = append([]eid.EID{}, b.action.entities...)
+	}
+
+	for _, c := range []struct {
+		keyword    string
+		conditions []Condition
+		dst        *[]Condition
+	}{{"when", b.when, &p.When}, {"unless", b.unless, &p.Unless}} {
+		for i, cond := range c.conditions {
+			if _, err := ParseExpr(cond.Text); err != nil {
+				errs = append(errs, fmt.Errorf("%s condition index %v: %w", c.keyword, i, err))
+			}
+			*c.dst = append(*c.dst, cond)
+		}
+	}
+
+	if len(errs) > 0 {
+		return Policy{}, errors.Join(errs...)
+	}
+	return p, nil
+}
+
+// RenderString builds the policy and renders it as text,
+// in the same way as Policy.RenderString.
+func (b *Builder) RenderString() (string, error) {
+	p, err := b.Build()
+	if err != nil {
+		return "", err
+	}
+	return p.RenderString()
+}
+
+// checkScopeClause returns an error for each invalid entity or entity type in the clause.
+func checkScopeClause(variable string, clause scopeClause) []error {
+	var errs []error
+	if clause.op == "is" && !isEntityType(clause.typ) {
+		errs = append(errs, fmt.Errorf("%s: invalid entity type %q", variable, clause.typ))
+	}
+	if variable == "action" && clause.op == "in" && len(clause.entities) == 0 {
+		errs = append(errs, errors.New("action: at least one action must be specified"))
+	}
+	for _, e := range clause.entities {
+		switch {
+		case !isEntityType(e.Type):
+			errs = append(errs, fmt.Errorf("%s: invalid entity type %q", variable, e.Type))
+		case e.ID == "":
+			errs = append(errs, fmt.Errorf("%s: entity %s must have an ID", variable, e))
+		case variable == "action" && !IsActionType(e.Type):
+			errs = append(errs, fmt.Errorf("action: entity type %q is not an action type, expected 'Action' or a namespaced type such as 'CF::Action'", e.Type))
+		}
+	}
+	return errs
+}
+
+// isEntityType returns true if typ is a valid entity type, such as 'User' or 'CF::User'.
+func isEntityType(typ string) bool {
+	for _, part := range strings.Split(typ, "::") {
+		if !isIdentifier(part) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		want    string
+	}{
+		{
+			name:    "allow_all",
+			builder: Permit(),
+			want:    "permit (\n\tprincipal,\n\taction,\n\tresource\n);",
+		},
+		{
+			name: "scope",
+			builder: Permit().
+				ID("eng-docs").
+				Principal(In(eid.New("Group", "eng"))).
+				Action(InList(eid.New("Action", "Read"), eid.New("Action", "Write"))).
+				Resource(Is("Doc")).
+				When("resource.public").
+				Unless(`resource.owner == User::"mallory"`),
+			want: `@id("eng-docs")
+permit (
+	principal in Group::"eng",
+	action in [Action::"Read", Action::"Write"],
+	resource is Doc
+)
+when {
+	resource.public
+}
+unless {
+	resource.owner == User::"mallory"
+};`,
+		},
+		{
+			name: "eq",
+			builder: Forbid().
+				Principal(Eq(eid.New("CF::User", "alice"))).
+				Action(Eq(eid.New("CF::Action", "Delete"))).
+				Resource(Any()),
+			want: "forbid (\n\tprincipal == CF::User::\"alice\",\n\taction == CF::Action::\"Delete\",\n\tresource\n);",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.RenderString()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			// the built policy is the same as the parsed text.
+			policy, err := tt.builder.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := ParsePolicy(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, parsed, policy)
+		})
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	_, err := Permit().
+		Annotate("id", "a").
+		Annotate("id", "b").
+		Principal(Eq(eid.New("User", ""))).
+		Principal(Any()).
+		Action(InList(eid.New("User", "alice"))).
+		Resource(Is("1Doc")).
+		When("resource.").
+		Build()
+
+	assert.EqualError(t, err, `principal: the principal is already constrained
+annotation: duplicate annotation "id"
+principal: entity User::"" must have an ID
+resource: invalid entity type "1Doc"
+action: entity type "User" is not an action type, expected 'Action' or a namespaced type such as 'CF::Action'
+when condition index 0: 1:10: expected identifier, got end of input`)
+
+	_, err = Permit().Action(InList()).Build()
+	assert.EqualError(t, err, "action: at least one action must be specified")
+}
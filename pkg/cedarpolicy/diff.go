@@ -0,0 +1,242 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind describes how a policy differs between two policy sets.
+type ChangeKind string
+
+const (
+	PolicyAdded    ChangeKind = "added"
+	PolicyRemoved  ChangeKind = "removed"
+	PolicyModified ChangeKind = "modified"
+)
+
+// FieldChange is a change to a single field of a policy, such as its
+// principal scope, an annotation, or a condition. Old is empty if the
+// field was added, and New is empty if the field was removed.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// PolicyChange describes a policy which was added, removed or modified.
+type PolicyChange struct {
+	Kind     ChangeKind
+	PolicyID string
+	// Fields contains the changed fields for modified policies.
+	Fields []FieldChange
+}
+
+type diffEntry struct {
+	id       string
+	explicit bool
+	fields   map[string]string
+	key      string
+	matched  bool
+}
+
+func newDiffEntries(policies []Policy) []*diffEntry {
+	entries := make([]*diffEntry, len(policies))
+	for i, p := range policies {
+		fields := p.fields()
+		_, explicit := p.explicitID()
+		entries[i] = &diffEntry{
+			id:       p.ID(i),
+			explicit: explicit,
+			fields:   fields,
+			key:      fieldsKey(fields),
+		}
+	}
+	return entries
+}
+
+// Diff compares two policy sets and returns the policies which were added,
+// removed or modified.
+//
+// Policies with an '@id' annotation are matched by ID. Policies without an ID
+// are matched by content, so that reordering or reformatting the policies is
+// not reported as a change. Any remaining policies without an ID are matched
+// by their position in the set.
+func Diff(oldPolicies, newPolicies []Policy) []PolicyChange {
+	oldEntries := newDiffEntries(oldPolicies)
+	newEntries := newDiffEntries(newPolicies)
+
+	var changes []PolicyChange
+
+	match := func(o, n *diffEntry) {
+		o.matched = true
+		n.matched = true
+		if fields := diffFields(o.fields, n.fields); len(fields) > 0 {
+			changes = append(changes, PolicyChange{Kind: PolicyModified, PolicyID: n.id, Fields: fields})
+		}
+	}
+
+	// match policies with explicit IDs.
+	for _, n := range newEntries {
+		for _, o := range oldEntries {
+			if n.explicit && o.explicit && !o.matched && o.id == n.id {
+				match(o, n)
+				break
+			}
+		}
+	}
+
+	// match unchanged policies without an ID by content.
+	for _, n := range newEntries {
+		for _, o := range oldEntries {
+			if !n.matched && !n.explicit && !o.matched && !o.explicit && o.key == n.key {
+				match(o, n)
+				break
+			}
+		}
+	}
+
+	// match the remaining policies without an ID by position.
+	for _, n := range newEntries {
+		for _, o := range oldEntries {
+			if !n.matched && !n.explicit && !o.matched && !o.explicit && o.id == n.id {
+				match(o, n)
+				break
+			}
+		}
+	}
+
+	for _, o := range oldEntries {
+		if !o.matched {
+			changes = append(changes, PolicyChange{Kind: PolicyRemoved, PolicyID: o.id})
+		}
+	}
+	for _, n := range newEntries {
+		if !n.matched {
+			changes = append(changes, PolicyChange{Kind: PolicyAdded, PolicyID: n.id})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changeOrder[changes[i].Kind] < changeOrder[changes[j].Kind]
+	})
+
+	return changes
+}
+
+var changeOrder = map[ChangeKind]int{
+	PolicyAdded:    0,
+	PolicyRemoved:  1,
+	PolicyModified: 2,
+}
+
+// DiffSummary renders a human-readable summary of the changes returned by Diff.
+func DiffSummary(changes []PolicyChange) string {
+	if len(changes) == 0 {
+		return "No changes.\n"
+	}
+
+	counts := map[ChangeKind]int{}
+	for _, c := range changes {
+		counts[c.Kind]++
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d added, %d removed, %d modified.\n\n", counts[PolicyAdded], counts[PolicyRemoved], counts[PolicyModified])
+
+	symbols := map[ChangeKind]string{PolicyAdded: "+", PolicyRemoved: "-", PolicyModified: "~"}
+	for _, c := range changes {
+		fmt.Fprintf(&b, "%s %s\n", symbols[c.Kind], c.PolicyID)
+		for _, f := range c.Fields {
+			switch {
+			case f.Old == "":
+				fmt.Fprintf(&b, "    %s: + %s\n", f.Field, f.New)
+			case f.New == "":
+				fmt.Fprintf(&b, "    %s: - %s\n", f.Field, f.Old)
+			default:
+				fmt.Fprintf(&b, "    %s: %s -> %s\n", f.Field, f.Old, f.New)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func diffFields(oldFields, newFields map[string]string) []FieldChange {
+	var changes []FieldChange
+	for _, name := range fieldNames(oldFields, newFields) {
+		if oldFields[name] != newFields[name] {
+			changes = append(changes, FieldChange{Field: name, Old: oldFields[name], New: newFields[name]})
+		}
+	}
+	return changes
+}
+
+// fieldNames returns the sorted union of the keys of the field maps.
+func fieldNames(maps ...map[string]string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				names = append(names, k)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func fieldsKey(fields map[string]string) string {
+	var b strings.Builder
+	for _, name := range fieldNames(fields) {
+		fmt.Fprintf(&b, "%s=%q;", name, fields[name])
+	}
+	return b.String()
+}
+
+// fields returns a normalized representation of the policy, keyed by field name.
+func (p Policy) fields() map[string]string {
+	fields := map[string]string{
+		"effect":    p.Effect,
+		"principal": p.principalScope().render("principal"),
+		"action":    p.actionScope().render("action"),
+		"resource":  p.resourceScope().render("resource"),
+	}
+	for _, a := range p.Annotations {
+		fields["annotation."+a.Name] = a.Value
+	}
+	for i, c := range p.When {
+		fields[fmt.Sprintf("when[%d]", i)] = normalizeWhitespace(c.Text)
+	}
+	for i, c := range p.Unless {
+		fields[fmt.Sprintf("unless[%d]", i)] = normalizeWhitespace(c.Text)
+	}
+	return fields
+}
+
+// render returns the Cedar text of the scope constraint.
+func (c scopeConstraint) render(variable string) string {
+	switch c.op {
+	case scopeEq:
+		return fmt.Sprintf("%s == %s", variable, c.entities[0])
+	case scopeIs:
+		if len(c.entities) > 0 {
+			return fmt.Sprintf("%s is %s in %s", variable, c.typ, c.entities[0])
+		}
+		return fmt.Sprintf("%s is %s", variable, c.typ)
+	case scopeSlot:
+		return fmt.Sprintf("%s %s ?%s", variable, c.slotOp, variable)
+	case scopeIn:
+		if variable != "action" {
+			return fmt.Sprintf("%s in %s", variable, c.entities[0])
+		}
+		uids := make([]string, len(c.entities))
+		for i, e := range c.entities {
+			uids[i] = e.String()
+		}
+		return fmt.Sprintf("%s in [%s]", variable, strings.Join(uids, ", "))
+	}
+	return variable
+}
@@ -0,0 +1,69 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	oldText := `
+@id("admins")
+permit (principal in Group::"admins", action, resource);
+
+permit (principal, action == Action::"Read", resource)
+when { resource.public };
+
+@id("legacy")
+forbid (principal, action, resource is Secret);
+`
+
+	newText := `
+permit (
+	principal,
+	action == Action::"Read",
+	resource
+)
+when {
+	resource.public
+};
+
+@id("admins")
+@advice("admins can do anything")
+permit (principal in Group::"admins", action, resource)
+unless { context.locked };
+
+permit (principal == User::"alice", action, resource);
+`
+
+	oldPolicies, err := ParsePolicySet(oldText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPolicies, err := ParsePolicySet(newText)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := Diff(oldPolicies, newPolicies)
+
+	assert.Equal(t, []PolicyChange{
+		{Kind: PolicyAdded, PolicyID: "policy2"},
+		{Kind: PolicyRemoved, PolicyID: "legacy"},
+		{Kind: PolicyModified, PolicyID: "admins", Fields: []FieldChange{
+			{Field: "annotation.advice", New: "admins can do anything"},
+			{Field: "unless[0]", New: "context.locked"},
+		}},
+	}, changes)
+
+	assert.Equal(t, `1 added, 1 removed, 1 modified.
+
++ policy2
+- legacy
+~ admins
+    annotation.advice: + admins can do anything
+    unless[0]: + context.locked
+`, DiffSummary(changes))
+
+	assert.Empty(t, Diff(oldPolicies, oldPolicies))
+}
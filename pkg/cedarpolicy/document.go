@@ -0,0 +1,343 @@
+package cedarpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// documentEID is an entity in a policy document, given either
+// as a type and ID or as a Cedar entity UID string.
+type documentEID struct {
+	Type string  `json:"type"`
+	ID   string  `json:"id"`
+	UID  *string `json:"uid"`
+}
+
+// documentPolicy is a policy in a policy document. The fields match the
+// attributes of a 'policy' block in the 'cedar_policyset' data source.
+type documentPolicy struct {
+	Effect      string `json:"effect"`
+	Annotations []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"annotation"`
+
+	AnyPrincipal bool         `json:"any_principal"`
+	Principal    *documentEID `json:"principal"`
+	PrincipalIn  *documentEID `json:"principal_in"`
+	PrincipalIs  string       `json:"principal_is"`
+
+	AnyAction bool           `json:"any_action"`
+	Action    *documentEID   `json:"action"`
+	ActionIn  *[]documentEID `json:"action_in"`
+
+	AnyResource bool         `json:"any_resource"`
+	Resource    *documentEID `json:"resource"`
+	ResourceIn  *documentEID `json:"resource_in"`
+	ResourceIs  string       `json:"resource_is"`
+
+	When   []documentCondition `json:"when"`
+	Unless []documentCondition `json:"unless"`
+
+	NotBefore *string `json:"not_before"`
+	NotAfter  *string `json:"not_after"`
+}
+
+// documentCondition is a condition in a policy document. Exactly one of
+// 'text' or 'macro' must be set.
+type documentCondition struct {
+	Text   string                   `json:"text"`
+	Macro  string                   `json:"macro"`
+	Params map[string]documentParam `json:"params"`
+}
+
+// documentParam is a parameter of a condition. Exactly one field must be set.
+type documentParam struct {
+	Entity   *documentEID     `json:"entity"`
+	String   *string          `json:"string"`
+	Long     *int64           `json:"long"`
+	Bool     *bool            `json:"bool"`
+	Set      *[]documentParam `json:"set"`
+	IP       *string          `json:"ip"`
+	Decimal  *string          `json:"decimal"`
+	Datetime *string          `json:"datetime"`
+}
+
+// DocumentPolicy is a policy parsed from a policy document,
+// along with the time window given by its 'not_before' and 'not_after' fields.
+type DocumentPolicy struct {
+	Policy
+	Validity Validity
+}
+
+// ParsePolicyDocument parses a JSON document containing policies. The document
+// may be a Cedar JSON policy set, a single policy, or a list of policies.
+// Each policy is given either in the Cedar JSON policy format, or with the same
+// fields as a 'policy' block of the 'cedar_policyset' data source, for example:
+//
+//	[{"effect": "permit", "principal_in": {"uid": "Group::\"admins\""}, "any_action": true, "any_resource": true}]
+//
+// Policies with a 'not_before' or 'not_after' time are restricted to that window
+// by comparing it with DefaultTimeAttribute. Conditions can't use macros.
+func ParsePolicyDocument(data []byte) ([]Policy, error) {
+	docs, err := ParseDocumentPolicies(data, nil)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]Policy, len(docs))
+	for i, doc := range docs {
+		policies[i] = doc.Policy.WithValidity(DefaultTimeAttribute, doc.Validity)
+	}
+	return policies, nil
+}
+
+// ParseDocumentPolicies parses a JSON document containing policies in the same
+// way as ParsePolicyDocument, but returns the time window of each policy rather
+// than adding it to the policy's conditions. Conditions which use a macro are
+// expanded with the given macros.
+func ParseDocumentPolicies(data []byte, macros Macros) ([]DocumentPolicy, error) {
+	data = bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(data, []byte("[")) {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		policies := make([]DocumentPolicy, len(items))
+		for i, item := range items {
+			policy, err := parseDocumentPolicy(item, macros)
+			if err != nil {
+				return nil, fmt.Errorf("policy index %v: %w", i, err)
+			}
+			policies[i] = policy
+		}
+		return policies, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["staticPolicies"]; ok {
+		parsed, err := ParsePolicySetJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		policies := make([]DocumentPolicy, len(parsed))
+		for i, policy := range parsed {
+			policies[i] = DocumentPolicy{Policy: policy}
+		}
+		return policies, nil
+	}
+
+	policy, err := parseDocumentPolicy(data, macros)
+	if err != nil {
+		return nil, err
+	}
+	return []DocumentPolicy{policy}, nil
+}
+
+// parseDocumentPolicy parses a single policy, in either the Cedar JSON policy
+// format or the 'policy' block format. Policies in the Cedar JSON format have
+// scope constraints with an 'op' field.
+func parseDocumentPolicy(data []byte, macros Macros) (DocumentPolicy, error) {
+	var probe struct {
+		Principal struct {
+			Op *string `json:"op"`
+		} `json:"principal"`
+	}
+	if err := json.Unmarshal(data, &probe); err == nil && probe.Principal.Op != nil {
+		policy, err := ParsePolicyJSON(data)
+		return DocumentPolicy{Policy: policy}, err
+	}
+
+	var doc documentPolicy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return DocumentPolicy{}, err
+	}
+	if doc.Effect == "" {
+		return DocumentPolicy{}, errors.New("'effect' must be specified")
+	}
+
+	policy := Policy{
+		Effect:       doc.Effect,
+		AnyPrincipal: doc.AnyPrincipal,
+		PrincipalIs:  doc.PrincipalIs,
+		AnyAction:    doc.AnyAction,
+		AnyResource:  doc.AnyResource,
+		ResourceIs:   doc.ResourceIs,
+	}
+	for _, e := range []struct {
+		field string
+		doc   *documentEID
+		eid   **eid.EID
+	}{
+		{"principal", doc.Principal, &policy.Principal},
+		{"principal_in", doc.PrincipalIn, &policy.PrincipalIn},
+		{"action", doc.Action, &policy.Action},
+		{"resource", doc.Resource, &policy.Resource},
+		{"resource_in", doc.ResourceIn, &policy.ResourceIn},
+	} {
+		resolved, err := e.doc.eid()
+		if err != nil {
+			return DocumentPolicy{}, fmt.Errorf("%s: %w", e.field, err)
+		}
+		*e.eid = resolved
+	}
+	policy.CombineIsIn()
+	for _, anno := range doc.Annotations {
+		policy.Annotations = append(policy.Annotations, Annotation{Name: anno.Name, Value: anno.Value})
+	}
+	if doc.ActionIn != nil {
+		policy.ActionIn = make([]eid.EID, len(*doc.ActionIn))
+		for i, e := range *doc.ActionIn {
+			resolved, err := e.eid()
+			if err != nil {
+				return DocumentPolicy{}, fmt.Errorf("action_in[%d]: %w", i, err)
+			}
+			policy.ActionIn[i] = *resolved
+		}
+	}
+	for _, c := range []struct {
+		keyword    string
+		conditions []documentCondition
+		dst        *[]Condition
+	}{{"when", doc.When, &policy.When}, {"unless", doc.Unless, &policy.Unless}} {
+		for i, cond := range c.conditions {
+			text, err := cond.text(macros)
+			if err != nil {
+				return DocumentPolicy{}, fmt.Errorf("%s[%d]: %w", c.keyword, i, err)
+			}
+			*c.dst = append(*c.dst, Condition{Text: text})
+		}
+	}
+
+	var validity Validity
+	for _, bound := range []struct {
+		name  string
+		value *string
+		dst   *time.Time
+	}{
+		{"not_before", doc.NotBefore, &validity.NotBefore},
+		{"not_after", doc.NotAfter, &validity.NotAfter},
+	} {
+		if bound.value == nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, *bound.value)
+		if err != nil {
+			return DocumentPolicy{}, fmt.Errorf("%s: %q is not an RFC3339 timestamp, such as '2024-10-15T09:00:00Z'", bound.name, *bound.value)
+		}
+		*bound.dst = t
+	}
+	if err := validity.Check(); err != nil {
+		return DocumentPolicy{}, err
+	}
+
+	return DocumentPolicy{Policy: policy, Validity: validity}, nil
+}
+
+// text returns the text of the condition, with its parameters bound.
+// If the condition refers to a macro, the macro's text is used.
+func (c documentCondition) text(macros Macros) (string, error) {
+	switch {
+	case c.Text != "" && c.Macro != "":
+		return "", errors.New("only one of 'text' or 'macro' may be specified")
+	case c.Text == "" && c.Macro == "":
+		return "", errors.New("one of 'text' or 'macro' must be specified")
+	}
+	if c.Params == nil && c.Macro == "" {
+		return c.Text, nil
+	}
+	params := make(map[string]Value, len(c.Params))
+	for name, param := range c.Params {
+		v, err := param.value()
+		if err != nil {
+			return "", fmt.Errorf("params.%s: %w", name, err)
+		}
+		params[name] = v
+	}
+	if c.Macro != "" {
+		return macros.Expand(c.Macro, params)
+	}
+	return BindParams(c.Text, params)
+}
+
+// value returns the Cedar value of the parameter.
+func (p documentParam) value() (Value, error) {
+	var values []Value
+	if p.Entity != nil {
+		e, err := p.Entity.eid()
+		if err != nil {
+			return nil, fmt.Errorf("entity: %w", err)
+		}
+		values = append(values, EntityUID{Type: e.Type, ID: e.ID})
+	}
+	if p.String != nil {
+		values = append(values, String(*p.String))
+	}
+	if p.Long != nil {
+		values = append(values, Long(*p.Long))
+	}
+	if p.Bool != nil {
+		values = append(values, Bool(*p.Bool))
+	}
+	if p.Set != nil {
+		set := make(Set, len(*p.Set))
+		for i, elem := range *p.Set {
+			v, err := elem.value()
+			if err != nil {
+				return nil, fmt.Errorf("set[%d]: %w", i, err)
+			}
+			set[i] = v
+		}
+		values = append(values, set)
+	}
+	for _, ext := range []struct {
+		arg   *string
+		parse func(string) (Value, error)
+	}{
+		{p.IP, func(s string) (Value, error) { return ParseIPAddr(s) }},
+		{p.Decimal, func(s string) (Value, error) { return ParseDecimal(s) }},
+		{p.Datetime, func(s string) (Value, error) { return ParseDatetime(s) }},
+	} {
+		if ext.arg == nil {
+			continue
+		}
+		v, err := ext.parse(*ext.arg)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+
+	if len(values) != 1 {
+		return nil, errors.New("exactly one of 'entity', 'string', 'long', 'bool', 'set', 'ip', 'decimal' or 'datetime' must be specified")
+	}
+	return values[0], nil
+}
+
+// eid returns the entity, parsing its 'uid' if set.
+func (e *documentEID) eid() (*eid.EID, error) {
+	if e == nil {
+		return nil, nil
+	}
+	if e.UID == nil {
+		return &eid.EID{Type: e.Type, ID: e.ID}, nil
+	}
+	if e.Type != "" || e.ID != "" {
+		return nil, fmt.Errorf("only one of 'uid' or 'type' and 'id' may be specified, got uid %q", *e.UID)
+	}
+	parsed, err := eid.Parse(*e.UID)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,128 @@
+package cedarpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePolicyDocument(t *testing.T) {
+	policies, err := ParsePolicyDocument([]byte(`[
+		{
+			"effect": "permit",
+			"annotation": [{"name": "id", "value": "readers"}],
+			"principal_in": {"uid": "Group::\"readers\""},
+			"action_in": [{"type": "Action", "id": "Read"}, {"uid": "Action::\"List\""}],
+			"any_resource": true,
+			"when": [{"text": "context.mfa"}]
+		},
+		{
+			"effect": "forbid",
+			"principal": {"op": "All"},
+			"action": {"op": "All"},
+			"resource": {"op": "is", "entity_type": "Secret"},
+			"conditions": []
+		}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := FormatPolicySet(policies, FormatOptions{Indent: "  "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `@id("readers")
+permit (
+  principal in Group::"readers",
+  action in [Action::"Read", Action::"List"],
+  resource
+)
+when {
+  context.mfa
+};
+
+forbid (
+  principal,
+  action,
+  resource is Secret
+);
+`, text)
+
+	policies, err = ParsePolicyDocument([]byte(`{"effect": "permit", "any_principal": true, "any_action": true, "any_resource": true}`))
+	if assert.NoError(t, err) {
+		assert.Len(t, policies, 1)
+	}
+
+	_, err = ParsePolicyDocument([]byte(`[{"effect": "permit", "principals": {"uid": "User::\"alice\""}}]`))
+	assert.ErrorContains(t, err, `policy index 0: json: unknown field "principals"`)
+
+	policies, err = ParsePolicyDocument([]byte(`{
+		"effect": "permit", "any_principal": true, "any_action": true, "any_resource": true,
+		"when": [{"text": "resource.owner == $owner && context.ip.isInRange($net)", "params": {"owner": {"entity": {"uid": "User::\"alice\""}}, "net": {"ip": "10.0.0.0/8"}}}]
+	}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Condition{{Text: `resource.owner == User::"alice" && context.ip.isInRange(ip("10.0.0.0/8"))`}}, policies[0].When)
+	}
+
+	_, err = ParsePolicyDocument([]byte(`{
+		"effect": "permit", "any_principal": true, "any_action": true, "any_resource": true,
+		"unless": [{"text": "context.level < $min", "params": {"min": {"long": 1, "string": "1"}}}]
+	}`))
+	assert.EqualError(t, err, "unless[0]: params.min: exactly one of 'entity', 'string', 'long', 'bool', 'set', 'ip', 'decimal' or 'datetime' must be specified")
+}
+
+func TestParseDocumentPolicies_Validity(t *testing.T) {
+	policies, err := ParseDocumentPolicies([]byte(`{
+		"effect": "permit", "any_principal": true, "any_action": true, "any_resource": true,
+		"not_before": "2024-10-15T09:00:00Z", "not_after": "2024-10-16T09:00:00+01:00"
+	}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "2024-10-15T09:00:00Z", policies[0].Validity.NotBefore.UTC().Format(time.RFC3339))
+	assert.Equal(t, "2024-10-16T08:00:00Z", policies[0].Validity.NotAfter.UTC().Format(time.RFC3339))
+	assert.Empty(t, policies[0].When)
+
+	rendered, err := ParsePolicyDocument([]byte(`{
+		"effect": "permit", "any_principal": true, "any_action": true, "any_resource": true,
+		"not_before": "2024-10-15T09:00:00Z"
+	}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Condition{{Text: `context.now >= datetime("2024-10-15T09:00:00Z")`}}, rendered[0].When)
+	}
+
+	_, err = ParseDocumentPolicies([]byte(`{"effect": "permit", "not_after": "tomorrow"}`), nil)
+	assert.EqualError(t, err, `not_after: "tomorrow" is not an RFC3339 timestamp, such as '2024-10-15T09:00:00Z'`)
+
+	_, err = ParseDocumentPolicies([]byte(`{"effect": "permit", "not_before": "2024-10-16T00:00:00Z", "not_after": "2024-10-15T00:00:00Z"}`), nil)
+	assert.EqualError(t, err, "not_after 2024-10-15T00:00:00Z is before not_before 2024-10-16T00:00:00Z")
+}
+
+func TestParseDocumentPolicies_Macros(t *testing.T) {
+	macros := Macros{
+		"mfa_required": {Name: "mfa_required", Text: `context.mfa == true`},
+		"member_of":    {Name: "member_of", Text: `principal in $group`, Params: []string{"group"}},
+	}
+
+	policies, err := ParseDocumentPolicies([]byte(`{
+		"effect": "permit", "any_principal": true, "any_action": true, "any_resource": true,
+		"when": [
+			{"macro": "mfa_required"},
+			{"macro": "member_of", "params": {"group": {"entity": {"uid": "Group::\"admins\""}}}}
+		]
+	}`), macros)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Condition{{Text: `context.mfa == true`}, {Text: `principal in Group::"admins"`}}, policies[0].When)
+	}
+
+	_, err = ParseDocumentPolicies([]byte(`{"effect": "permit", "when": [{"macro": "unknown"}]}`), macros)
+	assert.EqualError(t, err, `when[0]: macro "unknown" is not defined`)
+
+	_, err = ParseDocumentPolicies([]byte(`{"effect": "permit", "when": [{"text": "context.mfa", "macro": "mfa_required"}]}`), macros)
+	assert.EqualError(t, err, "when[0]: only one of 'text' or 'macro' may be specified")
+
+	_, err = ParsePolicyDocument([]byte(`{"effect": "permit", "unless": [{}]}`))
+	assert.EqualError(t, err, "unless[0]: one of 'text' or 'macro' must be specified")
+}
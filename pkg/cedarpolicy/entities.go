@@ -0,0 +1,282 @@
+package cedarpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Entity is a Cedar entity, with its attributes and parents.
+type Entity struct {
+	UID        EntityUID
+	Attributes Record
+	Parents    []EntityUID
+}
+
+// Entities is a store of Cedar entities, indexed by UID.
+type Entities map[EntityUID]*Entity
+
+// ParseEntities parses entities in the Cedar JSON entity format, for example:
+//
+//	[
+//	  {
+//	    "uid": { "type": "User", "id": "alice" },
+//	    "attrs": { "department": "engineering" },
+//	    "parents": [{ "type": "Group", "id": "eng" }]
+//	  }
+//	]
+func ParseEntities(data []byte) (Entities, error) {
+	var raw []struct {
+		UID     json.RawMessage   `json:"uid"`
+		Attrs   json.RawMessage   `json:"attrs"`
+		Parents []json.RawMessage `json:"parents"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing entities: %w", err)
+	}
+
+	entities := Entities{}
+	for i, r := range raw {
+		uid, err := parseEntityUIDJSON(r.UID)
+		if err != nil {
+			return nil, fmt.Errorf("entity %d: uid: %w", i, err)
+		}
+		if _, ok := entities[uid]; ok {
+			return nil, fmt.Errorf("entity %d: duplicate entity %s", i, uid)
+		}
+
+		entity := &Entity{UID: uid, Attributes: Record{}}
+
+		if len(r.Attrs) > 0 && string(r.Attrs) != "null" {
+			v, err := ParseValueJSON(r.Attrs)
+			if err != nil {
+				return nil, fmt.Errorf("entity %s: attrs: %w", uid, err)
+			}
+			attrs, ok := v.(Record)
+			if !ok {
+				return nil, fmt.Errorf("entity %s: attrs must be an object", uid)
+			}
+			entity.Attributes = attrs
+		}
+
+		for j, p := range r.Parents {
+			parent, err := parseEntityUIDJSON(p)
+			if err != nil {
+				return nil, fmt.Errorf("entity %s: parent %d: %w", uid, j, err)
+			}
+			entity.Parents = append(entity.Parents, parent)
+		}
+
+		entities[uid] = entity
+	}
+
+	return entities, nil
+}
+
+// parseEntityUIDJSON parses an entity UID written as either
+// '{"type": "User", "id": "alice"}' or '{"__entity": {"type": "User", "id": "alice"}}'.
+func parseEntityUIDJSON(data []byte) (EntityUID, error) {
+	var uid struct {
+		Type   *string `json:"type"`
+		ID     *string `json:"id"`
+		Entity *struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		} `json:"__entity"`
+	}
+	if err := json.Unmarshal(data, &uid); err != nil {
+		return EntityUID{}, err
+	}
+	if uid.Entity != nil {
+		return EntityUID{Type: uid.Entity.Type, ID: uid.Entity.ID}, nil
+	}
+	if uid.Type == nil || uid.ID == nil || *uid.Type == "" {
+		return EntityUID{}, fmt.Errorf("entity UID must contain 'type' and 'id' fields")
+	}
+	return EntityUID{Type: *uid.Type, ID: *uid.ID}, nil
+}
+
+// ParseValueJSON parses a Cedar value in the JSON format used for entity attributes
+// and request context. Entity references are written as '{"__entity": {"type": ..., "id": ...}}'
+// and extension values as '{"__extn": {"fn": "ip", "arg": "10.0.0.1"}}'.
+func ParseValueJSON(data []byte) (Value, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return valueFromJSON(v)
+}
+
+func valueFromJSON(v any) (Value, error) {
+	switch v := v.(type) {
+	case bool:
+		return Bool(v), nil
+
+	case string:
+		return String(v), nil
+
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("number %s is not a valid long", v)
+		}
+		return Long(n), nil
+
+	case []any:
+		set := make(Set, len(v))
+		for i, e := range v {
+			ev, err := valueFromJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			set[i] = ev
+		}
+		return set, nil
+
+	case map[string]any:
+		if e, ok := v["__entity"]; ok && len(v) == 1 {
+			obj, ok := e.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("__entity must be an object")
+			}
+			typ, _ := obj["type"].(string)
+			id, ok := obj["id"].(string)
+			if typ == "" || !ok {
+				return nil, fmt.Errorf("__entity must contain 'type' and 'id' fields")
+			}
+			return EntityUID{Type: typ, ID: id}, nil
+		}
+
+		if e, ok := v["__extn"]; ok && len(v) == 1 {
+			obj, ok := e.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("__extn must be an object")
+			}
+			fn, _ := obj["fn"].(string)
+			arg, ok := obj["arg"].(string)
+			if !ok {
+				return nil, fmt.Errorf("__extn must contain 'fn' and 'arg' fields")
+			}
+			return callExtension(fn, []Value{String(arg)})
+		}
+
+		record := Record{}
+		for k, e := range v {
+			ev, err := valueFromJSON(e)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			record[k] = ev
+		}
+		return record, nil
+	}
+
+	return nil, fmt.Errorf("unsupported JSON value %v", v)
+}
+
+// In returns true if the entity a is equal to b, or if b is an ancestor of a.
+func (e Entities) In(a, b EntityUID) bool {
+	if a == b {
+		return true
+	}
+
+	seen := map[EntityUID]bool{a: true}
+	queue := []EntityUID{a}
+	for len(queue) > 0 {
+		entity, ok := e[queue[0]]
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		for _, parent := range entity.Parents {
+			if parent == b {
+				return true
+			}
+			if !seen[parent] {
+				seen[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return false
+}
+
+// UIDs returns the UIDs of all entities in the store, sorted by type and ID.
+func (e Entities) UIDs() []EntityUID {
+	uids := make([]EntityUID, 0, len(e))
+	for uid := range e {
+		uids = append(uids, uid)
+	}
+	sortUIDs(uids)
+	return uids
+}
+
+func sortUIDs(uids []EntityUID) {
+	sort.Slice(uids, func(i, j int) bool {
+		if uids[i].Type != uids[j].Type {
+			return uids[i].Type < uids[j].Type
+		}
+		return uids[i].ID < uids[j].ID
+	})
+}
+
+// ParseRequest parses an authorization request in JSON format, for example:
+//
+//	{
+//	  "principal": { "type": "User", "id": "alice" },
+//	  "action": { "type": "Action", "id": "Read" },
+//	  "resource": { "type": "Document", "id": "plan" },
+//	  "context": { "mfa": true }
+//	}
+//
+// The context is optional.
+func ParseRequest(data []byte) (Request, error) {
+	var raw struct {
+		Principal json.RawMessage `json:"principal"`
+		Action    json.RawMessage `json:"action"`
+		Resource  json.RawMessage `json:"resource"`
+		Context   json.RawMessage `json:"context"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Request{}, fmt.Errorf("parsing request: %w", err)
+	}
+
+	req := Request{Context: Record{}}
+
+	for _, f := range []struct {
+		name string
+		data json.RawMessage
+		dst  *EntityUID
+	}{
+		{"principal", raw.Principal, &req.Principal},
+		{"action", raw.Action, &req.Action},
+		{"resource", raw.Resource, &req.Resource},
+	} {
+		if len(f.data) == 0 {
+			return Request{}, fmt.Errorf("%s: must be specified", f.name)
+		}
+		uid, err := parseEntityUIDJSON(f.data)
+		if err != nil {
+			return Request{}, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.dst = uid
+	}
+
+	if len(raw.Context) > 0 && string(raw.Context) != "null" {
+		v, err := ParseValueJSON(raw.Context)
+		if err != nil {
+			return Request{}, fmt.Errorf("context: %w", err)
+		}
+		record, ok := v.(Record)
+		if !ok {
+			return Request{}, fmt.Errorf("context: must be an object")
+		}
+		req.Context = record
+	}
+
+	return req, nil
+}
@@ -0,0 +1,589 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"strings"
+)
+
+// Request is a Cedar authorization request.
+type Request struct {
+	Principal EntityUID
+	Action    EntityUID
+	Resource  EntityUID
+	Context   Record
+}
+
+// EvaluationError is returned when a Cedar expression cannot be evaluated,
+// for example due to a type error or a missing attribute.
+type EvaluationError struct {
+	Message string
+}
+
+func (e *EvaluationError) Error() string {
+	return e.Message
+}
+
+func evalErrorf(format string, args ...any) error {
+	return &EvaluationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Evaluate evaluates a Cedar expression against a request and entity store.
+func Evaluate(expr Expr, entities Entities, req Request) (Value, error) {
+	e := evaluator{entities: entities, req: req}
+	return e.eval(expr)
+}
+
+type evaluator struct {
+	entities Entities
+	req      Request
+}
+
+func (e evaluator) eval(expr Expr) (Value, error) {
+	switch x := expr.(type) {
+	case LiteralExpr:
+		return x.Value, nil
+
+	case VarExpr:
+		switch x.Name {
+		case "principal":
+			return e.req.Principal, nil
+		case "action":
+			return e.req.Action, nil
+		case "resource":
+			return e.req.Resource, nil
+		case "context":
+			if e.req.Context == nil {
+				return Record{}, nil
+			}
+			return e.req.Context, nil
+		}
+		return nil, evalErrorf("unknown variable %q", x.Name)
+
+	case EntityExpr:
+		return x.UID, nil
+
+	case SetExpr:
+		set := make(Set, len(x.Elements))
+		for i, elem := range x.Elements {
+			v, err := e.eval(elem)
+			if err != nil {
+				return nil, err
+			}
+			set[i] = v
+		}
+		return set, nil
+
+	case RecordExpr:
+		record := Record{}
+		for _, f := range x.Fields {
+			v, err := e.eval(f.Value)
+			if err != nil {
+				return nil, err
+			}
+			record[f.Key] = v
+		}
+		return record, nil
+
+	case IfExpr:
+		cond, err := e.evalBool(x.Cond)
+		if err != nil {
+			return nil, err
+		}
+		if cond {
+			return e.eval(x.Then)
+		}
+		return e.eval(x.Else)
+
+	case UnaryExpr:
+		switch x.Op {
+		case "!":
+			v, err := e.evalBool(x.Operand)
+			if err != nil {
+				return nil, err
+			}
+			return Bool(!v), nil
+		case "-":
+			v, err := e.evalLong(x.Operand)
+			if err != nil {
+				return nil, err
+			}
+			if v == math.MinInt64 {
+				return nil, evalErrorf("integer overflow when negating %d", v)
+			}
+			return Long(-v), nil
+		}
+		return nil, evalErrorf("unknown operator %q", x.Op)
+
+	case BinaryExpr:
+		return e.evalBinary(x)
+
+	case HasExpr:
+		v, err := e.eval(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case Record:
+			_, ok := v[x.Attr]
+			return Bool(ok), nil
+		case EntityUID:
+			entity, ok := e.entities[v]
+			if !ok {
+				return Bool(false), nil
+			}
+			_, ok = entity.Attributes[x.Attr]
+			return Bool(ok), nil
+		}
+		return nil, evalErrorf("'has' expects an entity or record, got %s", v.TypeName())
+
+	case LikeExpr:
+		v, err := e.eval(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := v.(String)
+		if !ok {
+			return nil, evalErrorf("'like' expects a string, got %s", v.TypeName())
+		}
+		return Bool(x.Pattern.Match(string(s))), nil
+
+	case IsExpr:
+		v, err := e.eval(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		uid, ok := v.(EntityUID)
+		if !ok {
+			return nil, evalErrorf("'is' expects an entity, got %s", v.TypeName())
+		}
+		if uid.Type != x.EntityType {
+			return Bool(false), nil
+		}
+		if x.In == nil {
+			return Bool(true), nil
+		}
+		return e.evalIn(uid, x.In)
+
+	case AccessExpr:
+		v, err := e.eval(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case Record:
+			attr, ok := v[x.Attr]
+			if !ok {
+				return nil, evalErrorf("record does not have the attribute %q", x.Attr)
+			}
+			return attr, nil
+		case EntityUID:
+			entity, ok := e.entities[v]
+			if !ok {
+				return nil, evalErrorf("entity %s does not exist", v)
+			}
+			attr, ok := entity.Attributes[x.Attr]
+			if !ok {
+				return nil, evalErrorf("entity %s does not have the attribute %q", v, x.Attr)
+			}
+			return attr, nil
+		}
+		return nil, evalErrorf("attribute access expects an entity or record, got %s", v.TypeName())
+
+	case MethodCallExpr:
+		receiver, err := e.eval(x.Receiver)
+		if err != nil {
+			return nil, err
+		}
+		args, err := e.evalArgs(x.Args)
+		if err != nil {
+			return nil, err
+		}
+		return callMethod(receiver, x.Method, args)
+
+	case CallExpr:
+		args, err := e.evalArgs(x.Args)
+		if err != nil {
+			return nil, err
+		}
+		return callExtension(x.Func, args)
+	}
+
+	return nil, evalErrorf("unsupported expression %T", expr)
+}
+
+func (e evaluator) evalArgs(exprs []Expr) ([]Value, error) {
+	args := make([]Value, len(exprs))
+	for i, arg := range exprs {
+		v, err := e.eval(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+func (e evaluator) evalBool(expr Expr) (bool, error) {
+	v, err := e.eval(expr)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(Bool)
+	if !ok {
+		return false, evalErrorf("expected bool, got %s", v.TypeName())
+	}
+	return bool(b), nil
+}
+
+func (e evaluator) evalLong(expr Expr) (int64, error) {
+	v, err := e.eval(expr)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(Long)
+	if !ok {
+		return 0, evalErrorf("expected long, got %s", v.TypeName())
+	}
+	return int64(n), nil
+}
+
+func (e evaluator) evalBinary(x BinaryExpr) (Value, error) {
+	switch x.Op {
+	case "&&", "||":
+		left, err := e.evalBool(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		// short-circuit evaluation.
+		if (x.Op == "&&" && !left) || (x.Op == "||" && left) {
+			return Bool(left), nil
+		}
+		right, err := e.evalBool(x.Right)
+		if err != nil {
+			return nil, err
+		}
+		return Bool(right), nil
+
+	case "in":
+		left, err := e.eval(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		uid, ok := left.(EntityUID)
+		if !ok {
+			return nil, evalErrorf("'in' expects an entity on the left, got %s", left.TypeName())
+		}
+		return e.evalIn(uid, x.Right)
+	}
+
+	left, err := e.eval(x.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.eval(x.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch x.Op {
+	case "==":
+		return Bool(ValuesEqual(left, right)), nil
+	case "!=":
+		return Bool(!ValuesEqual(left, right)), nil
+	}
+
+	switch l := left.(type) {
+	case Datetime:
+		return compareOrdered(x.Op, l, right)
+	case Duration:
+		return compareOrdered(x.Op, l, right)
+	}
+
+	l, lok := left.(Long)
+	r, rok := right.(Long)
+	if !lok || !rok {
+		return nil, evalErrorf("'%s' expects long operands, got %s and %s", x.Op, left.TypeName(), right.TypeName())
+	}
+
+	switch x.Op {
+	case "<":
+		return Bool(l < r), nil
+	case "<=":
+		return Bool(l <= r), nil
+	case ">":
+		return Bool(l > r), nil
+	case ">=":
+		return Bool(l >= r), nil
+	case "+":
+		sum := l + r
+		if (sum > l) != (r > 0) {
+			return nil, evalErrorf("integer overflow when adding %d and %d", l, r)
+		}
+		return sum, nil
+	case "-":
+		diff := l - r
+		if (diff < l) != (r > 0) {
+			return nil, evalErrorf("integer overflow when subtracting %d from %d", r, l)
+		}
+		return diff, nil
+	case "*":
+		if l != 0 && r != 0 {
+			product := l * r
+			if product/r != l || (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64) {
+				return nil, evalErrorf("integer overflow when multiplying %d by %d", l, r)
+			}
+			return product, nil
+		}
+		return Long(0), nil
+	}
+
+	return nil, evalErrorf("unknown operator %q", x.Op)
+}
+
+// compareOrdered evaluates a comparison of two values of an ordered
+// extension type, such as datetimes.
+func compareOrdered[T interface {
+	Value
+	~int64
+}](op string, l T, right Value) (Value, error) {
+	r, ok := right.(T)
+	if !ok {
+		return nil, evalErrorf("'%s' expects %s operands, got %s and %s", op, l.TypeName(), l.TypeName(), right.TypeName())
+	}
+	switch op {
+	case "<":
+		return Bool(l < r), nil
+	case "<=":
+		return Bool(l <= r), nil
+	case ">":
+		return Bool(l > r), nil
+	case ">=":
+		return Bool(l >= r), nil
+	}
+	return nil, evalErrorf("'%s' expects long operands, got %s and %s", op, l.TypeName(), right.TypeName())
+}
+
+// evalIn evaluates 'uid in <expr>', where expr is an entity or a set of entities.
+func (e evaluator) evalIn(uid EntityUID, expr Expr) (Value, error) {
+	right, err := e.eval(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch right := right.(type) {
+	case EntityUID:
+		return Bool(e.entities.In(uid, right)), nil
+	case Set:
+		for _, elem := range right {
+			target, ok := elem.(EntityUID)
+			if !ok {
+				return nil, evalErrorf("'in' expects a set of entities, got an element of type %s", elem.TypeName())
+			}
+			if e.entities.In(uid, target) {
+				return Bool(true), nil
+			}
+		}
+		return Bool(false), nil
+	}
+
+	return nil, evalErrorf("'in' expects an entity or set of entities on the right, got %s", right.TypeName())
+}
+
+// Match returns true if s matches the pattern.
+func (p Pattern) Match(s string) bool {
+	if len(p) == 0 {
+		return s == ""
+	}
+
+	c := p[0]
+	if !c.Wildcard {
+		if !strings.HasPrefix(s, c.Literal) {
+			return false
+		}
+		return p[1:].Match(s[len(c.Literal):])
+	}
+
+	for i := 0; i <= len(s); i++ {
+		if p[1:].Match(s[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkArgs(name string, args []Value, types ...string) error {
+	if len(args) != len(types) {
+		return evalErrorf("%s expects %d arguments, got %d", name, len(types), len(args))
+	}
+	for i, arg := range args {
+		if arg.TypeName() != types[i] {
+			return evalErrorf("%s expects argument %d to be %s, got %s", name, i+1, types[i], arg.TypeName())
+		}
+	}
+	return nil
+}
+
+// callExtension calls a Cedar extension function, such as 'ip', 'decimal' or 'datetime'.
+func callExtension(name string, args []Value) (Value, error) {
+	switch name {
+	case "ip":
+		if err := checkArgs(name, args, "string"); err != nil {
+			return nil, err
+		}
+		v, err := ParseIPAddr(string(args[0].(String)))
+		if err != nil {
+			return nil, evalErrorf("%s", err)
+		}
+		return v, nil
+
+	case "decimal":
+		if err := checkArgs(name, args, "string"); err != nil {
+			return nil, err
+		}
+		v, err := ParseDecimal(string(args[0].(String)))
+		if err != nil {
+			return nil, evalErrorf("%s", err)
+		}
+		return v, nil
+
+	case "datetime":
+		if err := checkArgs(name, args, "string"); err != nil {
+			return nil, err
+		}
+		v, err := ParseDatetime(string(args[0].(String)))
+		if err != nil {
+			return nil, evalErrorf("%s", err)
+		}
+		return v, nil
+
+	case "duration":
+		if err := checkArgs(name, args, "string"); err != nil {
+			return nil, err
+		}
+		v, err := ParseDuration(string(args[0].(String)))
+		if err != nil {
+			return nil, evalErrorf("%s", err)
+		}
+		return v, nil
+	}
+
+	return nil, evalErrorf("unknown extension function %q", name)
+}
+
+// callMethod calls a method on a set or extension value.
+func callMethod(receiver Value, method string, args []Value) (Value, error) {
+	switch r := receiver.(type) {
+	case Set:
+		switch method {
+		case "contains":
+			if len(args) != 1 {
+				return nil, evalErrorf("contains expects 1 argument, got %d", len(args))
+			}
+			return Bool(setContains(r, args[0])), nil
+		case "containsAll", "containsAny":
+			if err := checkArgs(method, args, "set"); err != nil {
+				return nil, err
+			}
+			other := args[0].(Set)
+			if method == "containsAll" {
+				return Bool(setContainsAll(r, other)), nil
+			}
+			for _, e := range other {
+				if setContains(r, e) {
+					return Bool(true), nil
+				}
+			}
+			return Bool(false), nil
+		case "isEmpty":
+			if err := checkArgs(method, args); err != nil {
+				return nil, err
+			}
+			return Bool(len(r) == 0), nil
+		}
+
+	case IPAddr:
+		p := netip.Prefix(r)
+		switch method {
+		case "isIpv4", "isIpv6", "isLoopback", "isMulticast":
+			if err := checkArgs(method, args); err != nil {
+				return nil, err
+			}
+			switch method {
+			case "isIpv4":
+				return Bool(p.Addr().Is4()), nil
+			case "isIpv6":
+				return Bool(p.Addr().Is6()), nil
+			case "isLoopback":
+				return Bool(p.Addr().IsLoopback()), nil
+			default:
+				return Bool(p.Addr().IsMulticast()), nil
+			}
+		case "isInRange":
+			if err := checkArgs(method, args, "ipaddr"); err != nil {
+				return nil, err
+			}
+			other := netip.Prefix(args[0].(IPAddr))
+			return Bool(other.Bits() <= p.Bits() && other.Contains(p.Masked().Addr())), nil
+		}
+
+	case Decimal:
+		switch method {
+		case "lessThan", "lessThanOrEqual", "greaterThan", "greaterThanOrEqual":
+			if err := checkArgs(method, args, "decimal"); err != nil {
+				return nil, err
+			}
+			other := args[0].(Decimal)
+			switch method {
+			case "lessThan":
+				return Bool(r < other), nil
+			case "lessThanOrEqual":
+				return Bool(r <= other), nil
+			case "greaterThan":
+				return Bool(r > other), nil
+			default:
+				return Bool(r >= other), nil
+			}
+		}
+	}
+
+	if unsupportedMethods[method] {
+		return nil, unsupportedMethodError(method)
+	}
+	return nil, evalErrorf("unknown method %q for type %s", method, receiver.TypeName())
+}
+
+// unsupportedMethods are the Cedar methods which can be parsed, but not
+// evaluated: the methods of the datetime and duration extensions, and the
+// entity tag methods.
+var unsupportedMethods = map[string]bool{
+	"toDate":         true,
+	"toTime":         true,
+	"offset":         true,
+	"durationSince":  true,
+	"toDays":         true,
+	"toHours":        true,
+	"toMinutes":      true,
+	"toSeconds":      true,
+	"toMilliseconds": true,
+	"hasTag":         true,
+	"getTag":         true,
+}
+
+func unsupportedMethodError(method string) error {
+	return evalErrorf("method %q is not supported in evaluation", method)
+}
+
+// checkSupported returns an error if the expression calls a method which
+// can't be evaluated, so that such conditions are reported up front rather
+// than as an error for every request.
+func checkSupported(expr Expr) error {
+	var err error
+	Walk(expr, func(e Expr) {
+		if call, ok := e.(MethodCallExpr); ok && unsupportedMethods[call.Method] && err == nil {
+			err = unsupportedMethodError(call.Method)
+		}
+	})
+	return err
+}
@@ -0,0 +1,167 @@
+package cedarpolicy
+
+// Expr is a parsed Cedar expression, such as the body of a 'when' or 'unless' condition.
+type Expr interface {
+	expr()
+}
+
+// LiteralExpr is a boolean, long or string literal.
+type LiteralExpr struct {
+	Value Value
+}
+
+// VarExpr is a reference to one of the request variables:
+// 'principal', 'action', 'resource' or 'context'.
+type VarExpr struct {
+	Name string
+}
+
+// EntityExpr is an entity literal, such as 'User::"alice"'.
+type EntityExpr struct {
+	UID EntityUID
+}
+
+// SetExpr is a set literal, such as '[1, 2, 3]'.
+type SetExpr struct {
+	Elements []Expr
+}
+
+// RecordField is a single key and value in a record literal.
+type RecordField struct {
+	Key   string
+	Value Expr
+}
+
+// RecordExpr is a record literal, such as '{ "name": "alice" }'.
+type RecordExpr struct {
+	Fields []RecordField
+}
+
+// IfExpr is an 'if <cond> then <then> else <else>' expression.
+type IfExpr struct {
+	Cond Expr
+	Then Expr
+	Else Expr
+}
+
+// BinaryExpr is a binary operator expression. Op is one of
+// '||', '&&', '==', '!=', '<', '<=', '>', '>=', 'in', '+', '-' or '*'.
+type BinaryExpr struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+// UnaryExpr is a unary operator expression. Op is either '!' or '-'.
+type UnaryExpr struct {
+	Op      string
+	Operand Expr
+}
+
+// HasExpr is a '<expr> has <attr>' expression.
+type HasExpr struct {
+	Left Expr
+	Attr string
+}
+
+// PatternComponent is either a wildcard or a literal string in a 'like' pattern.
+type PatternComponent struct {
+	Wildcard bool
+	Literal  string
+}
+
+// Pattern is the pattern of a 'like' expression.
+type Pattern []PatternComponent
+
+// LikeExpr is a '<expr> like "<pattern>"' expression.
+type LikeExpr struct {
+	Left    Expr
+	Pattern Pattern
+}
+
+// IsExpr is a '<expr> is <type>' expression, optionally followed by 'in <expr>'.
+type IsExpr struct {
+	Left       Expr
+	EntityType string
+	In         Expr
+}
+
+// AccessExpr is an attribute access, written as either '<expr>.attr' or '<expr>["attr"]'.
+type AccessExpr struct {
+	Left Expr
+	Attr string
+}
+
+// MethodCallExpr is a method call, such as 'context.tags.contains("x")'.
+type MethodCallExpr struct {
+	Receiver Expr
+	Method   string
+	Args     []Expr
+}
+
+// CallExpr is an extension function call, such as 'ip("10.0.0.1")'.
+type CallExpr struct {
+	Func string
+	Args []Expr
+}
+
+func (LiteralExpr) expr()    {}
+func (VarExpr) expr()        {}
+func (EntityExpr) expr()     {}
+func (SetExpr) expr()        {}
+func (RecordExpr) expr()     {}
+func (IfExpr) expr()         {}
+func (BinaryExpr) expr()     {}
+func (UnaryExpr) expr()      {}
+func (HasExpr) expr()        {}
+func (LikeExpr) expr()       {}
+func (IsExpr) expr()         {}
+func (AccessExpr) expr()     {}
+func (MethodCallExpr) expr() {}
+func (CallExpr) expr()       {}
+
+// Walk calls fn for the expression and each of its sub-expressions, in depth-first order.
+func Walk(expr Expr, fn func(Expr)) {
+	if expr == nil {
+		return
+	}
+	fn(expr)
+
+	switch x := expr.(type) {
+	case SetExpr:
+		for _, e := range x.Elements {
+			Walk(e, fn)
+		}
+	case RecordExpr:
+		for _, f := range x.Fields {
+			Walk(f.Value, fn)
+		}
+	case IfExpr:
+		Walk(x.Cond, fn)
+		Walk(x.Then, fn)
+		Walk(x.Else, fn)
+	case BinaryExpr:
+		Walk(x.Left, fn)
+		Walk(x.Right, fn)
+	case UnaryExpr:
+		Walk(x.Operand, fn)
+	case HasExpr:
+		Walk(x.Left, fn)
+	case LikeExpr:
+		Walk(x.Left, fn)
+	case IsExpr:
+		Walk(x.Left, fn)
+		Walk(x.In, fn)
+	case AccessExpr:
+		Walk(x.Left, fn)
+	case MethodCallExpr:
+		Walk(x.Receiver, fn)
+		for _, e := range x.Args {
+			Walk(e, fn)
+		}
+	case CallExpr:
+		for _, e := range x.Args {
+			Walk(e, fn)
+		}
+	}
+}
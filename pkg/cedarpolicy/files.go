@@ -0,0 +1,38 @@
+package cedarpolicy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// PolicyFileExtension is the file extension used for Cedar policy files.
+const PolicyFileExtension = ".cedar"
+
+// PolicyFiles writes each policy to a separate file, returning a map of file
+// names to their contents. Each file holds the source text of the policy as
+// written, including its comments. Files are named after the policy ID, so
+// policy IDs must be unique and must be valid file names.
+func PolicyFiles(policies []PolicySource) (map[string]string, error) {
+	files := map[string]string{}
+	for i, p := range policies {
+		id := p.ID(i)
+		if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`+"\x00") {
+			return nil, fmt.Errorf("policy ID %q can't be used as a file name", id)
+		}
+
+		name := id + PolicyFileExtension
+		if _, ok := files[name]; ok {
+			return nil, fmt.Errorf("duplicate policy ID %q", id)
+		}
+		files[name] = p.Source + "\n"
+	}
+	return files, nil
+}
+
+// Hash returns the hex-encoded SHA-256 hash of the content.
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,56 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyFiles(t *testing.T) {
+	policies, err := ParsePolicySetSources(`
+// admins can do anything
+@id("admins") permit (principal in Group::"admins", action, resource);
+forbid (principal, action, resource) when { context.locked }; // unless locked
+
+@id("caf\u{e9}")
+@advice("a \"quoted\" value")
+permit (principal == User::"\u{200b}", action, resource);
+// trailing comment`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := PolicyFiles(policies)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, map[string]string{
+		"admins.cedar":    "// admins can do anything\n@id(\"admins\") permit (principal in Group::\"admins\", action, resource);\n",
+		"policy1.cedar":   "forbid (principal, action, resource) when { context.locked }; // unless locked\n",
+		"caf\u00e9.cedar": "@id(\"caf\\u{e9}\")\n@advice(\"a \\\"quoted\\\" value\")\npermit (principal == User::\"\\u{200b}\", action, resource);\n// trailing comment\n",
+	}, files)
+
+	// each file parses to the same policy.
+	for i, p := range policies {
+		got, err := ParsePolicy(files[p.ID(i)+PolicyFileExtension])
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, p.Policy, got)
+	}
+}
+
+func TestPolicyFiles_InvalidID(t *testing.T) {
+	for _, text := range []string{
+		`@id("../escape") permit (principal, action, resource);`,
+		`@id("a") permit (principal, action, resource); @id("a") forbid (principal, action, resource);`,
+	} {
+		policies, err := ParsePolicySetSources(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = PolicyFiles(policies)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,378 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// FormatOptions controls the layout of formatted Cedar policies.
+type FormatOptions struct {
+	// Indent is the string used for each level of indentation.
+	Indent string
+
+	// LineWidth is the maximum width of a line before action lists and
+	// '&&'/'||' chains are wrapped onto multiple lines. Tabs are counted
+	// as four characters. If zero, lines are never wrapped.
+	LineWidth int
+}
+
+// DefaultFormatOptions returns the default formatting options, which match
+// the layout produced by RenderString.
+func DefaultFormatOptions() FormatOptions {
+	return FormatOptions{
+		Indent:    "\t",
+		LineWidth: 80,
+	}
+}
+
+// Format parses Cedar policy text and renders it in canonical form.
+// Comments are not preserved.
+func Format(src string, opts FormatOptions) (string, error) {
+	policies, err := ParsePolicySet(src)
+	if err != nil {
+		return "", err
+	}
+	return FormatPolicySet(policies, opts)
+}
+
+// Equivalent returns true if two Cedar policy texts differ only in formatting,
+// such as whitespace, line breaks and comments. Policy templates may be compared.
+func Equivalent(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	canonical := func(src string) (string, error) {
+		policies, err := parsePolicySet(src, true, Version{})
+		if err != nil {
+			return "", err
+		}
+		return FormatPolicySet(policies, DefaultFormatOptions())
+	}
+
+	ca, err := canonical(a)
+	if err != nil {
+		return false
+	}
+	cb, err := canonical(b)
+	if err != nil {
+		return false
+	}
+	return ca == cb
+}
+
+// FormatPolicySet renders a list of policies in canonical form,
+// separated by blank lines.
+func FormatPolicySet(policies []Policy, opts FormatOptions) (string, error) {
+	rendered := make([]string, len(policies))
+	for i, p := range policies {
+		text, err := p.Format(opts)
+		if err != nil {
+			return "", fmt.Errorf("policy %q: %w", p.ID(i), err)
+		}
+		rendered[i] = text
+	}
+	return strings.Join(rendered, "\n\n") + "\n", nil
+}
+
+// Format renders the policy in canonical form. Unlike RenderString, the
+// conditions of the policy are parsed, so whitespace in conditions is
+// normalized and long conditions are wrapped.
+func (p Policy) Format(opts FormatOptions) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	f := formatter{opts: opts}
+	var lines []string
+
+	for _, anno := range p.Annotations {
+		lines = append(lines, fmt.Sprintf("@%s(%s)", anno.Name, eid.Quote(anno.Value)))
+	}
+
+	lines = append(lines, p.Effect+" (")
+	lines = append(lines, f.indentLines(p.principalScope().render("principal")+",", 1)...)
+	lines = append(lines, f.formatActionScope(p.actionScope())...)
+	lines = append(lines, f.indentLines(p.resourceScope().render("resource"), 1)...)
+	lines = append(lines, ")")
+
+	for _, c := range []struct {
+		keyword    string
+		conditions []Condition
+	}{{"when", p.When}, {"unless", p.Unless}} {
+		for i, cond := range c.conditions {
+			expr, err := ParseExpr(cond.Text)
+			if err != nil {
+				return "", fmt.Errorf("%s condition index %v: %w", c.keyword, i, err)
+			}
+			lines = append(lines, c.keyword+" {")
+			lines = append(lines, f.formatExpr(expr, 1)...)
+			lines = append(lines, "}")
+		}
+	}
+
+	lines[len(lines)-1] += ";"
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// Validate checks that the required fields of the policy are specified.
+func (p Policy) Validate() error {
+	_, err := p.RenderString()
+	return err
+}
+
+// FormatExpr renders an expression on a single line.
+func FormatExpr(expr Expr) string {
+	return formatFlat(expr, precLowest)
+}
+
+type formatter struct {
+	opts FormatOptions
+}
+
+func (f formatter) prefix(level int) string {
+	return strings.Repeat(f.opts.Indent, level)
+}
+
+// fits returns true if the text fits on a line at the given indentation level.
+func (f formatter) fits(text string, level int) bool {
+	if f.opts.LineWidth <= 0 {
+		return true
+	}
+	width := utf8.RuneCountInString(strings.ReplaceAll(f.prefix(level), "\t", "    "))
+	return width+utf8.RuneCountInString(text) <= f.opts.LineWidth
+}
+
+func (f formatter) indentLines(text string, level int) []string {
+	return []string{f.prefix(level) + text}
+}
+
+func (f formatter) formatActionScope(c scopeConstraint) []string {
+	flat := c.render("action") + ","
+	if c.op != scopeIn || len(c.entities) < 2 || f.fits(flat, 1) {
+		return f.indentLines(flat, 1)
+	}
+
+	lines := []string{f.prefix(1) + "action in ["}
+	for i, e := range c.entities {
+		line := f.prefix(2) + e.String()
+		if i < len(c.entities)-1 {
+			line += ","
+		}
+		lines = append(lines, line)
+	}
+	return append(lines, f.prefix(1)+"],")
+}
+
+// formatExpr renders an expression at the given indentation level, wrapping
+// '&&' and '||' chains with one operand per line if the expression is too long.
+func (f formatter) formatExpr(expr Expr, level int) []string {
+	return f.formatExprPrec(expr, level, precLowest)
+}
+
+func (f formatter) formatExprPrec(expr Expr, level int, minPrec int) []string {
+	flat := formatFlat(expr, minPrec)
+	if f.fits(flat, level) {
+		return []string{f.prefix(level) + flat}
+	}
+
+	bin, ok := expr.(BinaryExpr)
+	if !ok || (bin.Op != "&&" && bin.Op != "||") {
+		return []string{f.prefix(level) + flat}
+	}
+
+	if minPrec > precLowest {
+		// wrap nested chains in an indented block, so that the
+		// grouping of the operands is clear.
+		lines := []string{f.prefix(level) + "("}
+		lines = append(lines, f.formatExprPrec(expr, level+1, precLowest)...)
+		return append(lines, f.prefix(level)+")")
+	}
+
+	operands := flattenChain(bin.Op, expr)
+	var lines []string
+	for i, operand := range operands {
+		operandLines := f.formatExprPrec(operand, level, precedence(expr)+1)
+		if i < len(operands)-1 {
+			operandLines[len(operandLines)-1] += " " + bin.Op
+		}
+		lines = append(lines, operandLines...)
+	}
+	return lines
+}
+
+// flattenChain returns the operands of a left-associative chain of the same operator.
+func flattenChain(op string, expr Expr) []Expr {
+	if bin, ok := expr.(BinaryExpr); ok && bin.Op == op {
+		return append(flattenChain(op, bin.Left), bin.Right)
+	}
+	return []Expr{expr}
+}
+
+const (
+	precLowest = iota
+	precOr
+	precAnd
+	precRelation
+	precAdd
+	precMult
+	precUnary
+	precMember
+	precPrimary
+)
+
+func precedence(expr Expr) int {
+	switch x := expr.(type) {
+	case IfExpr:
+		return precLowest
+	case BinaryExpr:
+		switch x.Op {
+		case "||":
+			return precOr
+		case "&&":
+			return precAnd
+		case "+", "-":
+			return precAdd
+		case "*":
+			return precMult
+		}
+		return precRelation
+	case HasExpr, LikeExpr, IsExpr:
+		return precRelation
+	case UnaryExpr:
+		return precUnary
+	case LiteralExpr:
+		if n, ok := x.Value.(Long); ok && n < 0 {
+			return precUnary
+		}
+		return precPrimary
+	case AccessExpr, MethodCallExpr:
+		return precMember
+	}
+	return precPrimary
+}
+
+// formatFlat renders an expression on a single line, adding parentheses
+// if the precedence of the expression is lower than minPrec.
+func formatFlat(expr Expr, minPrec int) string {
+	s := formatFlatInner(expr)
+	if precedence(expr) < minPrec {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+func formatFlatInner(expr Expr) string {
+	switch x := expr.(type) {
+	case LiteralExpr:
+		return x.Value.String()
+
+	case VarExpr:
+		return x.Name
+
+	case EntityExpr:
+		return x.UID.String()
+
+	case SetExpr:
+		return "[" + formatList(x.Elements) + "]"
+
+	case RecordExpr:
+		fields := make([]string, len(x.Fields))
+		for i, field := range x.Fields {
+			fields[i] = eid.Quote(field.Key) + ": " + formatFlat(field.Value, precLowest)
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+
+	case IfExpr:
+		return fmt.Sprintf("if %s then %s else %s", formatFlat(x.Cond, precLowest), formatFlat(x.Then, precLowest), formatFlat(x.Else, precLowest))
+
+	case BinaryExpr:
+		prec := precedence(x)
+		switch prec {
+		case precRelation:
+			// relational operators are not associative.
+			return fmt.Sprintf("%s %s %s", formatFlat(x.Left, precAdd), x.Op, formatFlat(x.Right, precAdd))
+		default:
+			return fmt.Sprintf("%s %s %s", formatFlat(x.Left, prec), x.Op, formatFlat(x.Right, prec+1))
+		}
+
+	case UnaryExpr:
+		return x.Op + formatFlat(x.Operand, precUnary)
+
+	case HasExpr:
+		return formatFlat(x.Left, precAdd) + " has " + formatAttrName(x.Attr)
+
+	case LikeExpr:
+		return formatFlat(x.Left, precAdd) + " like " + x.Pattern.String()
+
+	case IsExpr:
+		s := formatFlat(x.Left, precAdd) + " is " + x.EntityType
+		if x.In != nil {
+			s += " in " + formatFlat(x.In, precAdd)
+		}
+		return s
+
+	case AccessExpr:
+		if isIdentifier(x.Attr) {
+			return formatFlat(x.Left, precMember) + "." + x.Attr
+		}
+		return formatFlat(x.Left, precMember) + "[" + eid.Quote(x.Attr) + "]"
+
+	case MethodCallExpr:
+		return fmt.Sprintf("%s.%s(%s)", formatFlat(x.Receiver, precMember), x.Method, formatList(x.Args))
+
+	case CallExpr:
+		return fmt.Sprintf("%s(%s)", x.Func, formatList(x.Args))
+	}
+
+	return fmt.Sprintf("<unknown expression %T>", expr)
+}
+
+func formatList(exprs []Expr) string {
+	items := make([]string, len(exprs))
+	for i, e := range exprs {
+		items[i] = formatFlat(e, precLowest)
+	}
+	return strings.Join(items, ", ")
+}
+
+func formatAttrName(attr string) string {
+	if isIdentifier(attr) {
+		return attr
+	}
+	return eid.Quote(attr)
+}
+
+// isIdentifier returns true if s can be written as a bare identifier.
+func isIdentifier(s string) bool {
+	if s == "" || reserved[s] {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// String renders the pattern as a Cedar string literal.
+func (p Pattern) String() string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range p {
+		if c.Wildcard {
+			b.WriteByte('*')
+			continue
+		}
+		quoted := eid.Quote(c.Literal)
+		b.WriteString(strings.ReplaceAll(quoted[1:len(quoted)-1], "*", `\*`))
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		opts FormatOptions
+		text string
+		want string
+	}{
+		{
+			name: "matches_render_string",
+			opts: DefaultFormatOptions(),
+			text: `@advice("test") permit(principal==CF::User::"user1",action in [Action::"Read"],resource) when {   resource.is_public   };`,
+			want: `@advice("test")
+permit (
+	principal == CF::User::"user1",
+	action in [Action::"Read"],
+	resource
+)
+when {
+	resource.is_public
+};
+`,
+		},
+		{
+			name: "is_in_scope",
+			opts: DefaultFormatOptions(),
+			text: `permit(principal is User in Group::"eng",action,resource is Document in Folder::"shared");`,
+			want: `permit (
+	principal is User in Group::"eng",
+	action,
+	resource is Document in Folder::"shared"
+);
+`,
+		},
+		{
+			name: "normalizes_conditions",
+			opts: DefaultFormatOptions(),
+			text: `forbid (principal, action, resource)
+unless { (principal in Group::"admins") ||
+  !(context.tags["env"] like "prod-*") && -1<context.count && context has "x-y" && ((1 + 2) * 3 == 9) };`,
+			want: `forbid (
+	principal,
+	action,
+	resource
+)
+unless {
+	principal in Group::"admins" ||
+	(
+		!(context.tags.env like "prod-*") &&
+		-1 < context.count &&
+		context has "x-y" &&
+		(1 + 2) * 3 == 9
+	)
+};
+`,
+		},
+		{
+			name: "wraps_long_lines",
+			opts: FormatOptions{Indent: "  ", LineWidth: 50},
+			text: `permit (principal, action in [Action::"Read", Action::"List", Action::"Describe"], resource)
+when { context.mfa && context.device.managed && (context.network == "corp" || context.network == "vpn") };
+permit (principal, action in [Action::"Read"], resource) when { true };`,
+			want: `permit (
+  principal,
+  action in [
+    Action::"Read",
+    Action::"List",
+    Action::"Describe"
+  ],
+  resource
+)
+when {
+  context.mfa &&
+  context.device.managed &&
+  (
+    context.network == "corp" ||
+    context.network == "vpn"
+  )
+};
+
+permit (
+  principal,
+  action in [Action::"Read"],
+  resource
+)
+when {
+  true
+};
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format(tt.text, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			// formatting must be idempotent.
+			again, err := Format(got, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, got, again)
+		})
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	assert.True(t, Equivalent(
+		`permit (principal == User::"alice", action, resource) when { context.mfa && resource.public };`,
+		"// allow alice\npermit(\n  principal == User::\"alice\",\n  action,\n  resource\n)\nwhen {\n  context.mfa &&\n  resource.public\n};",
+	))
+	assert.True(t, Equivalent(
+		`permit (principal == ?principal, action, resource);`,
+		"permit (principal == ?principal,\n\taction, resource);",
+	))
+	assert.False(t, Equivalent(
+		`permit (principal, action, resource) when { context.mfa };`,
+		`permit (principal, action, resource) unless { context.mfa };`,
+	))
+	assert.False(t, Equivalent(`not cedar`, `not  cedar`))
+}
+
+func TestStripComments(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "none", src: "permit (principal, action, resource);\n", want: "permit (principal, action, resource);\n"},
+		{name: "line", src: "// header\n  // indented\npermit (principal, action, resource);\n", want: "permit (principal, action, resource);\n"},
+		{name: "trailing", src: "permit (principal, action, resource); // trailing\n", want: "permit (principal, action, resource);\n"},
+		{name: "end_of_input", src: "permit (principal, action, resource);\n// end", want: "permit (principal, action, resource);\n"},
+		{name: "string", src: `permit (principal, action, resource) when { context.url == "http://a\"//" };`, want: `permit (principal, action, resource) when { context.url == "http://a\"//" };`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StripComments(tt.src))
+		})
+	}
+}
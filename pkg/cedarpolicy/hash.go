@@ -0,0 +1,73 @@
+package cedarpolicy
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SemanticHash returns a hash of the policy which only changes if the meaning
+// of the policy changes. Whitespace in conditions is normalized and
+// annotations are sorted by name before hashing.
+func (p Policy) SemanticHash() (string, error) {
+	p.Annotations = slices.Clone(p.Annotations)
+	sort.SliceStable(p.Annotations, func(i, j int) bool {
+		return p.Annotations[i].Name < p.Annotations[j].Name
+	})
+
+	// conditions are formatted without wrapping, so that the same expression
+	// always has the same text.
+	text, err := p.Format(FormatOptions{Indent: "\t"})
+	if err == nil {
+		return Hash(text), nil
+	}
+
+	// conditions which can't be parsed are hashed as their tokens separated
+	// by a single space, so that whitespace and comments are ignored but
+	// string literals are kept as written.
+	p.When = normalizeConditions(p.When)
+	p.Unless = normalizeConditions(p.Unless)
+	text, err = p.RenderString()
+	if err != nil {
+		return "", err
+	}
+	return Hash(text), nil
+}
+
+func normalizeConditions(conditions []Condition) []Condition {
+	normalized := make([]Condition, len(conditions))
+	for i, c := range conditions {
+		tokens, err := tokenize(c.Text)
+		if err != nil {
+			// text which can't be split into tokens is hashed as written.
+			normalized[i] = c
+			continue
+		}
+		texts := make([]string, 0, len(tokens))
+		for _, tok := range tokens {
+			if tok.kind != tokenEOF {
+				texts = append(texts, tok.text)
+			}
+		}
+		normalized[i] = Condition{Text: strings.Join(texts, " ")}
+	}
+	return normalized
+}
+
+// PolicySetHash returns a hash of the policy set, built from the semantic
+// hash of each policy. If ignoreOrder is true, reordering the policies
+// doesn't change the hash.
+func PolicySetHash(policies []Policy, ignoreOrder bool) (string, error) {
+	hashes := make([]string, len(policies))
+	for i, p := range policies {
+		hash, err := p.SemanticHash()
+		if err != nil {
+			return "", err
+		}
+		hashes[i] = hash
+	}
+	if ignoreOrder {
+		sort.Strings(hashes)
+	}
+	return Hash(strings.Join(hashes, "\n")), nil
+}
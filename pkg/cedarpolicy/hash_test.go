@@ -0,0 +1,62 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemanticHash(t *testing.T) {
+	hash := func(src string) string {
+		policy, err := ParsePolicy(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := policy.SemanticHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	base := hash(`@id("a") @advice("b") permit (principal, action, resource) when { context.mfa && resource.public };`)
+
+	assert.Equal(t, base, hash("@advice(\"b\")\n@id(\"a\")\npermit(principal,action,resource)\nwhen {\n  context.mfa&&\n  resource.public\n};"))
+	assert.NotEqual(t, base, hash(`@id("a") @advice("b") permit (principal, action, resource) when { context.mfa || resource.public };`))
+	assert.NotEqual(t, base, hash(`@id("b") @advice("b") permit (principal, action, resource) when { context.mfa && resource.public };`))
+}
+
+func TestSemanticHash_Unparseable(t *testing.T) {
+	hash := func(condition string) string {
+		policy := Policy{Effect: "permit", When: []Condition{{Text: condition}}}
+		h, err := policy.SemanticHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	base := hash(`context has a.b && context.a.b == "x  y"`)
+
+	assert.Equal(t, base, hash("context has a.b\n  && context.a.b == \"x  y\" // nested"))
+	assert.NotEqual(t, base, hash(`context has a.b && context.a.b == "x y"`))
+}
+
+func TestPolicySetHash(t *testing.T) {
+	a, err := ParsePolicySet(`permit (principal, action, resource); forbid (principal, action, resource) when { context.locked };`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := []Policy{a[1], a[0]}
+
+	ordered := func(policies []Policy, ignoreOrder bool) string {
+		h, err := PolicySetHash(policies, ignoreOrder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	assert.NotEqual(t, ordered(a, false), ordered(b, false))
+	assert.Equal(t, ordered(a, true), ordered(b, true))
+}
@@ -0,0 +1,256 @@
+package cedarpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// RenderJSON renders the policy in the Cedar JSON policy format.
+// The conditions of the policy are parsed, so they must be valid Cedar expressions.
+func (p Policy) RenderJSON() ([]byte, error) {
+	est, err := p.est()
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSON(est)
+}
+
+// RenderPolicySetJSON renders a list of policies in the Cedar JSON policy set format,
+// with each policy keyed by its ID.
+func RenderPolicySetJSON(policies []Policy) ([]byte, error) {
+	static := map[string]any{}
+	for i, p := range policies {
+		id := p.ID(i)
+		if _, ok := static[id]; ok {
+			return nil, fmt.Errorf("duplicate policy ID %q", id)
+		}
+		est, err := p.est()
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", id, err)
+		}
+		static[id] = est
+	}
+
+	return marshalJSON(map[string]any{
+		"staticPolicies": static,
+		"templates":      map[string]any{},
+		"templateLinks":  []any{},
+	})
+}
+
+// marshalJSON encodes v as indented JSON without escaping operators such as '&&'.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// est returns the policy in the Cedar JSON policy format, as a value for json.Marshal.
+func (p Policy) est() (map[string]any, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	out := map[string]any{
+		"effect":    p.Effect,
+		"principal": p.principalScope().est("principal"),
+		"action":    p.actionScope().est("action"),
+		"resource":  p.resourceScope().est("resource"),
+	}
+
+	if len(p.Annotations) > 0 {
+		annotations := map[string]string{}
+		for _, anno := range p.Annotations {
+			annotations[anno.Name] = anno.Value
+		}
+		out["annotations"] = annotations
+	}
+
+	conditions := []any{}
+	for _, c := range []struct {
+		keyword    string
+		conditions []Condition
+	}{{"when", p.When}, {"unless", p.Unless}} {
+		for i, cond := range c.conditions {
+			expr, err := ParseExpr(cond.Text)
+			if err != nil {
+				return nil, fmt.Errorf("%s condition index %v: %w", c.keyword, i, err)
+			}
+			conditions = append(conditions, map[string]any{
+				"kind": c.keyword,
+				"body": exprJSON(expr),
+			})
+		}
+	}
+	out["conditions"] = conditions
+
+	return out, nil
+}
+
+func (c scopeConstraint) est(variable string) map[string]any {
+	switch c.op {
+	case scopeSlot:
+		return map[string]any{"op": c.slotOp, "slot": "?" + variable}
+	case scopeEq:
+		return map[string]any{"op": "==", "entity": entityJSON(c.entities[0])}
+	case scopeIs:
+		if len(c.entities) > 0 {
+			return map[string]any{"op": "is", "entity_type": c.typ, "in": map[string]any{"entity": entityJSON(c.entities[0])}}
+		}
+		return map[string]any{"op": "is", "entity_type": c.typ}
+	case scopeIn:
+		if len(c.entities) == 1 {
+			return map[string]any{"op": "in", "entity": entityJSON(c.entities[0])}
+		}
+		entities := make([]any, len(c.entities))
+		for i, e := range c.entities {
+			entities[i] = entityJSON(e)
+		}
+		return map[string]any{"op": "in", "entities": entities}
+	}
+	return map[string]any{"op": "All"}
+}
+
+func entityJSON(uid EntityUID) map[string]any {
+	return map[string]any{"type": uid.Type, "id": uid.ID}
+}
+
+// builtinMethods are the methods which have their own operator
+// in the JSON expression format, rather than an extension call.
+var builtinMethods = map[string]bool{
+	"contains":    true,
+	"containsAll": true,
+	"containsAny": true,
+	"isEmpty":     true,
+}
+
+// exprJSON returns the expression in the Cedar JSON expression format.
+func exprJSON(expr Expr) any {
+	switch x := expr.(type) {
+	case LiteralExpr:
+		return map[string]any{"Value": valueJSON(x.Value)}
+
+	case VarExpr:
+		return map[string]any{"Var": x.Name}
+
+	case EntityExpr:
+		return map[string]any{"Value": valueJSON(x.UID)}
+
+	case SetExpr:
+		return map[string]any{"Set": exprListJSON(x.Elements)}
+
+	case RecordExpr:
+		fields := map[string]any{}
+		for _, f := range x.Fields {
+			fields[f.Key] = exprJSON(f.Value)
+		}
+		return map[string]any{"Record": fields}
+
+	case IfExpr:
+		return map[string]any{"if-then-else": map[string]any{
+			"if":   exprJSON(x.Cond),
+			"then": exprJSON(x.Then),
+			"else": exprJSON(x.Else),
+		}}
+
+	case BinaryExpr:
+		return map[string]any{x.Op: map[string]any{
+			"left":  exprJSON(x.Left),
+			"right": exprJSON(x.Right),
+		}}
+
+	case UnaryExpr:
+		op := x.Op
+		if op == "-" {
+			op = "neg"
+		}
+		return map[string]any{op: map[string]any{"arg": exprJSON(x.Operand)}}
+
+	case HasExpr:
+		return map[string]any{"has": map[string]any{"left": exprJSON(x.Left), "attr": x.Attr}}
+
+	case LikeExpr:
+		pattern := make([]any, len(x.Pattern))
+		for i, c := range x.Pattern {
+			if c.Wildcard {
+				pattern[i] = "Wildcard"
+			} else {
+				pattern[i] = map[string]any{"Literal": c.Literal}
+			}
+		}
+		return map[string]any{"like": map[string]any{"left": exprJSON(x.Left), "pattern": pattern}}
+
+	case IsExpr:
+		is := map[string]any{"left": exprJSON(x.Left), "entity_type": x.EntityType}
+		if x.In != nil {
+			is["in"] = exprJSON(x.In)
+		}
+		return map[string]any{"is": is}
+
+	case AccessExpr:
+		return map[string]any{".": map[string]any{"left": exprJSON(x.Left), "attr": x.Attr}}
+
+	case MethodCallExpr:
+		if builtinMethods[x.Method] {
+			if len(x.Args) == 0 {
+				return map[string]any{x.Method: map[string]any{"arg": exprJSON(x.Receiver)}}
+			}
+			return map[string]any{x.Method: map[string]any{"left": exprJSON(x.Receiver), "right": exprJSON(x.Args[0])}}
+		}
+		return map[string]any{x.Method: exprListJSON(append([]Expr{x.Receiver}, x.Args...))}
+
+	case CallExpr:
+		return map[string]any{x.Func: exprListJSON(x.Args)}
+	}
+
+	return nil
+}
+
+func exprListJSON(exprs []Expr) []any {
+	out := make([]any, len(exprs))
+	for i, e := range exprs {
+		out[i] = exprJSON(e)
+	}
+	return out
+}
+
+// valueJSON returns a literal value in the Cedar JSON value format.
+func valueJSON(v Value) any {
+	switch x := v.(type) {
+	case Bool:
+		return bool(x)
+	case Long:
+		return int64(x)
+	case String:
+		return string(x)
+	case EntityUID:
+		return map[string]any{"__entity": entityJSON(x)}
+	case Set:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = valueJSON(e)
+		}
+		return out
+	case Record:
+		out := map[string]any{}
+		for k, e := range x {
+			out[k] = valueJSON(e)
+		}
+		return out
+	case IPAddr:
+		return map[string]any{"__extn": map[string]any{"fn": "ip", "arg": x.Literal()}}
+	case Decimal:
+		return map[string]any{"__extn": map[string]any{"fn": "decimal", "arg": x.Literal()}}
+	case Datetime:
+		return map[string]any{"__extn": map[string]any{"fn": "datetime", "arg": x.Literal()}}
+	case Duration:
+		return map[string]any{"__extn": map[string]any{"fn": "duration", "arg": x.Literal()}}
+	}
+	return nil
+}
@@ -0,0 +1,591 @@
+package cedarpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// extensionFunctions are the extension functions which are called as
+// functions rather than methods, such as 'ip("10.0.0.1")'.
+var extensionFunctions = map[string]bool{
+	"ip":       true,
+	"decimal":  true,
+	"datetime": true,
+	"duration": true,
+}
+
+// binaryOperators are the operators which take a 'left' and 'right'
+// operand in the JSON expression format.
+var binaryOperators = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"in": true, "+": true, "-": true, "*": true, "&&": true, "||": true,
+}
+
+// ParsePolicyJSON parses a single policy in the Cedar JSON policy format.
+// Conditions are rendered as Cedar text.
+func ParsePolicyJSON(data []byte) (Policy, error) {
+	var est map[string]json.RawMessage
+	if err := decodeJSON(data, &est); err != nil {
+		return Policy{}, err
+	}
+	return policyFromEST(est, false)
+}
+
+// ParsePolicySetJSON parses a policy set in the Cedar JSON policy set format.
+// Static policies and template-linked policies are returned sorted by ID, and are
+// given an '@id' annotation with their ID unless they already have one.
+func ParsePolicySetJSON(data []byte) ([]Policy, error) {
+	var set struct {
+		StaticPolicies map[string]map[string]json.RawMessage `json:"staticPolicies"`
+		Templates      map[string]map[string]json.RawMessage `json:"templates"`
+		TemplateLinks  []struct {
+			TemplateID string                     `json:"templateId"`
+			NewID      string                     `json:"newId"`
+			Values     map[string]json.RawMessage `json:"values"`
+		} `json:"templateLinks"`
+	}
+	if err := decodeJSON(data, &set); err != nil {
+		return nil, err
+	}
+
+	byID := map[string]Policy{}
+
+	for id, est := range set.StaticPolicies {
+		policy, err := policyFromEST(est, false)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", id, err)
+		}
+		byID[id] = policy
+	}
+
+	for _, link := range set.TemplateLinks {
+		est, ok := set.Templates[link.TemplateID]
+		if !ok {
+			return nil, fmt.Errorf("template link %q: unknown template %q", link.NewID, link.TemplateID)
+		}
+		if _, ok := byID[link.NewID]; ok {
+			return nil, fmt.Errorf("duplicate policy ID %q", link.NewID)
+		}
+		template, err := policyFromEST(est, true)
+		if err != nil {
+			return nil, fmt.Errorf("template %q: %w", link.TemplateID, err)
+		}
+
+		var principal, resource *eid.EID
+		for slot, raw := range link.Values {
+			uid, err := entityFromJSON(raw)
+			if err != nil {
+				return nil, fmt.Errorf("template link %q: %w", link.NewID, err)
+			}
+			e := &eid.EID{Type: uid.Type, ID: uid.ID}
+			switch slot {
+			case "?principal":
+				principal = e
+			case "?resource":
+				resource = e
+			default:
+				return nil, fmt.Errorf("template link %q: unknown slot %q", link.NewID, slot)
+			}
+		}
+
+		policy, err := template.Link(principal, resource)
+		if err != nil {
+			return nil, fmt.Errorf("template link %q: %w", link.NewID, err)
+		}
+		byID[link.NewID] = policy
+	}
+
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	policies := make([]Policy, len(ids))
+	for i, id := range ids {
+		policy := byID[id]
+		if _, ok := policy.annotation("id"); !ok {
+			policy = policy.WithID(id)
+		}
+		policies[i] = policy
+	}
+	return policies, nil
+}
+
+// decodeJSON decodes JSON, keeping numbers as json.Number.
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func policyFromEST(est map[string]json.RawMessage, slots bool) (Policy, error) {
+	var policy Policy
+
+	var effect string
+	if err := json.Unmarshal(est["effect"], &effect); err != nil || (effect != "permit" && effect != "forbid") {
+		return policy, fmt.Errorf("'effect' must be either 'permit' or 'forbid'")
+	}
+	policy.Effect = effect
+
+	if raw, ok := est["annotations"]; ok {
+		var annotations map[string]string
+		if err := json.Unmarshal(raw, &annotations); err != nil {
+			return policy, fmt.Errorf("annotations: %w", err)
+		}
+		names := make([]string, 0, len(annotations))
+		for name := range annotations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			policy.Annotations = append(policy.Annotations, Annotation{
+				Name:  name,
+				Value: annotations[name],
+			})
+		}
+	}
+
+	if err := scopeFromEST(est["principal"], "principal", slots, &policy.AnyPrincipal, &policy.Principal, &policy.PrincipalIn, &policy.PrincipalIs, &policy.PrincipalIsIn, &policy.PrincipalSlot); err != nil {
+		return policy, err
+	}
+	if err := actionFromEST(est["action"], &policy); err != nil {
+		return policy, err
+	}
+	if err := scopeFromEST(est["resource"], "resource", slots, &policy.AnyResource, &policy.Resource, &policy.ResourceIn, &policy.ResourceIs, &policy.ResourceIsIn, &policy.ResourceSlot); err != nil {
+		return policy, err
+	}
+
+	if raw, ok := est["conditions"]; ok {
+		var conditions []struct {
+			Kind string          `json:"kind"`
+			Body json.RawMessage `json:"body"`
+		}
+		if err := json.Unmarshal(raw, &conditions); err != nil {
+			return policy, fmt.Errorf("conditions: %w", err)
+		}
+		for i, c := range conditions {
+			expr, err := exprFromJSON(c.Body)
+			if err != nil {
+				return policy, fmt.Errorf("condition index %v: %w", i, err)
+			}
+			cond := Condition{Text: FormatExpr(expr)}
+			switch c.Kind {
+			case "when":
+				policy.When = append(policy.When, cond)
+			case "unless":
+				policy.Unless = append(policy.Unless, cond)
+			default:
+				return policy, fmt.Errorf("condition index %v: 'kind' must be either 'when' or 'unless', got %q", i, c.Kind)
+			}
+		}
+	}
+
+	return policy, nil
+}
+
+// scopeJSON is a principal, action or resource constraint in the JSON policy format.
+type scopeJSON struct {
+	Op         string            `json:"op"`
+	Entity     json.RawMessage   `json:"entity"`
+	Entities   []json.RawMessage `json:"entities"`
+	EntityType string            `json:"entity_type"`
+	In         json.RawMessage   `json:"in"`
+	Slot       string            `json:"slot"`
+}
+
+func scopeFromEST(raw json.RawMessage, variable string, slots bool, anyClause *bool, eq **eid.EID, in **eid.EID, is *string, isIn **eid.EID, slot *string) error {
+	var scope scopeJSON
+	if err := json.Unmarshal(raw, &scope); err != nil {
+		return fmt.Errorf("%s: %w", variable, err)
+	}
+
+	if scope.Slot != "" {
+		if !slots {
+			return fmt.Errorf("%s: template slots are only permitted in policy templates", variable)
+		}
+		if scope.Slot != "?"+variable {
+			return fmt.Errorf("%s: expected slot '?%s', got '%s'", variable, variable, scope.Slot)
+		}
+		if scope.Op != "==" && scope.Op != "in" {
+			return fmt.Errorf("%s: unsupported operator %q for a slot", variable, scope.Op)
+		}
+		*slot = scope.Op
+		return nil
+	}
+
+	switch scope.Op {
+	case "All":
+		*anyClause = true
+
+	case "==", "in":
+		uid, err := entityFromJSON(scope.Entity)
+		if err != nil {
+			return fmt.Errorf("%s: %w", variable, err)
+		}
+		if scope.Op == "==" {
+			*eq = newEID(uid)
+		} else {
+			*in = newEID(uid)
+		}
+
+	case "is":
+		*is = scope.EntityType
+		if scope.In != nil {
+			var in struct {
+				Entity json.RawMessage `json:"entity"`
+			}
+			if err := json.Unmarshal(scope.In, &in); err != nil {
+				return fmt.Errorf("%s: %w", variable, err)
+			}
+			uid, err := entityFromJSON(in.Entity)
+			if err != nil {
+				return fmt.Errorf("%s: %w", variable, err)
+			}
+			*isIn = newEID(uid)
+		}
+
+	default:
+		return fmt.Errorf("%s: unsupported operator %q", variable, scope.Op)
+	}
+
+	return nil
+}
+
+func actionFromEST(raw json.RawMessage, policy *Policy) error {
+	var scope scopeJSON
+	if err := json.Unmarshal(raw, &scope); err != nil {
+		return fmt.Errorf("action: %w", err)
+	}
+
+	switch scope.Op {
+	case "All":
+		policy.AnyAction = true
+
+	case "==":
+		uid, err := entityFromJSON(scope.Entity)
+		if err != nil {
+			return fmt.Errorf("action: %w", err)
+		}
+		policy.Action = newEID(uid)
+
+	case "in":
+		raws := scope.Entities
+		if scope.Entity != nil {
+			raws = []json.RawMessage{scope.Entity}
+		}
+		entities := []eid.EID{}
+		for _, raw := range raws {
+			uid, err := entityFromJSON(raw)
+			if err != nil {
+				return fmt.Errorf("action: %w", err)
+			}
+			entities = append(entities, *newEID(uid))
+		}
+		policy.ActionIn = entities
+
+	default:
+		return fmt.Errorf("action: unsupported operator %q", scope.Op)
+	}
+
+	return nil
+}
+
+// entityFromJSON decodes an entity UID, written either as '{"type": ..., "id": ...}'
+// or with the '__entity' escape.
+func entityFromJSON(raw json.RawMessage) (EntityUID, error) {
+	var uid struct {
+		Type   *string `json:"type"`
+		ID     *string `json:"id"`
+		Entity *struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		} `json:"__entity"`
+	}
+	if err := json.Unmarshal(raw, &uid); err != nil {
+		return EntityUID{}, fmt.Errorf("invalid entity: %w", err)
+	}
+	if uid.Entity != nil {
+		return EntityUID{Type: uid.Entity.Type, ID: uid.Entity.ID}, nil
+	}
+	if uid.Type == nil || uid.ID == nil {
+		return EntityUID{}, errors.New("an entity must have a 'type' and an 'id'")
+	}
+	return EntityUID{Type: *uid.Type, ID: *uid.ID}, nil
+}
+
+// exprFromJSON decodes an expression in the Cedar JSON expression format.
+func exprFromJSON(raw json.RawMessage) (Expr, error) {
+	var node map[string]json.RawMessage
+	if err := decodeJSON(raw, &node); err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	if len(node) != 1 {
+		return nil, fmt.Errorf("an expression must have exactly one key, got %d", len(node))
+	}
+
+	var key string
+	var body json.RawMessage
+	for k, v := range node {
+		key, body = k, v
+	}
+
+	var operands struct {
+		Left       json.RawMessage `json:"left"`
+		Right      json.RawMessage `json:"right"`
+		Arg        json.RawMessage `json:"arg"`
+		Attr       string          `json:"attr"`
+		Pattern    []any           `json:"pattern"`
+		EntityType string          `json:"entity_type"`
+		In         json.RawMessage `json:"in"`
+		If         json.RawMessage `json:"if"`
+		Then       json.RawMessage `json:"then"`
+		Else       json.RawMessage `json:"else"`
+	}
+	var list []json.RawMessage
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	} else if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		if err := json.Unmarshal(body, &operands); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	exprs := func(raws ...json.RawMessage) ([]Expr, error) {
+		out := make([]Expr, len(raws))
+		for i, raw := range raws {
+			if raw == nil {
+				return nil, fmt.Errorf("%s: missing operand", key)
+			}
+			e, err := exprFromJSON(raw)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = e
+		}
+		return out, nil
+	}
+
+	switch {
+	case key == "Value":
+		return valueExprFromJSON(body)
+
+	case key == "Var":
+		var name string
+		if err := json.Unmarshal(body, &name); err != nil {
+			return nil, fmt.Errorf("Var: %w", err)
+		}
+		return VarExpr{Name: name}, nil
+
+	case key == "Slot":
+		return nil, errors.New("template slots are not supported in conditions")
+
+	case key == "Set":
+		elements, err := exprs(list...)
+		if err != nil {
+			return nil, err
+		}
+		return SetExpr{Elements: elements}, nil
+
+	case key == "Record":
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, fmt.Errorf("Record: %w", err)
+		}
+		return recordExprFromJSON(fields, exprFromJSON)
+
+	case key == "if-then-else":
+		e, err := exprs(operands.If, operands.Then, operands.Else)
+		if err != nil {
+			return nil, err
+		}
+		return IfExpr{Cond: e[0], Then: e[1], Else: e[2]}, nil
+
+	case binaryOperators[key]:
+		e, err := exprs(operands.Left, operands.Right)
+		if err != nil {
+			return nil, err
+		}
+		return BinaryExpr{Op: key, Left: e[0], Right: e[1]}, nil
+
+	case key == "!" || key == "neg":
+		e, err := exprs(operands.Arg)
+		if err != nil {
+			return nil, err
+		}
+		op := key
+		if op == "neg" {
+			op = "-"
+		}
+		return UnaryExpr{Op: op, Operand: e[0]}, nil
+
+	case key == "has" || key == ".":
+		e, err := exprs(operands.Left)
+		if err != nil {
+			return nil, err
+		}
+		if key == "has" {
+			return HasExpr{Left: e[0], Attr: operands.Attr}, nil
+		}
+		return AccessExpr{Left: e[0], Attr: operands.Attr}, nil
+
+	case key == "like":
+		e, err := exprs(operands.Left)
+		if err != nil {
+			return nil, err
+		}
+		var pattern Pattern
+		for _, c := range operands.Pattern {
+			switch c := c.(type) {
+			case string:
+				if c != "Wildcard" {
+					return nil, fmt.Errorf("like: invalid pattern component %q", c)
+				}
+				pattern = append(pattern, PatternComponent{Wildcard: true})
+			case map[string]any:
+				literal, ok := c["Literal"].(string)
+				if !ok {
+					return nil, errors.New("like: invalid pattern component")
+				}
+				pattern = append(pattern, PatternComponent{Literal: literal})
+			default:
+				return nil, errors.New("like: invalid pattern component")
+			}
+		}
+		return LikeExpr{Left: e[0], Pattern: pattern}, nil
+
+	case key == "is":
+		e, err := exprs(operands.Left)
+		if err != nil {
+			return nil, err
+		}
+		is := IsExpr{Left: e[0], EntityType: operands.EntityType}
+		if operands.In != nil {
+			in, err := exprs(operands.In)
+			if err != nil {
+				return nil, err
+			}
+			is.In = in[0]
+		}
+		return is, nil
+
+	case builtinMethods[key]:
+		if operands.Arg != nil {
+			e, err := exprs(operands.Arg)
+			if err != nil {
+				return nil, err
+			}
+			return MethodCallExpr{Receiver: e[0], Method: key}, nil
+		}
+		e, err := exprs(operands.Left, operands.Right)
+		if err != nil {
+			return nil, err
+		}
+		return MethodCallExpr{Receiver: e[0], Method: key, Args: e[1:]}, nil
+
+	case list != nil:
+		args, err := exprs(list...)
+		if err != nil {
+			return nil, err
+		}
+		if extensionFunctions[key] {
+			return CallExpr{Func: key, Args: args}, nil
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%s: a method call must have a receiver", key)
+		}
+		return MethodCallExpr{Receiver: args[0], Method: key, Args: args[1:]}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported expression %q", key)
+}
+
+// valueExprFromJSON decodes a literal value in the Cedar JSON value format.
+func valueExprFromJSON(raw json.RawMessage) (Expr, error) {
+	var v any
+	if err := decodeJSON(raw, &v); err != nil {
+		return nil, fmt.Errorf("Value: %w", err)
+	}
+
+	switch x := v.(type) {
+	case bool:
+		return LiteralExpr{Value: Bool(x)}, nil
+
+	case json.Number:
+		n, err := x.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("Value: %q is not a valid long", x)
+		}
+		return LiteralExpr{Value: Long(n)}, nil
+
+	case string:
+		return LiteralExpr{Value: String(x)}, nil
+
+	case []any:
+		var list []json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		elements := make([]Expr, len(list))
+		for i, item := range list {
+			e, err := valueExprFromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = e
+		}
+		return SetExpr{Elements: elements}, nil
+
+	case map[string]any:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+		if _, ok := fields["__entity"]; ok {
+			uid, err := entityFromJSON(raw)
+			if err != nil {
+				return nil, err
+			}
+			return EntityExpr{UID: uid}, nil
+		}
+		if extn, ok := fields["__extn"]; ok {
+			var call struct {
+				Fn  string `json:"fn"`
+				Arg string `json:"arg"`
+			}
+			if err := json.Unmarshal(extn, &call); err != nil {
+				return nil, fmt.Errorf("__extn: %w", err)
+			}
+			return CallExpr{Func: call.Fn, Args: []Expr{LiteralExpr{Value: String(call.Arg)}}}, nil
+		}
+		return recordExprFromJSON(fields, valueExprFromJSON)
+	}
+
+	return nil, errors.New("Value: unsupported value")
+}
+
+// recordExprFromJSON decodes the fields of a record, sorted by key.
+func recordExprFromJSON(fields map[string]json.RawMessage, decode func(json.RawMessage) (Expr, error)) (Expr, error) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	record := RecordExpr{Fields: []RecordField{}}
+	for _, k := range keys {
+		e, err := decode(fields[k])
+		if err != nil {
+			return nil, err
+		}
+		record.Fields = append(record.Fields, RecordField{Key: k, Value: e})
+	}
+	return record, nil
+}
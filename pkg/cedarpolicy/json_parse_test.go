@@ -0,0 +1,104 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePolicySetJSON_RoundTrip(t *testing.T) {
+	policies, err := ParsePolicySet(`
+@id("admins") @advice("admins can do anything")
+permit (principal in Group::"admins", action in [Action::"Read", Action::"Write"], resource is Document)
+when { context.mfa && !(resource.tags.contains("secret")) && context.ip.isInRange(ip("10.0.0.0/8")) }
+unless { resource has owner && resource.owner like "bot-*" || [1, -2].isEmpty() };
+
+@id("locked")
+forbid (principal == User::"alice", action == Action::"Delete", resource in Folder::"root")
+when { if context.level > 3 then principal is User in Group::"x" else {"a": decimal("1.5")}["a"] == context.d };
+
+@id("shared")
+permit (principal is User in Group::"eng", action, resource is Document in Folder::"shared");`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := RenderPolicySetJSON(policies)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParsePolicySetJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, parsed, 3) {
+		for i, p := range policies {
+			want, err := p.SemanticHash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parsed[i].SemanticHash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want != got {
+				wantText, _ := p.Format(DefaultFormatOptions())
+				gotText, _ := parsed[i].Format(DefaultFormatOptions())
+				assert.Equal(t, wantText, gotText)
+			}
+		}
+	}
+}
+
+func TestParsePolicySetJSON(t *testing.T) {
+	parsed, err := ParsePolicySetJSON([]byte(`{
+		"staticPolicies": {
+			"read": {
+				"effect": "permit",
+				"principal": {"op": "All"},
+				"action": {"op": "==", "entity": {"type": "Action", "id": "Read"}},
+				"resource": {"op": "All"},
+				"conditions": []
+			}
+		},
+		"templates": {
+			"owner": {
+				"effect": "permit",
+				"principal": {"op": "==", "slot": "?principal"},
+				"action": {"op": "All"},
+				"resource": {"op": "in", "slot": "?resource"},
+				"conditions": []
+			}
+		},
+		"templateLinks": [
+			{"templateId": "owner", "newId": "alice-plan", "values": {"?principal": {"type": "User", "id": "alice"}, "?resource": {"__entity": {"type": "Document", "id": "plan"}}}}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := FormatPolicySet(parsed, FormatOptions{Indent: "  "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `@id("alice-plan")
+permit (
+  principal == User::"alice",
+  action,
+  resource in Document::"plan"
+);
+
+@id("read")
+permit (
+  principal,
+  action == Action::"Read",
+  resource
+);
+`, text)
+
+	_, err = ParsePolicySetJSON([]byte(`{"staticPolicies": {"a": {"effect": "allow"}}}`))
+	assert.ErrorContains(t, err, `policy "a": 'effect' must be either 'permit' or 'forbid'`)
+}
@@ -0,0 +1,153 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "scope",
+			text: `@id("read") permit (principal == CF::User::"alice", action in [CF::Action::"Read", CF::Action::"List"], resource is CF::Document);`,
+			want: `{
+  "action": {
+    "entities": [
+      {
+        "id": "Read",
+        "type": "CF::Action"
+      },
+      {
+        "id": "List",
+        "type": "CF::Action"
+      }
+    ],
+    "op": "in"
+  },
+  "annotations": {
+    "id": "read"
+  },
+  "conditions": [],
+  "effect": "permit",
+  "principal": {
+    "entity": {
+      "id": "alice",
+      "type": "CF::User"
+    },
+    "op": "=="
+  },
+  "resource": {
+    "entity_type": "CF::Document",
+    "op": "is"
+  }
+}`,
+		},
+		{
+			name: "conditions",
+			text: `forbid (principal in Group::"eng", action, resource)
+when { context.tags.contains("prod") && !(resource has owner) }
+unless { ip("10.0.0.1").isInRange(context.network) };`,
+			want: `{
+  "action": {
+    "op": "All"
+  },
+  "conditions": [
+    {
+      "body": {
+        "&&": {
+          "left": {
+            "contains": {
+              "left": {
+                ".": {
+                  "attr": "tags",
+                  "left": {
+                    "Var": "context"
+                  }
+                }
+              },
+              "right": {
+                "Value": "prod"
+              }
+            }
+          },
+          "right": {
+            "!": {
+              "arg": {
+                "has": {
+                  "attr": "owner",
+                  "left": {
+                    "Var": "resource"
+                  }
+                }
+              }
+            }
+          }
+        }
+      },
+      "kind": "when"
+    },
+    {
+      "body": {
+        "isInRange": [
+          {
+            "ip": [
+              {
+                "Value": "10.0.0.1"
+              }
+            ]
+          },
+          {
+            ".": {
+              "attr": "network",
+              "left": {
+                "Var": "context"
+              }
+            }
+          }
+        ]
+      },
+      "kind": "unless"
+    }
+  ],
+  "effect": "forbid",
+  "principal": {
+    "entity": {
+      "id": "eng",
+      "type": "Group"
+    },
+    "op": "in"
+  },
+  "resource": {
+    "op": "All"
+  }
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParsePolicy(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := policy.RenderJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestCheckActionTypes(t *testing.T) {
+	policy, err := ParsePolicy(`permit (principal, action in [CF::Action::"Read", CF::User::"alice"], resource);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualError(t, policy.CheckActionTypes(), `action: entity type "CF::User" is not an action type, expected 'Action' or a namespaced type such as 'CF::Action'`)
+}
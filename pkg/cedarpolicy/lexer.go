@@ -0,0 +1,233 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenInt
+	tokenPunct
+	// tokenSlot is a template slot, such as '?principal'.
+	tokenSlot
+)
+
+type token struct {
+	kind tokenKind
+	// text is the raw source text of the token. For string tokens
+	// this includes the surrounding quotes and escape sequences.
+	text string
+	// pos is the byte offset of the token in the source.
+	pos int
+}
+
+// Position is a line and column in Cedar source text.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// positionOf converts a byte offset into a line and column.
+func positionOf(src string, offset int) Position {
+	if offset > len(src) {
+		offset = len(src)
+	}
+	line := strings.Count(src[:offset], "\n") + 1
+	col := offset - strings.LastIndex(src[:offset], "\n")
+	return Position{Line: line, Column: col}
+}
+
+// ParseError is returned when Cedar source text cannot be parsed.
+type ParseError struct {
+	Pos     Position
+	Message string
+
+	// Err is set if the text is valid Cedar syntax which was rejected by a
+	// check, such as a feature which the version doesn't support or an invalid
+	// extension literal, rather than syntax which can't be parsed.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// punctuation is ordered so that longer tokens are matched first.
+var punctuation = []string{
+	"::", "==", "!=", "<=", ">=", "&&", "||",
+	"@", "(", ")", "{", "}", "[", "]", ",", ";", ":", ".", "<", ">", "!", "+", "-", "*",
+}
+
+// StripComments removes the comments from Cedar source text, along with the
+// whitespace before each comment and any lines which only held a comment.
+// Text which has no comments is returned unchanged, so the result can be
+// compared with src to determine whether src has comments.
+func StripComments(src string) string {
+	var b strings.Builder
+	for i := 0; i < len(src); {
+		switch {
+		case src[i] == '"':
+			// string literals are copied as they are, so that '//' inside them is kept.
+			start := i
+			for i++; i < len(src) && src[i] != '"'; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+			i = min(i+1, len(src))
+			b.WriteString(src[start:i])
+
+		case strings.HasPrefix(src[i:], "//"):
+			line := strings.TrimRight(b.String(), " \t")
+			b.Reset()
+			b.WriteString(line)
+
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				i = len(src)
+				break
+			}
+			i += end
+			if line == "" || strings.HasSuffix(line, "\n") {
+				i++
+			}
+
+		default:
+			b.WriteByte(src[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// tokenize splits Cedar source text into tokens, skipping whitespace and comments.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end
+			}
+
+		case r == '"':
+			start := i
+			i++
+			for {
+				if i >= len(src) {
+					return nil, &ParseError{Pos: positionOf(src, start), Message: "unterminated string literal"}
+				}
+				if src[i] == '\\' {
+					i += 2
+					continue
+				}
+				if src[i] == '"' {
+					i++
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, text: src[start:i], pos: start})
+
+		case r >= '0' && r <= '9':
+			start := i
+			for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenInt, text: src[start:i], pos: start})
+
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[start:i], pos: start})
+
+		case r == '?':
+			start := i
+			i++
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenSlot, text: src[start:i], pos: start})
+
+		default:
+			matched := false
+			for _, p := range punctuation {
+				if strings.HasPrefix(src[i:], p) {
+					tokens = append(tokens, token{kind: tokenPunct, text: p, pos: i})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &ParseError{Pos: positionOf(src, i), Message: fmt.Sprintf("unexpected character %q", r)}
+			}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(src)})
+	return tokens, nil
+}
+
+// unquotePattern decodes a Cedar string literal used as the pattern of a 'like' operator.
+// Unescaped '*' characters are wildcards, while '\*' matches a literal '*'.
+func unquotePattern(lit string) (Pattern, error) {
+	var pattern Pattern
+	var literal strings.Builder
+
+	err := eid.DecodeString(lit, true, func(r rune, wildcard bool) {
+		if !wildcard {
+			literal.WriteRune(r)
+			return
+		}
+		if literal.Len() > 0 {
+			pattern = append(pattern, PatternComponent{Literal: literal.String()})
+			literal.Reset()
+		}
+		pattern = append(pattern, PatternComponent{Wildcard: true})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if literal.Len() > 0 {
+		pattern = append(pattern, PatternComponent{Literal: literal.String()})
+	}
+	return pattern, nil
+}
@@ -0,0 +1,121 @@
+package cedarpolicy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Macro is a named condition which can be used by many policies, so that
+// a change to the condition applies to each of them. Its text may contain
+// '$name' placeholders for its parameters, which are bound to values
+// when the macro is expanded.
+type Macro struct {
+	Name   string
+	Text   string
+	Params []string
+}
+
+// Check returns an error if the macro's name is not an identifier, or if
+// the placeholders in its text don't match its parameters.
+func (m Macro) Check() error {
+	if !isIdentifier(m.Name) {
+		return fmt.Errorf("macro name %q must be an identifier, such as 'mfa_required'", m.Name)
+	}
+	placeholders, err := Placeholders(m.Text)
+	if err != nil {
+		return fmt.Errorf("macro %q: %w", m.Name, err)
+	}
+
+	var errs []error
+	for i, name := range m.Params {
+		if !isIdentifier(name) {
+			errs = append(errs, fmt.Errorf("macro %q: parameter name %q must be an identifier", m.Name, name))
+		}
+		if containsString(m.Params[:i], name) {
+			errs = append(errs, fmt.Errorf("macro %q: parameter %q is declared more than once", m.Name, name))
+		}
+	}
+	for _, name := range placeholders {
+		if !containsString(m.Params, name) {
+			errs = append(errs, fmt.Errorf("macro %q: placeholder '$%s' is not a parameter of the macro", m.Name, name))
+		}
+	}
+	for _, name := range m.Params {
+		if !containsString(placeholders, name) {
+			errs = append(errs, fmt.Errorf("macro %q: parameter %q is not used", m.Name, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Expand returns the macro's text with its parameters bound to the values.
+// A value must be given for each of the macro's parameters.
+func (m Macro) Expand(params map[string]Value) (string, error) {
+	var errs []error
+	for _, name := range m.Params {
+		if _, ok := params[name]; !ok {
+			errs = append(errs, fmt.Errorf("macro %q requires parameter %q", m.Name, name))
+		}
+	}
+	for _, name := range sortedKeys(params) {
+		if !containsString(m.Params, name) {
+			errs = append(errs, fmt.Errorf("macro %q has no parameter %q", m.Name, name))
+		}
+	}
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	text, err := BindParams(m.Text, params)
+	if err != nil {
+		return "", fmt.Errorf("macro %q: %w", m.Name, err)
+	}
+	return text, nil
+}
+
+// Macros are condition macros, keyed by name.
+type Macros map[string]Macro
+
+// NewMacros checks the macros and returns them keyed by name.
+// It returns an error if two macros have the same name.
+func NewMacros(macros []Macro) (Macros, error) {
+	out := make(Macros, len(macros))
+	var errs []error
+	for _, m := range macros {
+		if err := m.Check(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, ok := out[m.Name]; ok {
+			errs = append(errs, fmt.Errorf("macro %q is defined more than once", m.Name))
+			continue
+		}
+		out[m.Name] = m
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return out, nil
+}
+
+// With returns the macros combined with the overrides. Overrides replace
+// macros with the same name.
+func (ms Macros) With(overrides Macros) Macros {
+	out := make(Macros, len(ms)+len(overrides))
+	for name, m := range ms {
+		out[name] = m
+	}
+	for name, m := range overrides {
+		out[name] = m
+	}
+	return out
+}
+
+// Expand expands the named macro with the values of its parameters.
+func (ms Macros) Expand(name string, params map[string]Value) (string, error) {
+	m, ok := ms[name]
+	if !ok {
+		return "", fmt.Errorf("macro %q is not defined", name)
+	}
+	return m.Expand(params)
+}
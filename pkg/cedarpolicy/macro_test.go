@@ -0,0 +1,84 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMacros(t *testing.T) {
+	tests := []struct {
+		name    string
+		macros  []Macro
+		wantErr string
+	}{
+		{
+			name: "ok",
+			macros: []Macro{
+				{Name: "mfa_required", Text: `context.mfa == true && context.device.managed`},
+				{Name: "member_of", Text: `principal in $group`, Params: []string{"group"}},
+			},
+		},
+		{
+			name:    "invalid_name",
+			macros:  []Macro{{Name: "mfa-required", Text: `context.mfa`}},
+			wantErr: `macro name "mfa-required" must be an identifier, such as 'mfa_required'`,
+		},
+		{
+			name:    "reserved_name",
+			macros:  []Macro{{Name: "if", Text: `context.mfa`}},
+			wantErr: `macro name "if" must be an identifier, such as 'mfa_required'`,
+		},
+		{
+			name: "duplicate",
+			macros: []Macro{
+				{Name: "mfa_required", Text: `context.mfa`},
+				{Name: "mfa_required", Text: `context.mfa == true`},
+			},
+			wantErr: `macro "mfa_required" is defined more than once`,
+		},
+		{
+			name:    "params_mismatch",
+			macros:  []Macro{{Name: "member_of", Text: `principal in $group`, Params: []string{"team"}}},
+			wantErr: "macro \"member_of\": placeholder '$group' is not a parameter of the macro\nmacro \"member_of\": parameter \"team\" is not used",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMacros(tt.macros)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, got, len(tt.macros))
+		})
+	}
+}
+
+func TestMacros_Expand(t *testing.T) {
+	macros := Macros{
+		"mfa_required": {Name: "mfa_required", Text: `context.mfa == true && context.device.managed`},
+		"member_of":    {Name: "member_of", Text: `principal in $group`, Params: []string{"group"}},
+	}
+
+	got, err := macros.Expand("mfa_required", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `context.mfa == true && context.device.managed`, got)
+
+	got, err = macros.Expand("member_of", map[string]Value{"group": EntityUID{Type: "Group", ID: "admins"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `principal in Group::"admins"`, got)
+
+	_, err = macros.Expand("member_of", map[string]Value{"team": String("a")})
+	assert.EqualError(t, err, "macro \"member_of\" requires parameter \"group\"\nmacro \"member_of\" has no parameter \"team\"")
+
+	_, err = macros.Expand("unknown", nil)
+	assert.EqualError(t, err, `macro "unknown" is not defined`)
+
+	overridden := macros.With(Macros{"mfa_required": {Name: "mfa_required", Text: `context.mfa`}})
+	got, err = overridden.Expand("mfa_required", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `context.mfa`, got)
+	assert.Equal(t, `context.mfa == true && context.device.managed`, macros["mfa_required"].Text)
+}
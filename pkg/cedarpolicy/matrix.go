@@ -0,0 +1,186 @@
+package cedarpolicy
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// MatrixEntry is the authorization decision for a single
+// principal, action and resource combination.
+type MatrixEntry struct {
+	Principal EntityUID
+	Action    EntityUID
+	Resource  EntityUID
+	Response
+}
+
+// MatrixOptions controls which requests are evaluated by PermissionMatrix.
+type MatrixOptions struct {
+	// Schema, if provided, is used to determine the actions to evaluate
+	// and the principal and resource types that each action applies to.
+	Schema *Schema
+
+	// PrincipalTypes and ResourceTypes restrict the entities considered
+	// as principals and resources. If empty, all entity types are considered.
+	PrincipalTypes []string
+	ResourceTypes  []string
+
+	// Context is the request context used for every request.
+	Context Record
+}
+
+// IsActionType returns true if the entity type is used for Cedar actions,
+// either 'Action' or a namespaced type such as 'CF::Action'.
+func IsActionType(typ string) bool {
+	return typ == "Action" || strings.HasSuffix(typ, "::Action")
+}
+
+// PermissionMatrix evaluates every combination of principal, action and resource.
+//
+// If a schema is provided, the actions declared in the schema are evaluated against
+// the entities in the store matching the action's principal and resource types.
+// Otherwise, every action entity in the store is evaluated against every other entity.
+// The schema's action groups are added to the store as the parents of each action.
+//
+// Actions in the schema which don't declare both the principal and the resource
+// types they apply to can't be evaluated, and are returned as skipped. Action
+// groups, which other actions are members of, are not evaluated or reported.
+func PermissionMatrix(authorizer *Authorizer, entities Entities, opts MatrixOptions) (entries []MatrixEntry, skipped []EntityUID) {
+	uids := entities.UIDs()
+	if opts.Schema != nil {
+		entities = opts.Schema.WithActions(entities)
+	}
+
+	var actions []EntityUID
+	if opts.Schema != nil {
+		actions = opts.Schema.ActionUIDs()
+	} else {
+		for _, uid := range uids {
+			if IsActionType(uid.Type) {
+				actions = append(actions, uid)
+			}
+		}
+	}
+
+	for _, action := range actions {
+		var principalTypes, resourceTypes []string
+		if opts.Schema != nil {
+			principalTypes = opts.Schema.Actions[action].PrincipalTypes
+			resourceTypes = opts.Schema.Actions[action].ResourceTypes
+			if len(principalTypes) == 0 || len(resourceTypes) == 0 {
+				if !opts.Schema.isActionGroup(action) {
+					skipped = append(skipped, action)
+				}
+				continue
+			}
+		}
+
+		principals := filterEntities(uids, principalTypes, opts.PrincipalTypes, opts.Schema != nil)
+		resources := filterEntities(uids, resourceTypes, opts.ResourceTypes, opts.Schema != nil)
+
+		for _, principal := range principals {
+			for _, resource := range resources {
+				resp := authorizer.IsAuthorized(entities, Request{
+					Principal: principal,
+					Action:    action,
+					Resource:  resource,
+					Context:   opts.Context,
+				})
+				entries = append(entries, MatrixEntry{
+					Principal: principal,
+					Action:    action,
+					Resource:  resource,
+					Response:  resp,
+				})
+			}
+		}
+	}
+
+	return entries, skipped
+}
+
+// filterEntities returns the non-action entities matching the type filters.
+// If strict is true, only entities matching the schema types are returned.
+func filterEntities(uids []EntityUID, schemaTypes, types []string, strict bool) []EntityUID {
+	var out []EntityUID
+	for _, uid := range uids {
+		if IsActionType(uid.Type) {
+			continue
+		}
+		if strict && !containsString(schemaTypes, uid.Type) {
+			continue
+		}
+		if len(types) > 0 && !containsString(types, uid.Type) {
+			continue
+		}
+		out = append(out, uid)
+	}
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+var matrixHeader = []string{"principal", "action", "resource", "decision", "policies"}
+
+func (e MatrixEntry) row() []string {
+	return []string{
+		e.Principal.String(),
+		e.Action.String(),
+		e.Resource.String(),
+		string(e.Decision),
+		strings.Join(e.Reasons, " "),
+	}
+}
+
+// RenderMatrixCSV renders a permission matrix as CSV, with a header row.
+func RenderMatrixCSV(entries []MatrixEntry) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(matrixHeader); err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if err := w.Write(e.row()); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderMatrixMarkdown renders a permission matrix as a Markdown table.
+func RenderMatrixMarkdown(entries []MatrixEntry) string {
+	var b strings.Builder
+
+	writeRow := func(cells []string) {
+		for i, c := range cells {
+			cells[i] = strings.ReplaceAll(c, "|", `\|`)
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	writeRow(append([]string{}, matrixHeader...))
+	fmt.Fprintf(&b, "|%s\n", strings.Repeat(" --- |", len(matrixHeader)))
+	for _, e := range entries {
+		row := e.row()
+		for i := range row[:3] {
+			row[i] = "`" + row[i] + "`"
+		}
+		writeRow(row)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Source is a named policy set to be merged with others.
+type Source struct {
+	Name     string
+	Policies []Policy
+}
+
+// Duplicate describes a policy which is identical to a policy from another source.
+type Duplicate struct {
+	Source      string
+	PolicyID    string
+	OtherSource string
+	OtherID     string
+}
+
+// Message returns a human-readable description of the duplicate.
+func (d Duplicate) Message() string {
+	return fmt.Sprintf("policy %q from source %q is identical to policy %q from source %q, and was not included in the merged PolicySet",
+		d.PolicyID, d.Source, d.OtherID, d.OtherSource)
+}
+
+// Merge combines several policy sets into one. Policies which are identical to
+// an earlier policy, ignoring their '@id' annotation, are left out and reported
+// as duplicates. An error is returned if two different policies have the same ID.
+//
+// Policies without an '@id' annotation are given one with their ID within their
+// source, such as 'policy1', so that their ID doesn't change when they are merged.
+// If prefixIDs is true, the ID of each policy is prefixed with the name of its
+// source and a '.', and set as the policy's '@id' annotation.
+func Merge(sources []Source, prefixIDs bool) ([]Policy, []Duplicate, error) {
+	type seen struct {
+		source string
+		id     string
+	}
+
+	var merged []Policy
+	var duplicates []Duplicate
+	byContent := map[string]seen{}
+
+	for _, source := range sources {
+		for i, policy := range source.Policies {
+			id := policy.ID(i)
+
+			content, err := policy.withoutID().SemanticHash()
+			if err != nil {
+				return nil, nil, fmt.Errorf("source %q: policy %q: %w", source.Name, id, err)
+			}
+			if other, ok := byContent[content]; ok {
+				duplicates = append(duplicates, Duplicate{
+					Source:      source.Name,
+					PolicyID:    id,
+					OtherSource: other.source,
+					OtherID:     other.id,
+				})
+				continue
+			}
+			byContent[content] = seen{source: source.Name, id: id}
+
+			if prefixIDs {
+				policy = policy.WithID(source.Name + "." + id)
+			} else if _, ok := policy.explicitID(); !ok {
+				policy = policy.WithID(id)
+			}
+			merged = append(merged, policy)
+		}
+	}
+
+	ids := map[string]bool{}
+	var conflicts []string
+	for i, policy := range merged {
+		id := policy.ID(i)
+		if ids[id] {
+			conflicts = append(conflicts, fmt.Sprintf("%q", id))
+		}
+		ids[id] = true
+	}
+	if len(conflicts) > 0 {
+		return nil, nil, fmt.Errorf("duplicate policy IDs in the merged PolicySet: %s", strings.Join(conflicts, ", "))
+	}
+
+	return merged, duplicates, nil
+}
+
+// withoutID returns a copy of the policy without its '@id' annotation.
+func (p Policy) withoutID() Policy {
+	p.Annotations = slices.DeleteFunc(slices.Clone(p.Annotations), func(a Annotation) bool {
+		return a.Name == "id"
+	})
+	return p
+}
+
+// WithID returns a copy of the policy with its '@id' annotation set to id.
+func (p Policy) WithID(id string) Policy {
+	p = p.withoutID()
+	p.Annotations = append([]Annotation{{
+		Name:  "id",
+		Value: id,
+	}}, p.Annotations...)
+	return p
+}
@@ -0,0 +1,58 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	parse := func(src string) []Policy {
+		policies, err := ParsePolicySet(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return policies
+	}
+
+	platform := Source{Name: "platform", Policies: parse(`
+@id("admins") permit (principal in Group::"admins", action, resource);
+forbid (principal, action, resource) when { context.locked };`)}
+	product := Source{Name: "product", Policies: parse(`
+@id("locked") forbid (principal, action, resource) when { context.locked };
+@id("readers") permit (principal in Group::"readers", action == Action::"Read", resource);`)}
+
+	merged, duplicates, err := Merge([]Source{platform, product}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for i, p := range merged {
+		ids = append(ids, p.ID(i))
+	}
+	assert.Equal(t, []string{"platform.admins", "platform.policy1", "product.readers"}, ids)
+	assert.Equal(t, []Duplicate{{Source: "product", PolicyID: "locked", OtherSource: "platform", OtherID: "policy1"}}, duplicates)
+
+	conflicting := Source{Name: "other", Policies: parse(`@id("admins") permit (principal, action, resource);`)}
+	_, _, err = Merge([]Source{platform, conflicting}, false)
+	assert.ErrorContains(t, err, `duplicate policy IDs in the merged PolicySet: "admins"`)
+
+	_, _, err = Merge([]Source{platform, conflicting}, true)
+	assert.NoError(t, err)
+
+	// policies without an ID keep the ID from their source, even if
+	// policies before them are left out as duplicates.
+	unannotated := Source{Name: "unannotated", Policies: parse(`
+forbid (principal, action, resource) when { context.locked };
+permit (principal, action == Action::"List", resource);`)}
+	merged, duplicates, err = Merge([]Source{product, unannotated}, false)
+	if assert.NoError(t, err) {
+		assert.Len(t, merged, 3)
+		assert.Equal(t, []Annotation{{Name: "id", Value: "policy1"}}, merged[2].Annotations)
+		assert.Equal(t, []Duplicate{{Source: "unannotated", PolicyID: "policy0", OtherSource: "product", OtherID: "locked"}}, duplicates)
+	}
+
+	_, _, err = Merge([]Source{platform, unannotated}, false)
+	assert.ErrorContains(t, err, `duplicate policy IDs in the merged PolicySet: "policy1"`)
+}
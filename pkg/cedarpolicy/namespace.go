@@ -0,0 +1,66 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// QualifyType prepends the namespace to an entity type, unless the
+// type is already qualified with a namespace or the namespace is empty.
+func QualifyType(namespace, typ string) string {
+	if namespace == "" || typ == "" || strings.Contains(typ, "::") {
+		return typ
+	}
+	return namespace + "::" + typ
+}
+
+// WithNamespace returns a copy of the policy with the unqualified entity types
+// in its scope qualified with the namespace. Cedar actions are entities of the
+// type 'Action' within their namespace, so 'Action::"Read"' becomes
+// 'CF::Action::"Read"' in the 'CF' namespace.
+func (p Policy) WithNamespace(namespace string) Policy {
+	if namespace == "" {
+		return p
+	}
+
+	qualifyEID := func(e *eid.EID) *eid.EID {
+		if e == nil {
+			return nil
+		}
+		return &eid.EID{Type: QualifyType(namespace, e.Type), ID: e.ID}
+	}
+
+	p.Principal = qualifyEID(p.Principal)
+	p.PrincipalIn = qualifyEID(p.PrincipalIn)
+	p.PrincipalIs = QualifyType(namespace, p.PrincipalIs)
+	p.PrincipalIsIn = qualifyEID(p.PrincipalIsIn)
+
+	p.Action = qualifyEID(p.Action)
+	if p.ActionIn != nil {
+		actions := make([]eid.EID, len(p.ActionIn))
+		for i := range p.ActionIn {
+			actions[i] = *qualifyEID(&p.ActionIn[i])
+		}
+		p.ActionIn = actions
+	}
+
+	p.Resource = qualifyEID(p.Resource)
+	p.ResourceIn = qualifyEID(p.ResourceIn)
+	p.ResourceIs = QualifyType(namespace, p.ResourceIs)
+	p.ResourceIsIn = qualifyEID(p.ResourceIsIn)
+
+	return p
+}
+
+// CheckActionTypes returns an error if an entity in the action scope of the
+// policy is not an action, such as 'Action::"Read"' or 'CF::Action::"Read"'.
+func (p Policy) CheckActionTypes() error {
+	for _, uid := range p.actionScope().entities {
+		if !IsActionType(uid.Type) {
+			return fmt.Errorf("action: entity type %q is not an action type, expected 'Action' or a namespaced type such as 'CF::Action'", uid.Type)
+		}
+	}
+	return nil
+}
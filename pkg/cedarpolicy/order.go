@@ -0,0 +1,118 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The orders which policies can be sorted in by SortPolicies.
+const (
+	OrderAsWritten      = "as_written"
+	OrderByID           = "by_id"
+	OrderByAnnotation   = "by_annotation:"
+	OrderByEffectThenID = "by_effect_then_id"
+)
+
+// Orders lists the valid values for the order of a policy set.
+var Orders = []string{OrderAsWritten, OrderByID, OrderByAnnotation + "<name>", OrderByEffectThenID}
+
+// SortPolicies returns a copy of the policies sorted in the given order:
+//
+//   - 'as_written' (or empty) keeps the policies in their original order.
+//   - 'by_id' sorts policies by their ID.
+//   - 'by_annotation:<name>' sorts policies by the value of the named annotation,
+//     then by ID. Policies without the annotation are sorted last.
+//   - 'by_effect_then_id' sorts forbid policies before permit policies, then by ID.
+//
+// Policies without an '@id' annotation are identified by their position, so
+// every policy must have one unless the order is 'as_written'. Otherwise,
+// sorting would change the IDs of the policies without one.
+func SortPolicies(policies []Policy, order string) ([]Policy, error) {
+	indexes, err := SortIndexes(policies, order)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]Policy, len(indexes))
+	for i, index := range indexes {
+		sorted[i] = policies[index]
+	}
+	return sorted, nil
+}
+
+// SortIndexes returns the indexes of the policies in the order that
+// SortPolicies sorts them, so that other values can be sorted alongside.
+func SortIndexes(policies []Policy, order string) ([]int, error) {
+	type entry struct {
+		policy Policy
+		index  int
+		id     string
+	}
+
+	var less func(a, b entry) bool
+
+	switch {
+	case order == "" || order == OrderAsWritten:
+		less = func(a, b entry) bool { return false }
+
+	case order == OrderByID:
+		less = func(a, b entry) bool { return a.id < b.id }
+
+	case order == OrderByEffectThenID:
+		less = func(a, b entry) bool {
+			ea, eb := a.policy.Effect, b.policy.Effect
+			if ea != eb {
+				return ea < eb
+			}
+			return a.id < b.id
+		}
+
+	case strings.HasPrefix(order, OrderByAnnotation) && len(order) > len(OrderByAnnotation):
+		name := strings.TrimPrefix(order, OrderByAnnotation)
+		less = func(a, b entry) bool {
+			va, oka := a.policy.annotation(name)
+			vb, okb := b.policy.annotation(name)
+			if oka != okb {
+				return oka
+			}
+			if va != vb {
+				return va < vb
+			}
+			return a.id < b.id
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid order %q, expected one of: %s", order, strings.Join(Orders, ", "))
+	}
+
+	if order != "" && order != OrderAsWritten {
+		for i, p := range policies {
+			if _, ok := p.explicitID(); !ok {
+				return nil, fmt.Errorf("order %q requires every policy to have an '@id' annotation, as policies without one are identified by their position: policy index %d has none", order, i)
+			}
+		}
+	}
+
+	entries := make([]entry, len(policies))
+	for i, p := range policies {
+		entries[i] = entry{policy: p, index: i, id: p.ID(i)}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return less(entries[i], entries[j]) })
+
+	indexes := make([]int, len(entries))
+	for i, e := range entries {
+		indexes[i] = e.index
+	}
+	return indexes, nil
+}
+
+// annotation returns the value of the named annotation, if present.
+func (p Policy) annotation(name string) (string, bool) {
+	for _, anno := range p.Annotations {
+		if anno.Name == name {
+			return anno.Value, true
+		}
+	}
+	return "", false
+}
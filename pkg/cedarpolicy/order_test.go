@@ -0,0 +1,48 @@
+package cedarpolicy
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortPolicies(t *testing.T) {
+	policies, err := ParsePolicySet(`
+@id("b") @team("platform") permit (principal, action, resource);
+@id("c") forbid (principal, action, resource);
+@id("a") @team("billing") permit (principal, action, resource);
+@id("d") forbid (principal, action, resource) when { context.locked };`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(order string) []string {
+		sorted, err := SortPolicies(policies, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for i, p := range sorted {
+			ids = append(ids, p.ID(i))
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"b", "c", "a", "d"}, ids(""))
+	assert.Equal(t, []string{"b", "c", "a", "d"}, ids("as_written"))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ids("by_id"))
+	assert.Equal(t, []string{"c", "d", "a", "b"}, ids("by_effect_then_id"))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ids("by_annotation:team"))
+
+	unannotated := append(slices.Clone(policies), Policy{Effect: "permit", AnyPrincipal: true, AnyAction: true, AnyResource: true})
+	_, err = SortPolicies(unannotated, "by_id")
+	assert.EqualError(t, err, `order "by_id" requires every policy to have an '@id' annotation, as policies without one are identified by their position: policy index 4 has none`)
+	_, err = SortPolicies(unannotated, "as_written")
+	assert.NoError(t, err)
+
+	_, err = SortPolicies(policies, "by_annotation:")
+	assert.Error(t, err)
+	_, err = SortPolicies(policies, "random")
+	assert.ErrorContains(t, err, `invalid order "random"`)
+}
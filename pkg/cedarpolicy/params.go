@@ -0,0 +1,165 @@
+package cedarpolicy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BindParams replaces the '$name' placeholders in condition text with the
+// values of the named parameters, rendered as Cedar literals. Placeholders
+// within string literals are not replaced. For example, binding 'owner' to
+// the entity User::"alice" in
+//
+//	resource.owner == $owner
+//
+// gives 'resource.owner == User::"alice"'. An error is returned for
+// placeholders without a parameter, for parameters which are not used,
+// and for values which can't be written as literals.
+func BindParams(text string, params map[string]Value) (string, error) {
+	var errs []error
+	for _, name := range sortedKeys(params) {
+		if err := checkLiteral(params[name]); err != nil {
+			errs = append(errs, fmt.Errorf("parameter %q: %w", name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	used := map[string]bool{}
+	var unbound []string
+	out, err := replacePlaceholders(text, func(name string) string {
+		value, ok := params[name]
+		if !ok {
+			if !containsString(unbound, name) {
+				unbound = append(unbound, name)
+			}
+			return ""
+		}
+		used[name] = true
+		return literal(value)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range unbound {
+		errs = append(errs, fmt.Errorf("placeholder '$%s' has no parameter", name))
+	}
+	for _, name := range sortedKeys(params) {
+		if !used[name] {
+			errs = append(errs, fmt.Errorf("parameter %q is not used", name))
+		}
+	}
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	return out, nil
+}
+
+// Placeholders returns the names of the '$name' placeholders in condition
+// text, in the order they first appear. Placeholders within string literals
+// and comments are ignored.
+func Placeholders(text string) ([]string, error) {
+	var names []string
+	_, err := replacePlaceholders(text, func(name string) string {
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+		return ""
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// replacePlaceholders calls replace for each '$name' placeholder outside of
+// string literals and '//' comments, and returns the text with each
+// placeholder replaced by the result.
+func replacePlaceholders(text string, replace func(name string) string) (string, error) {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(text):
+			b.WriteByte(c)
+			i++
+			b.WriteByte(text[i])
+			continue
+		case c == '"':
+			inString = !inString
+		case !inString && strings.HasPrefix(text[i:], "//"):
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				end = len(text) - i
+			}
+			b.WriteString(text[i : i+end])
+			i += end - 1
+			continue
+		case !inString && c == '$':
+			end := i + 1
+			for end < len(text) && isIdentChar(text[end], end == i+1) {
+				end++
+			}
+			name := text[i+1 : end]
+			if name == "" {
+				return "", fmt.Errorf("'$' at offset %d must be followed by a parameter name", i)
+			}
+			b.WriteString(replace(name))
+			i = end - 1
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// literal renders the value as a Cedar expression. Negative numbers are
+// parenthesized so that they can be placed anywhere in an expression.
+func literal(v Value) string {
+	if n, ok := v.(Long); ok && n < 0 {
+		return "(" + n.String() + ")"
+	}
+	return v.String()
+}
+
+// checkLiteral returns an error if the value can't be written as a Cedar literal,
+// such as an entity with an invalid type.
+func checkLiteral(v Value) error {
+	switch v := v.(type) {
+	case EntityUID:
+		if !isEntityType(v.Type) {
+			return fmt.Errorf("invalid entity type %q", v.Type)
+		}
+	case Set:
+		for i, e := range v {
+			if err := checkLiteral(e); err != nil {
+				return fmt.Errorf("set element %d: %w", i, err)
+			}
+		}
+	case Record:
+		for _, k := range sortedKeys(v) {
+			if err := checkLiteral(v[k]); err != nil {
+				return fmt.Errorf("attribute %q: %w", k, err)
+			}
+		}
+	}
+	return nil
+}
+
+func isIdentChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
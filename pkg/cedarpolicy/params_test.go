@@ -0,0 +1,85 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindParams(t *testing.T) {
+	net, err := ParseIPAddr("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		params  map[string]Value
+		want    string
+		wantErr string
+	}{
+		{
+			name: "typed",
+			text: `resource.owner == $owner && context.ip.isInRange($net) && context.level > $min && principal.tags.containsAll($tags)`,
+			params: map[string]Value{
+				"owner": EntityUID{Type: "CF::User", ID: `alice" || true || "`},
+				"net":   net,
+				"min":   Long(-1),
+				"tags":  Set{String("a\"b"), Bool(true)},
+			},
+			want: `resource.owner == CF::User::"alice\" || true || \"" && context.ip.isInRange(ip("10.0.0.0/8")) && context.level > (-1) && principal.tags.containsAll(["a\"b", true])`,
+		},
+		{
+			name:   "repeated",
+			text:   `$x == $x`,
+			params: map[string]Value{"x": Decimal(15000)},
+			want:   `decimal("1.5") == decimal("1.5")`,
+		},
+		{
+			name:   "in_string",
+			text:   `resource.name like "*$name\"$*" && resource.price == $name`,
+			params: map[string]Value{"name": Long(3)},
+			want:   `resource.name like "*$name\"$*" && resource.price == 3`,
+		},
+		{
+			name:   "in_comment",
+			text:   "resource.price == $price // at most $max, see \"$doc\"\n\t&& resource.url == \"http://$host\" // $note",
+			params: map[string]Value{"price": Long(3)},
+			want:   "resource.price == 3 // at most $max, see \"$doc\"\n\t&& resource.url == \"http://$host\" // $note",
+		},
+		{
+			name:    "unbound_and_unused",
+			text:    `resource.owner == $owner || resource.editor == $editor || resource.viewer == $owner`,
+			params:  map[string]Value{"net": net, "editor": String("bob")},
+			wantErr: "placeholder '$owner' has no parameter\nparameter \"net\" is not used",
+		},
+		{
+			name:    "invalid_entity_type",
+			text:    `resource.owner == $owner`,
+			params:  map[string]Value{"owner": Set{EntityUID{Type: `User::"a" || User`, ID: "b"}}},
+			wantErr: `parameter "owner": set element 0: invalid entity type "User::\"a\" || User"`,
+		},
+		{
+			name:    "no_name",
+			text:    `resource.owner == $`,
+			wantErr: "'$' at offset 18 must be followed by a parameter name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BindParams(tt.text, tt.params)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			_, err = ParseExpr(got)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,881 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// reserved identifiers may not be used as entity type or extension function names.
+var reserved = map[string]bool{
+	"true": true, "false": true, "if": true, "then": true, "else": true,
+	"in": true, "like": true, "has": true, "is": true,
+}
+
+var variables = map[string]bool{
+	"principal": true, "action": true, "resource": true, "context": true,
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+	// slots is true if template slots are permitted in the policy scope.
+	slots bool
+	// version is the Cedar version which the syntax must be supported by.
+	version Version
+}
+
+func newParser(src string) (*parser, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{src: src, tokens: tokens}, nil
+}
+
+// ParsePolicySet parses Cedar policy text into a list of policies.
+// The text of 'when' and 'unless' conditions is preserved as written,
+// with surrounding whitespace removed.
+func ParsePolicySet(src string) ([]Policy, error) {
+	return parsePolicySet(src, false, Version{})
+}
+
+func parsePolicySet(src string, slots bool, version Version) ([]Policy, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return nil, err
+	}
+	p.slots = slots
+	p.version = version
+
+	var policies []Policy
+	for p.peek().kind != tokenEOF {
+		policy, err := p.parsePolicy()
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// PolicySource is a policy and the Cedar text it was parsed from.
+type PolicySource struct {
+	Policy
+	// Source is the text of the policy as written, including the comments
+	// before it and a comment on the same line after its ';'.
+	Source string
+}
+
+// ParsePolicySetSources parses Cedar policy text into a list of policies,
+// keeping the source text of each policy. Comments after the last policy
+// are kept with it.
+func ParsePolicySetSources(src string) ([]PolicySource, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []PolicySource
+	start := 0
+	for p.peek().kind != tokenEOF {
+		policy, err := p.parsePolicy()
+		if err != nil {
+			return nil, err
+		}
+		semi := p.tokens[p.pos-1]
+		end := semi.pos + len(semi.text)
+		line, _, _ := strings.Cut(src[end:], "\n")
+		if strings.HasPrefix(strings.TrimSpace(line), "//") {
+			end += len(line)
+		}
+		policies = append(policies, PolicySource{Policy: policy, Source: strings.TrimSpace(src[start:end])})
+		start = end
+	}
+	if rest := strings.TrimSpace(src[start:]); rest != "" && len(policies) > 0 {
+		last := &policies[len(policies)-1]
+		last.Source += "\n" + rest
+	}
+	return policies, nil
+}
+
+// ParsePolicy parses the Cedar text of a single policy.
+func ParsePolicy(src string) (Policy, error) {
+	policies, err := ParsePolicySet(src)
+	if err != nil {
+		return Policy{}, err
+	}
+	if len(policies) != 1 {
+		return Policy{}, fmt.Errorf("expected a single policy, got %d", len(policies))
+	}
+	return policies[0], nil
+}
+
+// ParseExpr parses a Cedar expression, such as the text of a 'when' or 'unless' condition.
+func ParseExpr(src string) (Expr, error) {
+	return parseExpr(src, Version{})
+}
+
+func parseExpr(src string, version Version) (Expr, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return nil, err
+	}
+	p.version = version
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %s after end of expression", describe(tok))
+	}
+	return expr, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// peekAt returns the token n positions ahead of the current token.
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) is(text string) bool {
+	tok := p.peek()
+	return (tok.kind == tokenPunct || tok.kind == tokenIdent) && tok.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) (token, error) {
+	tok := p.next()
+	if (tok.kind != tokenPunct && tok.kind != tokenIdent) || tok.text != text {
+		return tok, p.errorf(tok, "expected '%s', got %s", text, describe(tok))
+	}
+	return tok, nil
+}
+
+func (p *parser) expectIdent() (token, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return tok, p.errorf(tok, "expected identifier, got %s", describe(tok))
+	}
+	return tok, nil
+}
+
+func (p *parser) expectString() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenString {
+		return "", p.errorf(tok, "expected string literal, got %s", describe(tok))
+	}
+	s, err := eid.Unquote(tok.text)
+	if err != nil {
+		return "", p.errorf(tok, "%s", err)
+	}
+	return s, nil
+}
+
+// require returns an error at the token if the target version doesn't support the feature.
+func (p *parser) require(tok token, f feature) error {
+	if err := p.version.check(f); err != nil {
+		return p.rejectf(tok, err)
+	}
+	return nil
+}
+
+// rejectf returns a ParseError for syntax which was parsed, but rejected by a check.
+func (p *parser) rejectf(tok token, err error) error {
+	return &ParseError{Pos: positionOf(p.src, tok.pos), Message: err.Error(), Err: err}
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return &ParseError{Pos: positionOf(p.src, tok.pos), Message: fmt.Sprintf(format, args...)}
+}
+
+func describe(tok token) string {
+	if tok.kind == tokenEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("'%s'", tok.text)
+}
+
+func (p *parser) parsePolicy() (Policy, error) {
+	var policy Policy
+
+	for p.accept("@") {
+		name, err := p.expectIdent()
+		if err != nil {
+			return policy, err
+		}
+		if !p.is("(") {
+			// an annotation without a value has the value "".
+			if err := p.require(name, featureValuelessAnnotations); err != nil {
+				return policy, err
+			}
+			policy.Annotations = append(policy.Annotations, Annotation{Name: name.text})
+			continue
+		}
+		if _, err := p.expect("("); err != nil {
+			return policy, err
+		}
+		value, err := p.expectString()
+		if err != nil {
+			return policy, err
+		}
+		if _, err := p.expect(")"); err != nil {
+			return policy, err
+		}
+		policy.Annotations = append(policy.Annotations, Annotation{
+			Name:  name.text,
+			Value: value,
+		})
+	}
+
+	effect := p.next()
+	if effect.kind != tokenIdent || (effect.text != "permit" && effect.text != "forbid") {
+		return policy, p.errorf(effect, "expected 'permit' or 'forbid', got %s", describe(effect))
+	}
+	policy.Effect = effect.text
+
+	if _, err := p.expect("("); err != nil {
+		return policy, err
+	}
+	if err := p.parsePrincipalOrResource("principal", &policy.AnyPrincipal, &policy.Principal, &policy.PrincipalIn, &policy.PrincipalIs, &policy.PrincipalIsIn, &policy.PrincipalSlot); err != nil {
+		return policy, err
+	}
+	if _, err := p.expect(","); err != nil {
+		return policy, err
+	}
+	if err := p.parseAction(&policy); err != nil {
+		return policy, err
+	}
+	if _, err := p.expect(","); err != nil {
+		return policy, err
+	}
+	if err := p.parsePrincipalOrResource("resource", &policy.AnyResource, &policy.Resource, &policy.ResourceIn, &policy.ResourceIs, &policy.ResourceIsIn, &policy.ResourceSlot); err != nil {
+		return policy, err
+	}
+	// a trailing comma is permitted after the resource clause.
+	p.accept(",")
+	if _, err := p.expect(")"); err != nil {
+		return policy, err
+	}
+
+	for p.is("when") || p.is("unless") {
+		kind := p.next().text
+		open, err := p.expect("{")
+		if err != nil {
+			return policy, err
+		}
+		if _, err := p.parseExpr(); err != nil {
+			return policy, err
+		}
+		closing, err := p.expect("}")
+		if err != nil {
+			return policy, err
+		}
+
+		text := strings.TrimSpace(p.src[open.pos+1 : closing.pos])
+		cond := Condition{Text: text}
+		if kind == "when" {
+			policy.When = append(policy.When, cond)
+		} else {
+			policy.Unless = append(policy.Unless, cond)
+		}
+	}
+
+	if _, err := p.expect(";"); err != nil {
+		return policy, err
+	}
+
+	return policy, nil
+}
+
+func (p *parser) parsePrincipalOrResource(variable string, anyClause *bool, eq **eid.EID, in **eid.EID, is *string, isIn **eid.EID, slot *string) error {
+	if _, err := p.expect(variable); err != nil {
+		return err
+	}
+
+	if (p.is("==") || p.is("in")) && p.peekAt(1).kind == tokenSlot {
+		op := p.next().text
+		tok := p.next()
+		if !p.slots {
+			return p.errorf(tok, "template slots such as '%s' are only permitted in policy templates", tok.text)
+		}
+		if tok.text != "?"+variable {
+			return p.errorf(tok, "expected slot '?%s', got '%s'", variable, tok.text)
+		}
+		*slot = op
+		return nil
+	}
+
+	switch {
+	case p.accept("=="):
+		uid, err := p.parseEntityUID()
+		if err != nil {
+			return err
+		}
+		*eq = newEID(uid)
+
+	case p.accept("in"):
+		uid, err := p.parseEntityUID()
+		if err != nil {
+			return err
+		}
+		*in = newEID(uid)
+
+	case p.is("is"):
+		tok := p.next()
+		if err := p.require(tok, featureIs); err != nil {
+			return err
+		}
+		typ, err := p.parsePath()
+		if err != nil {
+			return err
+		}
+		*is = typ
+		if p.accept("in") {
+			uid, err := p.parseEntityUID()
+			if err != nil {
+				return err
+			}
+			*isIn = newEID(uid)
+		}
+
+	default:
+		*anyClause = true
+	}
+
+	return nil
+}
+
+func (p *parser) parseAction(policy *Policy) error {
+	if _, err := p.expect("action"); err != nil {
+		return err
+	}
+
+	switch {
+	case p.accept("=="):
+		uid, err := p.parseEntityUID()
+		if err != nil {
+			return err
+		}
+		policy.Action = newEID(uid)
+
+	case p.accept("in"):
+		var entities []eid.EID
+		if p.accept("[") {
+			for !p.is("]") {
+				uid, err := p.parseEntityUID()
+				if err != nil {
+					return err
+				}
+				entities = append(entities, *newEID(uid))
+				if !p.accept(",") {
+					break
+				}
+			}
+			if _, err := p.expect("]"); err != nil {
+				return err
+			}
+		} else {
+			uid, err := p.parseEntityUID()
+			if err != nil {
+				return err
+			}
+			entities = append(entities, *newEID(uid))
+		}
+		if entities == nil {
+			entities = []eid.EID{}
+		}
+		policy.ActionIn = entities
+
+	default:
+		policy.AnyAction = true
+	}
+
+	return nil
+}
+
+func newEID(uid EntityUID) *eid.EID {
+	return &eid.EID{
+		Type: uid.Type,
+		ID:   uid.ID,
+	}
+}
+
+// parsePath parses an entity type or extension function name, such as 'CF::User'.
+func (p *parser) parsePath() (string, error) {
+	first, err := p.expectIdent()
+	if err != nil {
+		return "", err
+	}
+	if reserved[first.text] {
+		return "", p.errorf(first, "'%s' is a reserved identifier", first.text)
+	}
+
+	path := []string{first.text}
+	for p.is("::") && p.peekAt(1).kind == tokenIdent {
+		p.next()
+		path = append(path, p.next().text)
+	}
+	return strings.Join(path, "::"), nil
+}
+
+// parseEntityUID parses an entity reference such as 'CF::User::"alice"'.
+func (p *parser) parseEntityUID() (EntityUID, error) {
+	typ, err := p.parsePath()
+	if err != nil {
+		return EntityUID{}, err
+	}
+	if _, err := p.expect("::"); err != nil {
+		return EntityUID{}, err
+	}
+	id, err := p.expectString()
+	if err != nil {
+		return EntityUID{}, err
+	}
+	return EntityUID{Type: typ, ID: id}, nil
+}
+
+func (p *parser) parseExpr() (Expr, error) {
+	if !p.accept("if") {
+		return p.parseOr()
+	}
+
+	cond, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect("then"); err != nil {
+		return nil, err
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect("else"); err != nil {
+		return nil, err
+	}
+	els, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return IfExpr{Cond: cond, Then: then, Else: els}, nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: "||", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseRelation()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseRelation()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: "&&", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+var relationalOps = []string{"==", "!=", "<", "<=", ">", ">=", "in"}
+
+func (p *parser) parseRelation() (Expr, error) {
+	left, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range relationalOps {
+		if p.accept(op) {
+			right, err := p.parseAdd()
+			if err != nil {
+				return nil, err
+			}
+			return BinaryExpr{Op: op, Left: left, Right: right}, nil
+		}
+	}
+
+	switch {
+	case p.accept("has"):
+		tok := p.next()
+		switch tok.kind {
+		case tokenIdent:
+			return HasExpr{Left: left, Attr: tok.text}, nil
+		case tokenString:
+			attr, err := eid.Unquote(tok.text)
+			if err != nil {
+				return nil, p.errorf(tok, "%s", err)
+			}
+			return HasExpr{Left: left, Attr: attr}, nil
+		}
+		return nil, p.errorf(tok, "expected attribute name after 'has', got %s", describe(tok))
+
+	case p.accept("like"):
+		tok := p.next()
+		if tok.kind != tokenString {
+			return nil, p.errorf(tok, "expected pattern string after 'like', got %s", describe(tok))
+		}
+		pattern, err := unquotePattern(tok.text)
+		if err != nil {
+			return nil, p.errorf(tok, "%s", err)
+		}
+		return LikeExpr{Left: left, Pattern: pattern}, nil
+
+	case p.is("is"):
+		if err := p.require(p.next(), featureIs); err != nil {
+			return nil, err
+		}
+		typ, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		is := IsExpr{Left: left, EntityType: typ}
+		if p.accept("in") {
+			in, err := p.parseAdd()
+			if err != nil {
+				return nil, err
+			}
+			is.In = in
+		}
+		return is, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAdd() (Expr, error) {
+	left, err := p.parseMult()
+	if err != nil {
+		return nil, err
+	}
+	for p.is("+") || p.is("-") {
+		op := p.next().text
+		right, err := p.parseMult()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMult() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("*") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: "*", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return UnaryExpr{Op: "!", Operand: operand}, nil
+	}
+
+	if p.is("-") {
+		minus := p.next()
+
+		// negative integer literals are folded, so that the smallest
+		// long can be written without overflowing.
+		if tok := p.peek(); tok.kind == tokenInt {
+			p.next()
+			n, err := strconv.ParseUint(tok.text, 10, 64)
+			if err != nil || n > uint64(math.MaxInt64)+1 {
+				return nil, p.errorf(minus, "integer literal -%s is out of range", tok.text)
+			}
+			lit := LiteralExpr{Value: Long(-int64(n))}
+			if n == uint64(math.MaxInt64)+1 {
+				lit = LiteralExpr{Value: Long(math.MinInt64)}
+			}
+			return p.parseAccess(lit)
+		}
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return UnaryExpr{Op: "-", Operand: operand}, nil
+	}
+
+	return p.parseMember()
+}
+
+func (p *parser) parseMember() (Expr, error) {
+	primary, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return p.parseAccess(primary)
+}
+
+// parseAccess parses any attribute accesses and method calls following an expression.
+func (p *parser) parseAccess(expr Expr) (Expr, error) {
+	for {
+		switch {
+		case p.accept("."):
+			name, err := p.expectIdent()
+			if err != nil {
+				return nil, err
+			}
+			if p.accept("(") {
+				args, err := p.parseExprList(")")
+				if err != nil {
+					return nil, err
+				}
+				if name.text == "hasTag" || name.text == "getTag" {
+					if err := p.require(name, featureEntityTags); err != nil {
+						return nil, err
+					}
+				}
+				expr = MethodCallExpr{Receiver: expr, Method: name.text, Args: args}
+			} else {
+				expr = AccessExpr{Left: expr, Attr: name.text}
+			}
+
+		case p.is("[") && p.peekAt(1).kind == tokenString:
+			p.next()
+			attr, err := p.expectString()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			expr = AccessExpr{Left: expr, Attr: attr}
+
+		default:
+			return expr, nil
+		}
+	}
+}
+
+// parseExprList parses a comma-separated list of expressions, up to and including the closing token.
+func (p *parser) parseExprList(closing string) ([]Expr, error) {
+	var exprs []Expr
+	for !p.is(closing) {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if _, err := p.expect(closing); err != nil {
+		return nil, err
+	}
+	return exprs, nil
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.peek()
+
+	switch tok.kind {
+	case tokenInt:
+		p.next()
+		n, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "integer literal %s is out of range", tok.text)
+		}
+		return LiteralExpr{Value: Long(n)}, nil
+
+	case tokenString:
+		s, err := p.expectString()
+		if err != nil {
+			return nil, err
+		}
+		return LiteralExpr{Value: String(s)}, nil
+
+	case tokenIdent:
+		switch {
+		case tok.text == "true" || tok.text == "false":
+			p.next()
+			return LiteralExpr{Value: Bool(tok.text == "true")}, nil
+
+		case variables[tok.text] && !p.nextIs(1, "::"):
+			p.next()
+			return VarExpr{Name: tok.text}, nil
+		}
+
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		if p.accept("(") {
+			if path == "datetime" || path == "duration" {
+				if err := p.require(tok, featureDatetime); err != nil {
+					return nil, err
+				}
+			}
+			argTok := p.peek()
+			args, err := p.parseExprList(")")
+			if err != nil {
+				return nil, err
+			}
+			if err := checkConstructor(path, args); err != nil {
+				return nil, p.rejectf(argTok, err)
+			}
+			return CallExpr{Func: path, Args: args}, nil
+		}
+		if !p.is("::") {
+			return nil, p.errorf(tok, "unexpected identifier '%s'", path)
+		}
+		p.next()
+		id, err := p.expectString()
+		if err != nil {
+			return nil, err
+		}
+		return EntityExpr{UID: EntityUID{Type: path, ID: id}}, nil
+
+	case tokenPunct:
+		switch tok.text {
+		case "(":
+			p.next()
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return expr, nil
+
+		case "[":
+			p.next()
+			elems, err := p.parseExprList("]")
+			if err != nil {
+				return nil, err
+			}
+			return SetExpr{Elements: elems}, nil
+
+		case "{":
+			p.next()
+			return p.parseRecord()
+		}
+	}
+
+	return nil, p.errorf(tok, "unexpected %s", describe(tok))
+}
+
+// constructors parse the argument of each extension constructor function.
+var constructors = map[string]func(string) (Value, error){
+	"ip":       func(s string) (Value, error) { return ParseIPAddr(s) },
+	"decimal":  func(s string) (Value, error) { return ParseDecimal(s) },
+	"datetime": func(s string) (Value, error) { return ParseDatetime(s) },
+	"duration": func(s string) (Value, error) { return ParseDuration(s) },
+}
+
+// checkConstructor returns an error if a call to an extension constructor,
+// such as 'ip("10.0.0.1")', has a literal argument which is not valid, so
+// that the error is reported when the policy is parsed rather than when it
+// is evaluated. Arguments which are not literals can't be checked.
+func checkConstructor(name string, args []Expr) error {
+	parse, ok := constructors[name]
+	if !ok {
+		return nil
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("'%s' expects a single string argument, got %d arguments", name, len(args))
+	}
+	lit, ok := args[0].(LiteralExpr)
+	if !ok {
+		return nil
+	}
+	s, ok := lit.Value.(String)
+	if !ok {
+		return fmt.Errorf("'%s' expects a string argument, got %s", name, lit.Value.TypeName())
+	}
+	_, err := parse(string(s))
+	return err
+}
+
+func (p *parser) nextIs(n int, text string) bool {
+	tok := p.peekAt(n)
+	return tok.kind == tokenPunct && tok.text == text
+}
+
+func (p *parser) parseRecord() (Expr, error) {
+	var record RecordExpr
+	for !p.is("}") {
+		tok := p.next()
+		var key string
+		switch tok.kind {
+		case tokenIdent:
+			key = tok.text
+		case tokenString:
+			k, err := eid.Unquote(tok.text)
+			if err != nil {
+				return nil, p.errorf(tok, "%s", err)
+			}
+			key = k
+		default:
+			return nil, p.errorf(tok, "expected record key, got %s", describe(tok))
+		}
+		if _, err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		record.Fields = append(record.Fields, RecordField{Key: key, Value: value})
+		if !p.accept(",") {
+			break
+		}
+	}
+	if _, err := p.expect("}"); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,225 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePolicySet(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []Policy
+		wantErr string
+	}{
+		{
+			name: "allow_all",
+			text: `permit (principal, action, resource);`,
+			want: []Policy{
+				{
+					Effect:       "permit",
+					AnyPrincipal: true,
+					AnyAction:    true,
+					AnyResource:  true,
+				},
+			},
+		},
+		{
+			name: "scopes_and_conditions",
+			text: `// a comment
+@id("eng-read")
+@advice("test")
+forbid (
+	principal in CF::Group::"eng",
+	action in [Action::"Read", Action::"List"],
+	resource is Document,
+)
+when { resource.owner != principal }
+unless {
+	context.admin
+};
+
+permit (principal is User, action == Action::"Write", resource == Document::"a\"b");`,
+			want: []Policy{
+				{
+					Effect: "forbid",
+					Annotations: []Annotation{
+						{Name: "id", Value: "eng-read"},
+						{Name: "advice", Value: "test"},
+					},
+					PrincipalIn: &eid.EID{Type: "CF::Group", ID: "eng"},
+					ActionIn: []eid.EID{
+						{Type: "Action", ID: "Read"},
+						{Type: "Action", ID: "List"},
+					},
+					ResourceIs: "Document",
+					When:       []Condition{{Text: "resource.owner != principal"}},
+					Unless:     []Condition{{Text: "context.admin"}},
+				},
+				{
+					Effect:      "permit",
+					PrincipalIs: "User",
+					Action:      &eid.EID{Type: "Action", ID: "Write"},
+					Resource:    &eid.EID{Type: "Document", ID: `a"b`},
+				},
+			},
+		},
+		{
+			name:    "missing_semicolon",
+			text:    `permit (principal, action, resource)`,
+			wantErr: "1:37: expected ';', got end of input",
+		},
+		{
+			name:    "invalid_condition",
+			text:    "permit (principal, action, resource)\nwhen { resource.owner == };",
+			wantErr: "2:26: unexpected '}'",
+		},
+		{
+			name: "is_in_scope",
+			text: `permit (principal is User in Group::"eng", action, resource is Document in Folder::"shared");`,
+			want: []Policy{
+				{
+					Effect:        "permit",
+					PrincipalIs:   "User",
+					PrincipalIsIn: &eid.EID{Type: "Group", ID: "eng"},
+					AnyAction:     true,
+					ResourceIs:    "Document",
+					ResourceIsIn:  &eid.EID{Type: "Folder", ID: "shared"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicySet(tt.text)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePolicySet_RoundTrip(t *testing.T) {
+	policy := Policy{
+		Effect:      "permit",
+		Annotations: []Annotation{{Name: "advice", Value: "test"}},
+		PrincipalIn: &eid.EID{Type: "CF::User", ID: "user1"},
+		ActionIn:    []eid.EID{{Type: "Action::Access", ID: "Request"}},
+		AnyResource: true,
+		When:        []Condition{{Text: `resource.tags.contains("prod") && context.ip.isInRange(ip("10.0.0.0/8"))`}},
+	}
+
+	text, err := policy.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParsePolicy(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, policy, got)
+}
+
+func TestParsePolicySet_RoundTripEscapes(t *testing.T) {
+	src := `@id("a\"b")
+@advice("line\nbreak \\ \u{200b}")
+permit (
+	principal == CF::User::"x\u{200b}",
+	action in [CF::Action::"Read \"all\""],
+	resource in CF::Folder::"tab\there"
+);`
+
+	policy, err := ParsePolicy(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "a\"b", policy.Annotations[0].Value)
+	assert.Equal(t, "x\u200b", policy.Principal.ID)
+
+	text, err := policy.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParsePolicy(text)
+	if err != nil {
+		t.Fatalf("unable to parse the rendered policy %s: %s", text, err)
+	}
+	assert.Equal(t, policy, got)
+}
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		text string
+		want Expr
+	}{
+		{
+			text: `principal in Group::"eng" || -1 < context.count`,
+			want: BinaryExpr{
+				Op:    "||",
+				Left:  BinaryExpr{Op: "in", Left: VarExpr{Name: "principal"}, Right: EntityExpr{UID: EntityUID{Type: "Group", ID: "eng"}}},
+				Right: BinaryExpr{Op: "<", Left: LiteralExpr{Value: Long(-1)}, Right: AccessExpr{Left: VarExpr{Name: "context"}, Attr: "count"}},
+			},
+		},
+		{
+			text: `resource["name"] like "*.txt" && resource has owner`,
+			want: BinaryExpr{
+				Op:    "&&",
+				Left:  LikeExpr{Left: AccessExpr{Left: VarExpr{Name: "resource"}, Attr: "name"}, Pattern: Pattern{{Wildcard: true}, {Literal: ".txt"}}},
+				Right: HasExpr{Left: VarExpr{Name: "resource"}, Attr: "owner"},
+			},
+		},
+		{
+			text: `if context.tags.isEmpty() then {a: 1} else [decimal("1.5")]`,
+			want: IfExpr{
+				Cond: MethodCallExpr{Receiver: AccessExpr{Left: VarExpr{Name: "context"}, Attr: "tags"}, Method: "isEmpty"},
+				Then: RecordExpr{Fields: []RecordField{{Key: "a", Value: LiteralExpr{Value: Long(1)}}}},
+				Else: SetExpr{Elements: []Expr{CallExpr{Func: "decimal", Args: []Expr{LiteralExpr{Value: String("1.5")}}}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseExpr(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseExpr_ExtensionLiterals(t *testing.T) {
+	tests := []struct {
+		text    string
+		wantErr string
+	}{
+		{text: `context.ip.isInRange(ip("10.0.0.0/8")) && context.amount.lessThan(decimal("1.2345"))`},
+		{text: `context.now < datetime("2024-10-15") + duration("1d")`},
+		{text: `ip(context.addr).isLoopback()`},
+		{text: `context.ip.isInRange(ip("10.0.0.300"))`, wantErr: `1:25: invalid IP address "10.0.0.300"`},
+		{text: `decimal("1.23456")`, wantErr: `1:9: invalid decimal "1.23456": must have between one and four digits after the decimal point`},
+		{text: `context.now < datetime("2024-10-15 11:35")`, wantErr: `1:24: invalid datetime "2024-10-15 11:35": expected a date such as '2024-10-15', or a date and time such as '2024-10-15T11:35:00Z' or '2024-10-15T11:35:00.000+0100'`},
+		{text: `duration("1h1d")`, wantErr: `1:10: invalid duration "1h1d": each number must be followed by one of the units 'd', 'h', 'm', 's' or 'ms', in that order`},
+		{text: `ip(1)`, wantErr: `1:4: 'ip' expects a string argument, got long`},
+		{text: `decimal("1.0", "2.0")`, wantErr: `1:9: 'decimal' expects a single string argument, got 2 arguments`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := ParseExpr(tt.text)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -1,37 +1,90 @@
+// Package cedarpolicy renders, parses, formats and validates Cedar policies.
+// It is plain Go with no dependency on Terraform, so it can be used by other
+// Go programs as well as by the provider, which converts its configuration
+// into Policy values.
 package cedarpolicy
 
 import (
+	"fmt"
+
 	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
-	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+// Condition is a 'when' or 'unless' condition of a policy.
 type Condition struct {
-	Text types.String `tfsdk:"text"`
+	Text string
 }
 
+// Annotation is an annotation on a policy, such as '@id("admins")'.
 type Annotation struct {
-	Name  types.String `tfsdk:"name"`
-	Value types.String `tfsdk:"value"`
+	Name  string
+	Value string
 }
 
+// Policy is a Cedar policy. Exactly one of the principal, action and resource
+// fields of the scope should be set, as checked when the policy is rendered.
 type Policy struct {
-	Effect      types.String `tfsdk:"effect"`
-	Annotations []Annotation `tfsdk:"annotation"`
+	Effect      string
+	Annotations []Annotation
+
+	AnyPrincipal bool
+	Principal    *eid.EID
+	PrincipalIn  *eid.EID
+	PrincipalIs  string
+	// PrincipalIsIn is set with PrincipalIs for 'principal is <type> in <entity>' scopes.
+	PrincipalIsIn *eid.EID
+
+	AnyAction bool
+	Action    *eid.EID
+	// ActionIn is nil unless the action scope is 'action in [...]'.
+	ActionIn []eid.EID
+
+	AnyResource bool
+	Resource    *eid.EID
+	ResourceIn  *eid.EID
+	ResourceIs  string
+	// ResourceIsIn is set with ResourceIs for 'resource is <type> in <entity>' scopes.
+	ResourceIsIn *eid.EID
 
-	AnyPrincipal types.Bool   `tfsdk:"any_principal"`
-	Principal    *eid.EID     `tfsdk:"principal"`
-	PrincipalIn  *eid.EID     `tfsdk:"principal_in"`
-	PrincipalIs  types.String `tfsdk:"principal_is"`
+	When   []Condition
+	Unless []Condition
 
-	AnyAction types.Bool `tfsdk:"any_action"`
-	Action    *eid.EID   `tfsdk:"action"`
-	ActionIn  *[]eid.EID `tfsdk:"action_in"`
+	// PrincipalSlot and ResourceSlot are set on policy templates where the
+	// scope refers to the '?principal' or '?resource' slot rather than an
+	// entity. They hold the scope operator, either '==' or 'in'.
+	PrincipalSlot string
+	ResourceSlot  string
+}
+
+// CombineIsIn turns 'in' clauses which are set along with an 'is' clause
+// into a single 'is <type> in <entity>' clause, so that callers can set
+// PrincipalIs and PrincipalIn together rather than PrincipalIsIn.
+func (p *Policy) CombineIsIn() {
+	if p.PrincipalIs != "" && p.PrincipalIn != nil {
+		p.PrincipalIsIn, p.PrincipalIn = p.PrincipalIn, nil
+	}
+	if p.ResourceIs != "" && p.ResourceIn != nil {
+		p.ResourceIsIn, p.ResourceIn = p.ResourceIn, nil
+	}
+}
 
-	AnyResource types.Bool   `tfsdk:"any_resource"`
-	Resource    *eid.EID     `tfsdk:"resource"`
-	ResourceIn  *eid.EID     `tfsdk:"resource_in"`
-	ResourceIs  types.String `tfsdk:"resource_is"`
+// ID returns the identifier of the policy. If the policy has an
+// '@id' annotation its value is used, otherwise the ID falls back
+// to the Cedar default of 'policy' followed by the index of the
+// policy in the set.
+func (p Policy) ID(index int) string {
+	if id, ok := p.explicitID(); ok {
+		return id
+	}
+	return fmt.Sprintf("policy%d", index)
+}
 
-	When   []Condition `tfsdk:"when"`
-	Unless []Condition `tfsdk:"unless"`
+// explicitID returns the value of the '@id' annotation, if present.
+func (p Policy) explicitID() (string, bool) {
+	for _, anno := range p.Annotations {
+		if anno.Name == "id" && anno.Value != "" {
+			return anno.Value, true
+		}
+	}
+	return "", false
 }
@@ -0,0 +1,187 @@
+package cedarpolicy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// PermissionSet is a named list of actions which can be granted to roles.
+type PermissionSet struct {
+	Name    string
+	Actions []string
+}
+
+// Role grants the actions in its permission sets to the members of its groups.
+// If ResourceIn is set, the role only applies to resources in that entity.
+// If ResourceIs is set, the role only applies to resources of that type.
+type Role struct {
+	Name           string
+	Groups         []string
+	PermissionSets []string
+	ResourceIn     *EntityUID
+	ResourceIs     string
+}
+
+// OwnerGrant grants actions on a resource to the principal which owns it,
+// given by an attribute of the resource. If Actions is empty, all actions
+// are granted.
+type OwnerGrant struct {
+	Name       string
+	Attribute  string
+	Actions    []string
+	ResourceIs string
+}
+
+// RBAC describes role-based access control grants, which are
+// turned into Cedar policies by Policies.
+type RBAC struct {
+	// GroupType is the entity type of groups. Defaults to 'Group'.
+	GroupType string
+	// ActionType is the entity type of actions. Defaults to 'Action'.
+	ActionType string
+
+	PermissionSets []PermissionSet
+	Roles          []Role
+	Owners         []OwnerGrant
+}
+
+// Policies returns a policy for each group of each role, with the ID
+// '<role>.<group>' and a '@role' annotation, followed by a policy for
+// each owner grant, with the name of the grant as its ID.
+func (r RBAC) Policies() ([]Policy, error) {
+	groupType := r.GroupType
+	if groupType == "" {
+		groupType = "Group"
+	}
+
+	permissionSets := map[string][]string{}
+	for _, set := range r.PermissionSets {
+		if _, ok := permissionSets[set.Name]; ok {
+			return nil, fmt.Errorf("duplicate permission set %q", set.Name)
+		}
+		permissionSets[set.Name] = set.Actions
+	}
+
+	var policies []Policy
+	ids := map[string]bool{}
+	add := func(id string, policy Policy) error {
+		if ids[id] {
+			return fmt.Errorf("duplicate policy ID %q", id)
+		}
+		ids[id] = true
+		policies = append(policies, policy)
+		return nil
+	}
+
+	for _, role := range r.Roles {
+		if len(role.Groups) == 0 {
+			return nil, fmt.Errorf("role %q: at least one group must be specified", role.Name)
+		}
+		if role.ResourceIn != nil && role.ResourceIs != "" {
+			return nil, fmt.Errorf("role %q: only one of 'resource_in' or 'resource_is' may be specified", role.Name)
+		}
+
+		var actions []string
+		for _, name := range role.PermissionSets {
+			set, ok := permissionSets[name]
+			if !ok {
+				return nil, fmt.Errorf("role %q: unknown permission set %q", role.Name, name)
+			}
+			for _, action := range set {
+				if !slices.Contains(actions, action) {
+					actions = append(actions, action)
+				}
+			}
+		}
+		if len(actions) == 0 {
+			return nil, fmt.Errorf("role %q: the permission sets do not contain any actions", role.Name)
+		}
+
+		for _, group := range role.Groups {
+			id := role.Name + "." + group
+			policy := Policy{
+				Effect: "permit",
+				Annotations: []Annotation{
+					{Name: "id", Value: id},
+					{Name: "role", Value: role.Name},
+				},
+				PrincipalIn: newEID(EntityUID{Type: groupType, ID: group}),
+				ActionIn:    r.actions(actions),
+			}
+			setResource(&policy, role.ResourceIn, role.ResourceIs)
+
+			if err := add(id, policy); err != nil {
+				return nil, fmt.Errorf("role %q: %w", role.Name, err)
+			}
+		}
+	}
+
+	for _, owner := range r.Owners {
+		if owner.Attribute == "" {
+			return nil, fmt.Errorf("owner grant %q: 'attribute' must be specified", owner.Name)
+		}
+
+		policy := Policy{
+			Effect: "permit",
+			Annotations: []Annotation{
+				{Name: "id", Value: owner.Name},
+			},
+			AnyPrincipal: true,
+			When: []Condition{{
+				Text: fmt.Sprintf("resource has %s && resource%s == principal", formatAttrName(owner.Attribute), accessor(owner.Attribute)),
+			}},
+		}
+		if len(owner.Actions) == 0 {
+			policy.AnyAction = true
+		} else {
+			policy.ActionIn = r.actions(owner.Actions)
+		}
+		setResource(&policy, nil, owner.ResourceIs)
+
+		if err := add(owner.Name, policy); err != nil {
+			return nil, fmt.Errorf("owner grant %q: %w", owner.Name, err)
+		}
+	}
+
+	if len(policies) == 0 {
+		return nil, errors.New("at least one role or owner grant must be specified")
+	}
+	return policies, nil
+}
+
+// actions returns the action entities for the action IDs.
+func (r RBAC) actions(ids []string) []eid.EID {
+	actionType := r.ActionType
+	if actionType == "" {
+		actionType = "Action"
+	}
+	actions := make([]eid.EID, len(ids))
+	for i, id := range ids {
+		actions[i] = *newEID(EntityUID{Type: actionType, ID: id})
+	}
+	return actions
+}
+
+// setResource sets the resource scope of the policy.
+func setResource(policy *Policy, in *EntityUID, is string) {
+	switch {
+	case in != nil:
+		policy.ResourceIn = newEID(*in)
+	case is != "":
+		policy.ResourceIs = is
+	default:
+		policy.AnyResource = true
+	}
+}
+
+// accessor returns the Cedar syntax for accessing the attribute,
+// either '.attr' or '["attr"]'.
+func accessor(attr string) string {
+	if isIdentifier(attr) {
+		return "." + attr
+	}
+	return "[" + eid.Quote(attr) + "]"
+}
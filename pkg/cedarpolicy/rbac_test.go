@@ -0,0 +1,67 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRBAC(t *testing.T) {
+	rbac := RBAC{
+		PermissionSets: []PermissionSet{
+			{Name: "viewer", Actions: []string{"Read", "List"}},
+			{Name: "editor", Actions: []string{"Read", "Write"}},
+		},
+		Roles: []Role{
+			{
+				Name:           "editors",
+				Groups:         []string{"engineering", "design"},
+				PermissionSets: []string{"viewer", "editor"},
+				ResourceIn:     &EntityUID{Type: "Folder", ID: "shared"},
+			},
+		},
+		Owners: []OwnerGrant{
+			{Name: "owner", Attribute: "owner", ResourceIs: "Document"},
+		},
+	}
+
+	policies, err := rbac.Policies()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := FormatPolicySet(policies, FormatOptions{Indent: "  "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `@id("editors.engineering")
+@role("editors")
+permit (
+  principal in Group::"engineering",
+  action in [Action::"Read", Action::"List", Action::"Write"],
+  resource in Folder::"shared"
+);
+
+@id("editors.design")
+@role("editors")
+permit (
+  principal in Group::"design",
+  action in [Action::"Read", Action::"List", Action::"Write"],
+  resource in Folder::"shared"
+);
+
+@id("owner")
+permit (
+  principal,
+  action,
+  resource is Document
+)
+when {
+  resource has owner && resource.owner == principal
+};
+`, text)
+
+	rbac.Roles[0].PermissionSets = []string{"admin"}
+	_, err = rbac.Policies()
+	assert.ErrorContains(t, err, `role "editors": unknown permission set "admin"`)
+}
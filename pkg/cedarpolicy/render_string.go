@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
 )
 
 // RenderString renders a text-based representation of the policy.
@@ -13,8 +15,8 @@ func (p Policy) RenderString() (string, error) {
 	// create annotations in the format
 	// @name("value")
 	for _, anno := range p.Annotations {
-		name := anno.Name.ValueString()
-		value := anno.Value.ValueString()
+		name := anno.Name
+		value := anno.Value
 
 		if name == "" {
 			return "", errors.New("policy annotation 'name' field must be specified")
@@ -23,11 +25,11 @@ func (p Policy) RenderString() (string, error) {
 			return "", errors.New("policy annotation 'value' field must be specified")
 		}
 
-		line := fmt.Sprintf(`@%s("%s")`, name, value)
+		line := fmt.Sprintf("@%s(%s)", name, eid.Quote(value))
 		output = append(output, line)
 	}
 
-	effect := p.Effect.ValueString()
+	effect := p.Effect
 
 	if effect != "permit" && effect != "forbid" {
 		return "", fmt.Errorf("effect must be either 'permit' or 'forbid', got %q", effect)
@@ -39,8 +41,8 @@ func (p Policy) RenderString() (string, error) {
 	if p.Principal != nil {
 		// principal == <entity>,
 
-		principalType := p.Principal.Type.ValueString()
-		principalID := p.Principal.ID.ValueString()
+		principalType := p.Principal.Type
+		principalID := p.Principal.ID
 
 		if principalType == "" {
 			return "", errors.New("principal type must be specified")
@@ -50,13 +52,13 @@ func (p Policy) RenderString() (string, error) {
 			return "", errors.New("principal ID must be specified")
 		}
 
-		line := fmt.Sprintf("\tprincipal == %s::%q,", principalType, principalID)
+		line := fmt.Sprintf("\tprincipal == %s,", p.Principal)
 		output = append(output, line)
 	} else if p.PrincipalIn != nil {
 		// principal in <entity>,
 
-		typ := p.PrincipalIn.Type.ValueString()
-		id := p.PrincipalIn.ID.ValueString()
+		typ := p.PrincipalIn.Type
+		id := p.PrincipalIn.ID
 
 		if typ == "" {
 			return "", fmt.Errorf("principal_in: type must be specified")
@@ -65,13 +67,19 @@ func (p Policy) RenderString() (string, error) {
 			return "", fmt.Errorf("principal_in: ID must be specified")
 		}
 
-		line := fmt.Sprintf("\tprincipal in %s::%q,", typ, id)
+		line := fmt.Sprintf("\tprincipal in %s,", p.PrincipalIn)
 		output = append(output, line)
-	} else if p.PrincipalIs.ValueString() != "" {
+	} else if p.PrincipalIs != "" {
 		// principal is <entity type>,
-		line := fmt.Sprintf("\tprincipal is %s,", p.PrincipalIs.ValueString())
+		line := fmt.Sprintf("\tprincipal is %s,", p.PrincipalIs)
+		if p.PrincipalIsIn != nil {
+			line = fmt.Sprintf("\tprincipal is %s in %s,", p.PrincipalIs, p.PrincipalIsIn)
+		}
 		output = append(output, line)
-	} else if p.AnyPrincipal.ValueBool() {
+	} else if p.PrincipalSlot != "" {
+		// principal == ?principal,
+		output = append(output, fmt.Sprintf("\tprincipal %s ?principal,", p.PrincipalSlot))
+	} else if p.AnyPrincipal {
 		// principal,
 		output = append(output, "\tprincipal,")
 	}
@@ -79,8 +87,8 @@ func (p Policy) RenderString() (string, error) {
 	if p.Action != nil {
 		// action == <entity>,
 
-		typ := p.Action.Type.ValueString()
-		id := p.Action.ID.ValueString()
+		typ := p.Action.Type
+		id := p.Action.ID
 
 		if typ == "" {
 			return "", errors.New("action type must be specified")
@@ -90,16 +98,16 @@ func (p Policy) RenderString() (string, error) {
 			return "", errors.New("action ID must be specified")
 		}
 
-		line := fmt.Sprintf("\taction == %s::%q,", typ, id)
+		line := fmt.Sprintf("\taction == %s,", p.Action)
 		output = append(output, line)
 	} else if p.ActionIn != nil {
 		// action == [<entity>, <entity>],
 
-		entities := make([]string, len(*p.ActionIn))
+		entities := make([]string, len(p.ActionIn))
 
-		for i, ent := range *p.ActionIn {
-			typ := ent.Type.ValueString()
-			id := ent.ID.ValueString()
+		for i, ent := range p.ActionIn {
+			typ := ent.Type
+			id := ent.ID
 
 			if typ == "" {
 				return "", fmt.Errorf("action_in entry %v: type must be specified", i)
@@ -107,12 +115,12 @@ func (p Policy) RenderString() (string, error) {
 			if id == "" {
 				return "", fmt.Errorf("action_in entry %v: ID must be specified", i)
 			}
-			entities[i] = fmt.Sprintf(`%s::"%s"`, typ, id)
+			entities[i] = ent.String()
 		}
 
 		line := fmt.Sprintf("\taction in [%s],", strings.Join(entities, ", "))
 		output = append(output, line)
-	} else if p.AnyAction.ValueBool() {
+	} else if p.AnyAction {
 		// action,
 		output = append(output, "\taction,")
 	}
@@ -120,8 +128,8 @@ func (p Policy) RenderString() (string, error) {
 	if p.Resource != nil {
 		// resource == <entity>,
 
-		resourceType := p.Resource.Type.ValueString()
-		resourceID := p.Resource.ID.ValueString()
+		resourceType := p.Resource.Type
+		resourceID := p.Resource.ID
 
 		if resourceType == "" {
 			return "", errors.New("resource type must be specified")
@@ -131,13 +139,13 @@ func (p Policy) RenderString() (string, error) {
 			return "", errors.New("resource ID must be specified")
 		}
 
-		line := fmt.Sprintf("\tresource == %s::%q", resourceType, resourceID)
+		line := fmt.Sprintf("\tresource == %s", p.Resource)
 		output = append(output, line)
 	} else if p.ResourceIn != nil {
 		// resource in <entity>, <entity>,
 
-		typ := p.ResourceIn.Type.ValueString()
-		id := p.ResourceIn.ID.ValueString()
+		typ := p.ResourceIn.Type
+		id := p.ResourceIn.ID
 
 		if typ == "" {
 			return "", fmt.Errorf("resource_in: type must be specified")
@@ -146,13 +154,19 @@ func (p Policy) RenderString() (string, error) {
 			return "", fmt.Errorf("resource_in: ID must be specified")
 		}
 
-		line := fmt.Sprintf("\tresource in %s::%q", typ, id)
+		line := fmt.Sprintf("\tresource in %s", p.ResourceIn)
 		output = append(output, line)
-	} else if p.ResourceIs.ValueString() != "" {
+	} else if p.ResourceIs != "" {
 		// resource is <entity type>,
-		line := fmt.Sprintf("\tresource is %s", p.ResourceIs.ValueString())
+		line := fmt.Sprintf("\tresource is %s", p.ResourceIs)
+		if p.ResourceIsIn != nil {
+			line = fmt.Sprintf("\tresource is %s in %s", p.ResourceIs, p.ResourceIsIn)
+		}
 		output = append(output, line)
-	} else if p.AnyResource.ValueBool() {
+	} else if p.ResourceSlot != "" {
+		// resource == ?resource
+		output = append(output, fmt.Sprintf("\tresource %s ?resource", p.ResourceSlot))
+	} else if p.AnyResource {
 		// resource,
 		output = append(output, "\tresource")
 	}
@@ -162,7 +176,7 @@ func (p Policy) RenderString() (string, error) {
 
 	// when conditions
 	for i, when := range p.When {
-		text := when.Text.ValueString()
+		text := when.Text
 		if text == "" {
 			return "", fmt.Errorf("when condition index %v: 'text' must be specified", i)
 		}
@@ -174,7 +188,7 @@ func (p Policy) RenderString() (string, error) {
 
 	// unless conditions
 	for i, unless := range p.Unless {
-		text := unless.Text.ValueString()
+		text := unless.Text
 		if text == "" {
 			return "", fmt.Errorf("unless condition index %v: 'text' must be specified", i)
 		}
@@ -4,7 +4,6 @@ import (
 	"testing"
 
 	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
-	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,10 +18,10 @@ func TestRenderString(t *testing.T) {
 		{
 			name: "allow_all",
 			policy: Policy{
-				Effect:       types.StringValue("permit"),
-				AnyPrincipal: types.BoolValue(true),
-				AnyAction:    types.BoolValue(true),
-				AnyResource:  types.BoolValue(true),
+				Effect:       "permit",
+				AnyPrincipal: true,
+				AnyAction:    true,
+				AnyResource:  true,
 			},
 			want: `permit (
 	principal,
@@ -33,16 +32,16 @@ func TestRenderString(t *testing.T) {
 		{
 			name: "allow_all_with_advice",
 			policy: Policy{
-				Effect: types.StringValue("permit"),
+				Effect: "permit",
 				Annotations: []Annotation{
 					{
-						Name:  types.StringValue("advice"),
-						Value: types.StringValue("test"),
+						Name:  "advice",
+						Value: "test",
 					},
 				},
-				AnyPrincipal: types.BoolValue(true),
-				AnyAction:    types.BoolValue(true),
-				AnyResource:  types.BoolValue(true),
+				AnyPrincipal: true,
+				AnyAction:    true,
+				AnyResource:  true,
 			},
 			want: `@advice("test")
 permit (
@@ -54,18 +53,18 @@ permit (
 		{
 			name: "principal_action_resource_equals",
 			policy: Policy{
-				Effect: types.StringValue("permit"),
+				Effect: "permit",
 				Principal: &eid.EID{
-					Type: types.StringValue("CF::User"),
-					ID:   types.StringValue("user1"),
+					Type: "CF::User",
+					ID:   "user1",
 				},
 				Action: &eid.EID{
-					Type: types.StringValue("Action::Access"),
-					ID:   types.StringValue("Request"),
+					Type: "Action::Access",
+					ID:   "Request",
 				},
 				Resource: &eid.EID{
-					Type: types.StringValue("Test::Vault"),
-					ID:   types.StringValue("test1"),
+					Type: "Test::Vault",
+					ID:   "test1",
 				},
 			},
 			want: `permit (
@@ -77,21 +76,21 @@ permit (
 		{
 			name: "when",
 			policy: Policy{
-				Effect: types.StringValue("permit"),
+				Effect: "permit",
 				Principal: &eid.EID{
-					Type: types.StringValue("CF::User"),
-					ID:   types.StringValue("user1"),
+					Type: "CF::User",
+					ID:   "user1",
 				},
 				Action: &eid.EID{
-					Type: types.StringValue("Action::Access"),
-					ID:   types.StringValue("Request"),
+					Type: "Action::Access",
+					ID:   "Request",
 				},
 				Resource: &eid.EID{
-					Type: types.StringValue("Test::Vault"),
-					ID:   types.StringValue("test1"),
+					Type: "Test::Vault",
+					ID:   "test1",
 				},
 				When: []Condition{
-					{Text: types.StringValue("true")},
+					{Text: "true"},
 				},
 			},
 			want: `permit (
@@ -107,22 +106,22 @@ when {
 		{
 			name: "unless",
 			policy: Policy{
-				Effect: types.StringValue("permit"),
+				Effect: "permit",
 				Principal: &eid.EID{
-					Type: types.StringValue("CF::User"),
-					ID:   types.StringValue("user1"),
+					Type: "CF::User",
+					ID:   "user1",
 				},
 				Action: &eid.EID{
-					Type: types.StringValue("Action::Access"),
-					ID:   types.StringValue("Request"),
+					Type: "Action::Access",
+					ID:   "Request",
 				},
 				Resource: &eid.EID{
-					Type: types.StringValue("Test::Vault"),
-					ID:   types.StringValue("test1"),
+					Type: "Test::Vault",
+					ID:   "test1",
 				},
 
 				Unless: []Condition{
-					{Text: types.StringValue("true")},
+					{Text: "true"},
 				},
 			},
 			want: `permit (
@@ -138,22 +137,22 @@ unless {
 		{
 			name: "principal_action_resource_in",
 			policy: Policy{
-				Effect: types.StringValue("permit"),
+				Effect: "permit",
 				PrincipalIn: &eid.EID{
-					Type: types.StringValue("CF::User"),
-					ID:   types.StringValue("user1"),
+					Type: "CF::User",
+					ID:   "user1",
 				},
 
-				ActionIn: &[]eid.EID{
+				ActionIn: []eid.EID{
 					{
-						Type: types.StringValue("Action::Access"),
-						ID:   types.StringValue("Request"),
+						Type: "Action::Access",
+						ID:   "Request",
 					},
 				},
 
 				ResourceIn: &eid.EID{
-					Type: types.StringValue("Test::Vault"),
-					ID:   types.StringValue("test1"),
+					Type: "Test::Vault",
+					ID:   "test1",
 				},
 			},
 			want: `permit (
@@ -165,46 +164,62 @@ unless {
 		{
 			name: "in_condition_multiple_values",
 			policy: Policy{
-				Effect: types.StringValue("permit"),
+				Effect: "permit",
 				PrincipalIn: &eid.EID{
-					Type: types.StringValue("CF::User"),
-					ID:   types.StringValue("user1"),
+					Type: "CF::User",
+					ID:   "user1",
 				},
 
-				ActionIn: &[]eid.EID{
+				ActionIn: []eid.EID{
 					{
-						Type: types.StringValue("Action::Access"),
-						ID:   types.StringValue("Request"),
+						Type: "Action::Access",
+						ID:   "Request",
 					},
 					{
-						Type: types.StringValue("Action::Access"),
-						ID:   types.StringValue("Close"),
+						Type: "Action::Access",
+						ID:   "Close",
 					},
 				},
 
 				ResourceIn: &eid.EID{
-					Type: types.StringValue("Test::Vault"),
-					ID:   types.StringValue("test1"),
+					Type: "Test::Vault",
+					ID:   "test1",
 				},
 			},
 			want: `permit (
 	principal in CF::User::"user1",
 	action in [Action::Access::"Request", Action::Access::"Close"],
 	resource in Test::Vault::"test1"
+);`,
+		},
+		{
+			name: "principal_resource_is_in",
+			policy: Policy{
+				Effect:        "permit",
+				PrincipalIs:   "CF::User",
+				PrincipalIsIn: &eid.EID{Type: "CF::Group", ID: "eng"},
+				AnyAction:     true,
+				ResourceIs:    "Test::Vault",
+				ResourceIsIn:  &eid.EID{Type: "Test::Folder", ID: "shared"},
+			},
+			want: `permit (
+	principal is CF::User in CF::Group::"eng",
+	action,
+	resource is Test::Vault in Test::Folder::"shared"
 );`,
 		},
 		{
 			name: "principal_resource_is",
 			policy: Policy{
-				Effect:      types.StringValue("permit"),
-				PrincipalIs: types.StringValue("CF::User"),
+				Effect:      "permit",
+				PrincipalIs: "CF::User",
 
 				Action: &eid.EID{
-					Type: types.StringValue("Action::Access"),
-					ID:   types.StringValue("Request"),
+					Type: "Action::Access",
+					ID:   "Request",
 				},
 
-				ResourceIs: types.StringValue("Test::Vault"),
+				ResourceIs: "Test::Vault",
 			},
 			want: `permit (
 	principal is CF::User,
@@ -215,28 +230,28 @@ unless {
 		{
 			name: "test having multiple when conditions",
 			policy: Policy{
-				Effect: types.StringValue("permit"),
+				Effect: "permit",
 				Principal: &eid.EID{
-					Type: types.StringValue("CF::User"),
-					ID:   types.StringValue("user1"),
+					Type: "CF::User",
+					ID:   "user1",
 				},
 				Action: &eid.EID{
-					Type: types.StringValue("Action::Access"),
-					ID:   types.StringValue("Request"),
+					Type: "Action::Access",
+					ID:   "Request",
 				},
 				Resource: &eid.EID{
-					Type: types.StringValue("Test::Vault"),
-					ID:   types.StringValue("test1"),
+					Type: "Test::Vault",
+					ID:   "test1",
 				},
 				When: []Condition{
 					{
-						Text: types.StringValue("true"),
+						Text: "true",
 					},
 					{
-						Text: types.StringValue("test2"),
+						Text: "test2",
 					},
 					{
-						Text: types.StringValue("test3"),
+						Text: "test3",
 					},
 				},
 			},
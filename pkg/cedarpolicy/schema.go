@@ -0,0 +1,190 @@
+package cedarpolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Schema is a Cedar schema, parsed from the Cedar JSON schema format.
+// Entity type and action names are fully qualified with their namespace.
+type Schema struct {
+	EntityTypes map[string]EntityTypeSchema
+	Actions     map[EntityUID]ActionSchema
+}
+
+// EntityTypeSchema describes an entity type declared in a schema.
+type EntityTypeSchema struct {
+	MemberOfTypes []string
+}
+
+// ActionSchema describes an action declared in a schema.
+type ActionSchema struct {
+	PrincipalTypes []string
+	ResourceTypes  []string
+	MemberOf       []EntityUID
+}
+
+type jsonSchemaNamespace struct {
+	EntityTypes map[string]struct {
+		MemberOfTypes []string `json:"memberOfTypes"`
+	} `json:"entityTypes"`
+	Actions map[string]struct {
+		AppliesTo *struct {
+			PrincipalTypes []string `json:"principalTypes"`
+			ResourceTypes  []string `json:"resourceTypes"`
+		} `json:"appliesTo"`
+		MemberOf []struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"memberOf"`
+	} `json:"actions"`
+}
+
+// ParseSchema parses a schema in the Cedar JSON schema format.
+func ParseSchema(data []byte) (*Schema, error) {
+	var namespaces map[string]jsonSchemaNamespace
+	if err := json.Unmarshal(data, &namespaces); err != nil {
+		return nil, fmt.Errorf("parsing schema: %w", err)
+	}
+
+	schema := &Schema{
+		EntityTypes: map[string]EntityTypeSchema{},
+		Actions:     map[EntityUID]ActionSchema{},
+	}
+
+	for ns, namespace := range namespaces {
+		for name, et := range namespace.EntityTypes {
+			schema.EntityTypes[qualify(ns, name)] = EntityTypeSchema{
+				MemberOfTypes: qualifyAll(ns, et.MemberOfTypes),
+			}
+		}
+	}
+
+	for ns, namespace := range namespaces {
+		actionType := qualify(ns, "Action")
+
+		for name, action := range namespace.Actions {
+			var as ActionSchema
+			if action.AppliesTo != nil {
+				as.PrincipalTypes = schema.resolveAll(ns, action.AppliesTo.PrincipalTypes)
+				as.ResourceTypes = schema.resolveAll(ns, action.AppliesTo.ResourceTypes)
+			}
+			for _, m := range action.MemberOf {
+				typ := actionType
+				if m.Type != "" {
+					typ = schema.resolveActionType(ns, m.Type)
+				}
+				as.MemberOf = append(as.MemberOf, EntityUID{Type: typ, ID: m.ID})
+			}
+			schema.Actions[EntityUID{Type: actionType, ID: name}] = as
+		}
+	}
+
+	return schema, nil
+}
+
+// ActionUIDs returns the UIDs of all actions declared in the schema, sorted by type and ID.
+func (s *Schema) ActionUIDs() []EntityUID {
+	uids := make([]EntityUID, 0, len(s.Actions))
+	for uid := range s.Actions {
+		uids = append(uids, uid)
+	}
+	sortUIDs(uids)
+	return uids
+}
+
+// isActionGroup returns true if another action in the schema is a member of the action.
+func (s *Schema) isActionGroup(uid EntityUID) bool {
+	for _, action := range s.Actions {
+		for _, parent := range action.MemberOf {
+			if parent == uid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WithActions returns a copy of the entities which includes an entity for each
+// action declared in the schema, with the action groups it is a member of as
+// its parents, so that conditions such as 'action in Action::"ReadOnly"' are
+// evaluated using the schema's action hierarchy.
+func (s *Schema) WithActions(entities Entities) Entities {
+	out := make(Entities, len(entities)+len(s.Actions))
+	for uid, entity := range entities {
+		out[uid] = entity
+	}
+	for uid, action := range s.Actions {
+		entity := &Entity{UID: uid}
+		if existing, ok := out[uid]; ok {
+			entity.Attributes = existing.Attributes
+			entity.Parents = append(entity.Parents, existing.Parents...)
+		}
+		for _, parent := range action.MemberOf {
+			if !containsUID(entity.Parents, parent) {
+				entity.Parents = append(entity.Parents, parent)
+			}
+		}
+		out[uid] = entity
+	}
+	return out
+}
+
+func containsUID(list []EntityUID, uid EntityUID) bool {
+	for _, item := range list {
+		if item == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func qualify(ns, name string) string {
+	if ns == "" {
+		return name
+	}
+	return ns + "::" + name
+}
+
+func qualifyAll(ns string, names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		if strings.Contains(n, "::") {
+			out[i] = n
+		} else {
+			out[i] = qualify(ns, n)
+		}
+	}
+	return out
+}
+
+// resolveAll resolves entity type references made from within a namespace.
+// Unqualified names refer to types in the same namespace if declared there,
+// otherwise to types in the empty namespace.
+func (s *Schema) resolveAll(ns string, names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = s.resolve(ns, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (s *Schema) resolve(ns, name string) string {
+	if strings.Contains(name, "::") {
+		return name
+	}
+	if _, ok := s.EntityTypes[qualify(ns, name)]; ok {
+		return qualify(ns, name)
+	}
+	return name
+}
+
+func (s *Schema) resolveActionType(ns, name string) string {
+	if strings.Contains(name, "::") {
+		return name
+	}
+	return qualify(ns, name)
+}
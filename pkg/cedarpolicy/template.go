@@ -0,0 +1,69 @@
+package cedarpolicy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// ParseTemplate parses the Cedar text of a single policy template. The scope
+// of a template refers to the '?principal' slot, the '?resource' slot, or both,
+// for example 'permit (principal == ?principal, action, resource in ?resource);'.
+func ParseTemplate(src string) (Policy, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return Policy{}, err
+	}
+	p.slots = true
+
+	policy, err := p.parsePolicy()
+	if err != nil {
+		return Policy{}, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return Policy{}, p.errorf(tok, "expected a single policy template, got %s", describe(tok))
+	}
+	if !policy.IsTemplate() {
+		return Policy{}, errors.New("a policy template must refer to the '?principal' or '?resource' slot")
+	}
+	return policy, nil
+}
+
+// IsTemplate returns true if the policy scope refers to a template slot.
+func (p Policy) IsTemplate() bool {
+	return p.PrincipalSlot != "" || p.ResourceSlot != ""
+}
+
+// Link returns a static policy created from the template by filling its slots
+// with the principal and resource entities. An entity must be provided for each
+// slot the template refers to, and only for those slots.
+func (p Policy) Link(principal, resource *eid.EID) (Policy, error) {
+	link := func(variable, op string, e *eid.EID, eq, in **eid.EID) error {
+		if op == "" {
+			if e != nil {
+				return fmt.Errorf("the template does not have a '?%s' slot", variable)
+			}
+			return nil
+		}
+		if e == nil {
+			return fmt.Errorf("the template requires a %s for the '?%s' slot", variable, variable)
+		}
+		if op == "==" {
+			*eq = e
+		} else {
+			*in = e
+		}
+		return nil
+	}
+
+	if err := link("principal", p.PrincipalSlot, principal, &p.Principal, &p.PrincipalIn); err != nil {
+		return Policy{}, err
+	}
+	if err := link("resource", p.ResourceSlot, resource, &p.Resource, &p.ResourceIn); err != nil {
+		return Policy{}, err
+	}
+	p.PrincipalSlot = ""
+	p.ResourceSlot = ""
+	return p, nil
+}
@@ -0,0 +1,72 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTemplate(t *testing.T) {
+	template, err := ParseTemplate(`permit (principal == ?principal, action, resource in ?resource) when { resource.public };`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := template.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "permit (\n\tprincipal == ?principal,\n\taction,\n\tresource in ?resource\n)\nwhen {\n\tresource.public\n};", text)
+
+	policy, err := template.Link(
+		&eid.EID{Type: "User", ID: "alice"},
+		&eid.EID{Type: "Folder", ID: "docs"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err = policy.RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "permit (\n\tprincipal == User::\"alice\",\n\taction,\n\tresource in Folder::\"docs\"\n)\nwhen {\n\tresource.public\n};", text)
+
+	_, err = template.Link(nil, nil)
+	assert.EqualError(t, err, "the template requires a principal for the '?principal' slot")
+}
+
+func TestParseTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		parse   func(string) (Policy, error)
+		wantErr string
+	}{
+		{
+			name:    "slot_in_static_policy",
+			text:    `permit (principal == ?principal, action, resource);`,
+			parse:   ParsePolicy,
+			wantErr: "1:22: template slots such as '?principal' are only permitted in policy templates",
+		},
+		{
+			name:    "wrong_slot",
+			text:    `permit (principal == ?resource, action, resource);`,
+			parse:   ParseTemplate,
+			wantErr: "1:22: expected slot '?principal', got '?resource'",
+		},
+		{
+			name:    "no_slots",
+			text:    `permit (principal, action, resource);`,
+			parse:   ParseTemplate,
+			wantErr: "a policy template must refer to the '?principal' or '?resource' slot",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parse(tt.text)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ValidatePolicy checks a policy against the schema. It returns an error for each
+// entity type or action in the policy scope and conditions which is not declared
+// in the schema, and for principal and resource scopes which don't match the
+// types that the policy's actions apply to.
+func (s *Schema) ValidatePolicy(p Policy) []error {
+	var errs []error
+
+	actions := p.actionScope()
+	for _, uid := range actions.entities {
+		if _, ok := s.Actions[uid]; !ok {
+			errs = append(errs, fmt.Errorf("action: unknown action %s", uid))
+		}
+	}
+
+	errs = append(errs, s.validateScope("principal", p.principalScope(), actions, func(a ActionSchema) []string { return a.PrincipalTypes })...)
+	errs = append(errs, s.validateScope("resource", p.resourceScope(), actions, func(a ActionSchema) []string { return a.ResourceTypes })...)
+
+	for _, c := range []struct {
+		keyword    string
+		conditions []Condition
+	}{{"when", p.When}, {"unless", p.Unless}} {
+		for i, cond := range c.conditions {
+			expr, err := ParseExpr(cond.Text)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s condition index %v: %w", c.keyword, i, err))
+				continue
+			}
+			for _, err := range s.validateExpr(expr) {
+				errs = append(errs, fmt.Errorf("%s condition index %v: %w", c.keyword, i, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+func (s *Schema) validateScope(variable string, scope scopeConstraint, actions scopeConstraint, appliesTo func(ActionSchema) []string) []error {
+	var errs []error
+
+	var types []string
+	switch scope.op {
+	case scopeIs:
+		types = []string{scope.typ}
+		for _, uid := range scope.entities {
+			types = append(types, uid.Type)
+		}
+	case scopeEq, scopeIn:
+		for _, uid := range scope.entities {
+			types = append(types, uid.Type)
+		}
+	}
+
+	for _, typ := range types {
+		if _, ok := s.EntityTypes[typ]; !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown entity type %q", variable, typ))
+		}
+	}
+	if len(errs) > 0 || len(types) == 0 {
+		return errs
+	}
+
+	// 'principal in <entity>' matches descendants of the entity, which may
+	// have a different type, so only exact type matches are checked.
+	if scope.op == scopeIn || scope.op == scopeAny || actions.op == scopeAny {
+		return nil
+	}
+
+	// 'action in [...]' matches the listed actions and any actions which are members of them.
+	candidates := actions.entities
+	if actions.op == scopeIn {
+		candidates = s.actionsIn(actions.entities)
+	}
+
+	typ := types[0]
+	var allowed []string
+	known := false
+	for _, uid := range candidates {
+		action, ok := s.Actions[uid]
+		if !ok {
+			continue
+		}
+		known = true
+		if containsString(appliesTo(action), typ) {
+			return nil
+		}
+		for _, t := range appliesTo(action) {
+			if !containsString(allowed, t) {
+				allowed = append(allowed, t)
+			}
+		}
+	}
+	if !known {
+		// unknown actions have already been reported.
+		return nil
+	}
+	if len(allowed) == 0 {
+		return []error{fmt.Errorf("%s: the policy's actions do not apply to any %s types", variable, variable)}
+	}
+
+	sort.Strings(allowed)
+	return []error{fmt.Errorf("%s: entity type %q is not a %s type for the policy's actions, expected one of %q", variable, typ, variable, allowed)}
+}
+
+// actionsIn returns the actions which are equal to or members of any of the groups.
+func (s *Schema) actionsIn(groups []EntityUID) []EntityUID {
+	var out []EntityUID
+	for _, uid := range s.ActionUIDs() {
+		for _, group := range groups {
+			if s.actionIn(uid, group, map[EntityUID]bool{}) {
+				out = append(out, uid)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func (s *Schema) actionIn(uid, group EntityUID, seen map[EntityUID]bool) bool {
+	if uid == group {
+		return true
+	}
+	if seen[uid] {
+		return false
+	}
+	seen[uid] = true
+	for _, parent := range s.Actions[uid].MemberOf {
+		if s.actionIn(parent, group, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) validateExpr(expr Expr) []error {
+	var errs []error
+	Walk(expr, func(e Expr) {
+		switch x := e.(type) {
+		case EntityExpr:
+			if IsActionType(x.UID.Type) {
+				if _, ok := s.Actions[x.UID]; !ok {
+					errs = append(errs, fmt.Errorf("unknown action %s", x.UID))
+				}
+			} else if _, ok := s.EntityTypes[x.UID.Type]; !ok {
+				errs = append(errs, fmt.Errorf("unknown entity type %q", x.UID.Type))
+			}
+		case IsExpr:
+			if _, ok := s.EntityTypes[x.EntityType]; !ok {
+				errs = append(errs, fmt.Errorf("unknown entity type %q", x.EntityType))
+			}
+		}
+	})
+	return errs
+}
@@ -0,0 +1,91 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchema = `{
+	"CF": {
+		"entityTypes": {
+			"User": {"memberOfTypes": ["Group"]},
+			"Group": {},
+			"Document": {}
+		},
+		"actions": {
+			"Read": {"appliesTo": {"principalTypes": ["User"], "resourceTypes": ["Document"]}, "memberOf": [{"id": "All"}]},
+			"Write": {"appliesTo": {"principalTypes": ["User"], "resourceTypes": ["Document"]}, "memberOf": [{"id": "All"}]},
+			"All": {}
+		}
+	}
+}`
+
+func TestValidatePolicy(t *testing.T) {
+	schema, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "valid",
+			text: `permit (principal is CF::User, action in [CF::Action::"All"], resource in CF::Group::"eng")
+when { principal in CF::Group::"eng" && resource is CF::Document };`,
+		},
+		{
+			name: "unknown_types",
+			text: `permit (principal == CF::Usr::"alice", action == CF::Action::"Raed", resource)
+when { principal in CF::Grp::"eng" };`,
+			want: []string{
+				`action: unknown action CF::Action::"Raed"`,
+				`principal: unknown entity type "CF::Usr"`,
+				`when condition index 0: unknown entity type "CF::Grp"`,
+			},
+		},
+		{
+			name: "wrong_principal_type",
+			text: `permit (principal is CF::Document, action == CF::Action::"Read", resource is CF::User);`,
+			want: []string{
+				`principal: entity type "CF::Document" is not a principal type for the policy's actions, expected one of ["CF::User"]`,
+				`resource: entity type "CF::User" is not a resource type for the policy's actions, expected one of ["CF::Document"]`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParsePolicy(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, err := range schema.ValidatePolicy(policy) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithNamespace(t *testing.T) {
+	policy, err := ParsePolicy(`permit (principal is User, action in [Action::"Read", Other::Action::"Write"], resource == CF::Document::"plan");`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := policy.WithNamespace("CF").RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, `permit (
+	principal is CF::User,
+	action in [CF::Action::"Read", Other::Action::"Write"],
+	resource == CF::Document::"plan"
+);`, got)
+}
@@ -0,0 +1,62 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimeAttribute is the request attribute which time-bounded
+// policies compare against, unless another attribute is configured.
+const DefaultTimeAttribute = "context.now"
+
+// Validity is the time window in which a policy applies, such as a
+// just-in-time access grant. A zero bound leaves that side of the window open.
+type Validity struct {
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// IsZero returns true if neither bound of the window is set.
+func (v Validity) IsZero() bool {
+	return v.NotBefore.IsZero() && v.NotAfter.IsZero()
+}
+
+// Check returns an error if the window ends before it begins.
+func (v Validity) Check() error {
+	if !v.NotBefore.IsZero() && !v.NotAfter.IsZero() && v.NotAfter.Before(v.NotBefore) {
+		return fmt.Errorf("not_after %s is before not_before %s", v.NotAfter.Format(time.RFC3339), v.NotBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Expired returns true if the window ended before the given time.
+func (v Validity) Expired(now time.Time) bool {
+	return !v.NotAfter.IsZero() && v.NotAfter.Before(now)
+}
+
+// Conditions returns the 'when' conditions which restrict a policy to the window,
+// comparing the window's bounds with attr, which must evaluate to a datetime,
+// for example:
+//
+//	context.now >= datetime("2024-10-15T09:00:00Z")
+func (v Validity) Conditions(attr string) []Condition {
+	var conditions []Condition
+	if !v.NotBefore.IsZero() {
+		conditions = append(conditions, Condition{Text: fmt.Sprintf("%s >= %s", attr, NewDatetime(v.NotBefore))})
+	}
+	if !v.NotAfter.IsZero() {
+		conditions = append(conditions, Condition{Text: fmt.Sprintf("%s <= %s", attr, NewDatetime(v.NotAfter))})
+	}
+	return conditions
+}
+
+// WithValidity returns a copy of the policy which only applies within the window.
+// The conditions for the window are added before the policy's other 'when' conditions.
+func (p Policy) WithValidity(attr string, v Validity) Policy {
+	conditions := v.Conditions(attr)
+	if len(conditions) == 0 {
+		return p
+	}
+	p.When = append(conditions, p.When...)
+	return p
+}
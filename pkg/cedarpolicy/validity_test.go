@@ -0,0 +1,70 @@
+package cedarpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithValidity(t *testing.T) {
+	policy, err := ParsePolicy(`permit (principal, action, resource) when { resource.public };`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := Validity{
+		NotBefore: time.Date(2024, 10, 15, 9, 0, 0, 0, time.FixedZone("", 3600)),
+		NotAfter:  time.Date(2024, 10, 15, 17, 30, 0, 500*int(time.Millisecond), time.UTC),
+	}
+	got, err := policy.WithValidity(DefaultTimeAttribute, v).RenderString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `permit (
+	principal,
+	action,
+	resource
+)
+when {
+	context.now >= datetime("2024-10-15T08:00:00Z")
+}
+when {
+	context.now <= datetime("2024-10-15T17:30:00.500Z")
+}
+when {
+	resource.public
+};`, got)
+
+	assert.False(t, v.Expired(v.NotAfter))
+	assert.True(t, v.Expired(v.NotAfter.Add(time.Millisecond)))
+	assert.False(t, Validity{NotBefore: v.NotBefore}.Expired(time.Now()))
+
+	assert.NoError(t, v.Check())
+	assert.EqualError(t, Validity{NotBefore: v.NotAfter, NotAfter: v.NotBefore}.Check(), "not_after 2024-10-15T09:00:00+01:00 is before not_before 2024-10-15T17:30:00Z")
+
+	// the conditions are satisfied within the window.
+	unconditional, err := Permit().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizer, err := NewAuthorizer([]Policy{unconditional.WithValidity(DefaultTimeAttribute, v)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		now  time.Time
+		want Decision
+	}{
+		{v.NotBefore.Add(-time.Second), Deny},
+		{v.NotBefore, Allow},
+		{v.NotAfter, Allow},
+		{v.NotAfter.Add(time.Second), Deny},
+	} {
+		resp := authorizer.IsAuthorized(Entities{}, Request{
+			Resource: EntityUID{Type: "Doc", ID: "a"},
+			Context:  Record{"now": NewDatetime(tt.now)},
+		})
+		assert.Equal(t, tt.want, resp.Decision, tt.now)
+	}
+}
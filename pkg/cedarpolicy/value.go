@@ -0,0 +1,381 @@
+package cedarpolicy
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// Value is a Cedar runtime value.
+type Value interface {
+	// TypeName returns the Cedar type of the value, used in error messages.
+	TypeName() string
+	// String renders the value as a Cedar expression.
+	String() string
+}
+
+// Bool is a Cedar boolean.
+type Bool bool
+
+// Long is a Cedar 64-bit signed integer.
+type Long int64
+
+// String is a Cedar string.
+type String string
+
+// EntityUID is the unique identifier of a Cedar entity, made up of its type and ID.
+type EntityUID struct {
+	Type string
+	ID   string
+}
+
+// Set is a Cedar set. Sets are unordered and duplicate elements are ignored
+// when comparing sets.
+type Set []Value
+
+// Record is a Cedar record.
+type Record map[string]Value
+
+// IPAddr is a value of the Cedar 'ipaddr' extension type.
+// Single addresses are represented as a prefix covering only that address.
+type IPAddr netip.Prefix
+
+// Decimal is a value of the Cedar 'decimal' extension type,
+// stored as a fixed-point number with four decimal places.
+type Decimal int64
+
+// Datetime is a value of the Cedar 'datetime' extension type,
+// stored as the number of milliseconds since the Unix epoch.
+type Datetime int64
+
+// Duration is a value of the Cedar 'duration' extension type,
+// stored as a number of milliseconds.
+type Duration int64
+
+func (Bool) TypeName() string      { return "bool" }
+func (Long) TypeName() string      { return "long" }
+func (String) TypeName() string    { return "string" }
+func (EntityUID) TypeName() string { return "entity" }
+func (Set) TypeName() string       { return "set" }
+func (Record) TypeName() string    { return "record" }
+func (IPAddr) TypeName() string    { return "ipaddr" }
+func (Decimal) TypeName() string   { return "decimal" }
+func (Datetime) TypeName() string  { return "datetime" }
+func (Duration) TypeName() string  { return "duration" }
+
+func (v Bool) String() string   { return strconv.FormatBool(bool(v)) }
+func (v Long) String() string   { return strconv.FormatInt(int64(v), 10) }
+func (v String) String() string { return eid.Quote(string(v)) }
+
+func (v EntityUID) String() string {
+	return v.Type + "::" + eid.Quote(v.ID)
+}
+
+func (v Set) String() string {
+	elems := make([]string, len(v))
+	for i, e := range v {
+		elems[i] = e.String()
+	}
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+func (v Record) String() string {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, len(keys))
+	for i, k := range keys {
+		fields[i] = eid.Quote(k) + ": " + v[k].String()
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+func (v IPAddr) String() string {
+	return fmt.Sprintf("ip(%s)", eid.Quote(v.Literal()))
+}
+
+// Literal returns the address as it would be passed to the 'ip' extension function.
+func (v IPAddr) Literal() string {
+	p := netip.Prefix(v)
+	if p.Bits() == p.Addr().BitLen() {
+		return p.Addr().String()
+	}
+	return p.String()
+}
+
+func (v Decimal) String() string {
+	return fmt.Sprintf("decimal(%s)", eid.Quote(v.Literal()))
+}
+
+// Literal returns the decimal as it would be passed to the 'decimal' extension function.
+func (v Decimal) Literal() string {
+	n := int64(v)
+	sign := ""
+	// avoid overflow when negating the smallest decimal.
+	u := uint64(n)
+	if n < 0 {
+		sign = "-"
+		u = uint64(-(n + 1)) + 1
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%04d", u%10000), "0")
+	if frac == "" {
+		frac = "0"
+	}
+	return fmt.Sprintf("%s%d.%s", sign, u/10000, frac)
+}
+
+func (v Datetime) String() string {
+	return fmt.Sprintf("datetime(%s)", eid.Quote(v.Literal()))
+}
+
+// Literal returns the datetime as it would be passed to the 'datetime'
+// extension function. Datetimes are written in UTC, with milliseconds
+// only if they are not zero.
+func (v Datetime) Literal() string {
+	t := v.Time()
+	if t.Nanosecond() != 0 {
+		return t.Format("2006-01-02T15:04:05.000Z")
+	}
+	return t.Format("2006-01-02T15:04:05Z")
+}
+
+// Time returns the datetime as a UTC time.
+func (v Datetime) Time() time.Time {
+	return time.UnixMilli(int64(v)).UTC()
+}
+
+// NewDatetime returns the datetime for a time, truncated to milliseconds.
+func NewDatetime(t time.Time) Datetime {
+	return Datetime(t.UnixMilli())
+}
+
+// datetimeLayouts are the formats accepted by the Cedar 'datetime' extension function.
+var datetimeLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05.000Z",
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05.000-0700",
+}
+
+// ParseDatetime parses the argument of the Cedar 'datetime' extension function,
+// which is a date such as '2024-10-15', or a date and time with either a 'Z'
+// suffix or a UTC offset such as '2024-10-15T11:35:00.000+0100'.
+func ParseDatetime(s string) (Datetime, error) {
+	for _, layout := range datetimeLayouts {
+		if len(s) != len(layout) {
+			continue
+		}
+		if t, err := time.Parse(layout, s); err == nil {
+			return NewDatetime(t), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid datetime %q: expected a date such as '2024-10-15', or a date and time such as '2024-10-15T11:35:00Z' or '2024-10-15T11:35:00.000+0100'", s)
+}
+
+func (v Duration) String() string {
+	return fmt.Sprintf("duration(%s)", eid.Quote(v.Literal()))
+}
+
+// durationUnits are the units of a Cedar duration, in the order they must be written.
+var durationUnits = []struct {
+	name string
+	ms   int64
+}{
+	{"d", 24 * 60 * 60 * 1000},
+	{"h", 60 * 60 * 1000},
+	{"m", 60 * 1000},
+	{"s", 1000},
+	{"ms", 1},
+}
+
+// Literal returns the duration as it would be passed to the 'duration'
+// extension function, such as '1d2h30m'. Units which are zero are left out.
+func (v Duration) Literal() string {
+	n := int64(v)
+	if n == 0 {
+		return "0ms"
+	}
+	var b strings.Builder
+	// avoid overflow when negating the smallest duration.
+	u := uint64(n)
+	if n < 0 {
+		b.WriteString("-")
+		u = uint64(-(n + 1)) + 1
+	}
+	for _, unit := range durationUnits {
+		if count := u / uint64(unit.ms); count > 0 {
+			fmt.Fprintf(&b, "%d%s", count, unit.name)
+			u %= uint64(unit.ms)
+		}
+	}
+	return b.String()
+}
+
+// ParseDuration parses the argument of the Cedar 'duration' extension function,
+// which is an optional '-' followed by a number of days, hours, minutes, seconds
+// and milliseconds, in that order, such as '1d2h30m' or '-250ms'. Each unit may
+// be given at most once, and units which are zero may be left out.
+func ParseDuration(s string) (Duration, error) {
+	rest := strings.TrimPrefix(s, "-")
+	negative := len(rest) < len(s)
+	if rest == "" {
+		return 0, fmt.Errorf("invalid duration %q: expected a duration such as '1d2h30m' or '250ms'", s)
+	}
+
+	var total uint64
+	next := 0
+	for rest != "" {
+		digits := 0
+		for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+			digits++
+		}
+		if digits == 0 {
+			return 0, fmt.Errorf("invalid duration %q: expected a number at %q", s, rest)
+		}
+		count, err := strconv.ParseUint(rest[:digits], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("duration %q is out of range", s)
+		}
+		rest = rest[digits:]
+
+		unit := -1
+		for i := next; i < len(durationUnits); i++ {
+			// 'm' is a prefix of 'ms', so the longer unit is checked for first.
+			if name := durationUnits[i].name; strings.HasPrefix(rest, name) && !(name == "m" && strings.HasPrefix(rest, "ms")) {
+				unit = i
+				break
+			}
+		}
+		if unit == -1 {
+			return 0, fmt.Errorf("invalid duration %q: each number must be followed by one of the units 'd', 'h', 'm', 's' or 'ms', in that order", s)
+		}
+		rest = rest[len(durationUnits[unit].name):]
+		next = unit + 1
+
+		ms := uint64(durationUnits[unit].ms)
+		if count > (math.MaxInt64+1)/ms || count*ms > math.MaxInt64+1-total {
+			return 0, fmt.Errorf("duration %q is out of range", s)
+		}
+		total += count * ms
+	}
+
+	if total > math.MaxInt64 {
+		if !negative {
+			return 0, fmt.Errorf("duration %q is out of range", s)
+		}
+		return Duration(math.MinInt64), nil
+	}
+	if negative {
+		return Duration(-int64(total)), nil
+	}
+	return Duration(total), nil
+}
+
+// ParseIPAddr parses the argument of the Cedar 'ip' extension function,
+// which is either an IPv4 or IPv6 address or a CIDR range.
+func ParseIPAddr(s string) (IPAddr, error) {
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return IPAddr{}, fmt.Errorf("invalid IP address or range %q", s)
+		}
+		return IPAddr(p), nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil || addr.Zone() != "" {
+		return IPAddr{}, fmt.Errorf("invalid IP address %q", s)
+	}
+	return IPAddr(netip.PrefixFrom(addr, addr.BitLen())), nil
+}
+
+// ParseDecimal parses the argument of the Cedar 'decimal' extension function.
+// Decimals must contain a decimal point followed by between one and four digits.
+func ParseDecimal(s string) (Decimal, error) {
+	whole, frac, ok := strings.Cut(s, ".")
+	if !ok || len(frac) == 0 || len(frac) > 4 || whole == "" || whole == "-" {
+		return 0, fmt.Errorf("invalid decimal %q: must have between one and four digits after the decimal point", s)
+	}
+
+	negative := strings.HasPrefix(whole, "-")
+	digits := strings.TrimPrefix(whole, "-")
+	for _, c := range digits + frac {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+
+	for len(frac) < 4 {
+		frac += "0"
+	}
+
+	n, err := strconv.ParseInt(digits+frac, 10, 64)
+	if err != nil {
+		// the smallest decimal can't be parsed before negation.
+		if negative && digits+frac == "9223372036854775808" {
+			return Decimal(math.MinInt64), nil
+		}
+		return 0, fmt.Errorf("decimal %q is out of range", s)
+	}
+	if negative {
+		n = -n
+	}
+	return Decimal(n), nil
+}
+
+// ValuesEqual returns true if two Cedar values are equal.
+// Values of different types are never equal.
+func ValuesEqual(a, b Value) bool {
+	switch a := a.(type) {
+	case Set:
+		b, ok := b.(Set)
+		if !ok {
+			return false
+		}
+		return setContainsAll(a, b) && setContainsAll(b, a)
+
+	case Record:
+		b, ok := b.(Record)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !ValuesEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return a == b
+}
+
+func setContains(set Set, v Value) bool {
+	for _, e := range set {
+		if ValuesEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// setContainsAll returns true if every element of sub is in set.
+func setContainsAll(set, sub Set) bool {
+	for _, e := range sub {
+		if !setContains(set, e) {
+			return false
+		}
+	}
+	return true
+}
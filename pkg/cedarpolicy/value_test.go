@@ -0,0 +1,40 @@
+package cedarpolicy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Duration
+		literal string
+		wantErr string
+	}{
+		{in: "1d2h3m4s5ms", want: Duration(93784005), literal: "1d2h3m4s5ms"},
+		{in: "90m", want: Duration(5400000), literal: "1h30m"},
+		{in: "-250ms", want: Duration(-250), literal: "-250ms"},
+		{in: "0s", want: Duration(0), literal: "0ms"},
+		{in: "-9223372036854775808ms", want: Duration(math.MinInt64), literal: "-106751991167d7h12m55s808ms"},
+		{in: "9223372036854775808ms", wantErr: `duration "9223372036854775808ms" is out of range`},
+		{in: "", wantErr: `invalid duration "": expected a duration such as '1d2h30m' or '250ms'`},
+		{in: "1h2", wantErr: `invalid duration "1h2": each number must be followed by one of the units 'd', 'h', 'm', 's' or 'ms', in that order`},
+		{in: "1s1s", wantErr: `invalid duration "1s1s": each number must be followed by one of the units 'd', 'h', 'm', 's' or 'ms', in that order`},
+		{in: "h", wantErr: `invalid duration "h": expected a number at "h"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDuration(tt.in)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.literal, got.Literal())
+		})
+	}
+}
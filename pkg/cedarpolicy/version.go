@@ -0,0 +1,139 @@
+package cedarpolicy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a version of the Cedar language, such as 3.4, which policies
+// are written for. The zero Version is the latest version, which permits
+// all of the syntax supported by this package.
+type Version struct {
+	Major int
+	Minor int
+}
+
+// ParseVersion parses a version such as '3', '3.4' or '3.4.1'.
+// The patch version is ignored, as the syntax doesn't change between patches.
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid Cedar version %q, expected a version such as '3.4'", s)
+	}
+	var nums []int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid Cedar version %q, expected a version such as '3.4'", s)
+		}
+		nums = append(nums, n)
+	}
+	v := Version{Major: nums[0]}
+	if len(nums) > 1 {
+		v.Minor = nums[1]
+	}
+	if v.Major < 2 {
+		return Version{}, fmt.Errorf("unsupported Cedar version %q, the earliest supported version is 2.0", s)
+	}
+	return v, nil
+}
+
+// IsZero returns true if the version is the zero Version.
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+func (v Version) String() string {
+	if v.IsZero() {
+		return "latest"
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// AtLeast returns true if the version is the same as or later than other.
+// The zero Version is later than every other version.
+func (v Version) AtLeast(other Version) bool {
+	if v.IsZero() {
+		return true
+	}
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	return v.Minor >= other.Minor
+}
+
+// feature is Cedar syntax which is not supported by every version.
+type feature struct {
+	name  string
+	since Version
+}
+
+var (
+	featureIs                   = feature{"the 'is' operator", Version{3, 0}}
+	featureEntityTags           = feature{"entity tags ('hasTag' and 'getTag')", Version{4, 0}}
+	featureValuelessAnnotations = feature{"annotations without a value", Version{4, 2}}
+	featureDatetime             = feature{"the 'datetime' and 'duration' extensions", Version{4, 3}}
+)
+
+// check returns an error if the version doesn't support the feature.
+func (v Version) check(f feature) error {
+	if v.AtLeast(f.since) {
+		return nil
+	}
+	return fmt.Errorf("Cedar %s does not support %s (added in Cedar %s)", v, f.name, f.since)
+}
+
+// ParsePolicySet parses Cedar policy text in the same way as ParsePolicySet,
+// returning an error if it uses syntax which the version doesn't support.
+func (v Version) ParsePolicySet(src string) ([]Policy, error) {
+	return parsePolicySet(src, false, v)
+}
+
+// ParsePolicy parses the Cedar text of a single policy in the same way as ParsePolicy,
+// returning an error if it uses syntax which the version doesn't support.
+func (v Version) ParsePolicy(src string) (Policy, error) {
+	policies, err := v.ParsePolicySet(src)
+	if err != nil {
+		return Policy{}, err
+	}
+	if len(policies) != 1 {
+		return Policy{}, fmt.Errorf("expected a single policy, got %d", len(policies))
+	}
+	return policies[0], nil
+}
+
+// ParseExpr parses a Cedar expression in the same way as ParseExpr,
+// returning an error if it uses syntax which the version doesn't support.
+func (v Version) ParseExpr(src string) (Expr, error) {
+	return parseExpr(src, v)
+}
+
+// CheckPolicy returns an error if the policy can't be rendered for the version,
+// such as a 'principal is' scope for Cedar 2.x, or a condition which uses the
+// 'datetime' extension before it was introduced. Conditions are also checked
+// for extension literals which are not valid, such as 'ip("10.0.0.300")'.
+//
+// Conditions which this package can't parse are not checked, as the parser
+// doesn't handle all Cedar syntax and they may still be valid.
+func (v Version) CheckPolicy(p Policy) error {
+	if p.PrincipalIs != "" || p.ResourceIs != "" {
+		if err := v.check(featureIs); err != nil {
+			return err
+		}
+	}
+	for _, c := range []struct {
+		keyword    string
+		conditions []Condition
+	}{{"when", p.When}, {"unless", p.Unless}} {
+		for i, cond := range c.conditions {
+			_, err := v.ParseExpr(cond.Text)
+			var parseErr *ParseError
+			if errors.As(err, &parseErr) && parseErr.Err != nil {
+				return fmt.Errorf("%s condition index %v: %w", c.keyword, i, err)
+			}
+		}
+	}
+	return nil
+}
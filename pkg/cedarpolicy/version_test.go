@@ -0,0 +1,120 @@
+package cedarpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Version
+		wantErr string
+	}{
+		{in: "2", want: Version{2, 0}},
+		{in: "3.4", want: Version{3, 4}},
+		{in: "4.3.1", want: Version{4, 3}},
+		{in: "v4", wantErr: `invalid Cedar version "v4", expected a version such as '3.4'`},
+		{in: "1.0", wantErr: `unsupported Cedar version "1.0", the earliest supported version is 2.0`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseVersion(tt.in)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersion_ParsePolicySet(t *testing.T) {
+	tests := []struct {
+		name    string
+		version Version
+		src     string
+		wantErr string
+	}{
+		{
+			name:    "is_scope",
+			version: Version{2, 4},
+			src:     `permit (principal is User, action, resource);`,
+			wantErr: "1:19: Cedar 2.4 does not support the 'is' operator (added in Cedar 3.0)",
+		},
+		{
+			name:    "is_scope_supported",
+			version: Version{3, 0},
+			src:     `permit (principal is User, action, resource);`,
+		},
+		{
+			name:    "is_expr",
+			version: Version{2, 4},
+			src:     `permit (principal, action, resource) when { resource is Document };`,
+			wantErr: "1:54: Cedar 2.4 does not support the 'is' operator (added in Cedar 3.0)",
+		},
+		{
+			name:    "entity_tags",
+			version: Version{3, 4},
+			src:     `permit (principal, action, resource) when { resource.hasTag("owner") };`,
+			wantErr: "1:54: Cedar 3.4 does not support entity tags ('hasTag' and 'getTag') (added in Cedar 4.0)",
+		},
+		{
+			name:    "valueless_annotation",
+			version: Version{4, 1},
+			src:     `@readonly permit (principal, action, resource);`,
+			wantErr: "1:2: Cedar 4.1 does not support annotations without a value (added in Cedar 4.2)",
+		},
+		{
+			name:    "datetime",
+			version: Version{4, 0},
+			src:     `permit (principal, action, resource) when { context.now < datetime("2024-10-15") };`,
+			wantErr: "1:59: Cedar 4.0 does not support the 'datetime' and 'duration' extensions (added in Cedar 4.3)",
+		},
+		{
+			name: "latest",
+			src:  `@readonly permit (principal is User, action, resource) when { resource.hasTag("owner") && context.now < datetime("2024-10-15") };`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.version.ParsePolicySet(tt.src)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVersion_CheckPolicy(t *testing.T) {
+	policy, err := Permit().Principal(Is("User")).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualError(t, Version{2, 4}.CheckPolicy(policy), "Cedar 2.4 does not support the 'is' operator (added in Cedar 3.0)")
+	assert.NoError(t, Version{3, 0}.CheckPolicy(policy))
+
+	policy, err = Permit().When(`context.now < datetime("2024-10-15")`).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.EqualError(t, Version{4, 0}.CheckPolicy(policy), "when condition index 0: 1:15: Cedar 4.0 does not support the 'datetime' and 'duration' extensions (added in Cedar 4.3)")
+
+	policy = Policy{Effect: "permit", Unless: []Condition{{Text: `ip("10.0.0.300").isLoopback()`}}}
+	assert.EqualError(t, Version{}.CheckPolicy(policy), `unless condition index 0: 1:4: invalid IP address "10.0.0.300"`)
+
+	// conditions which can't be parsed are not checked.
+	policy = Policy{Effect: "permit", When: []Condition{{Text: `context has a.b`}}}
+	assert.NoError(t, Version{}.CheckPolicy(policy))
+	assert.NoError(t, Version{3, 0}.CheckPolicy(policy))
+}
+
+func TestParsePolicy_ValuelessAnnotation(t *testing.T) {
+	policy, err := ParsePolicy(`@readonly @id("a") permit (principal, action, resource);`)
+	assert.NoError(t, err)
+	assert.Equal(t, []Annotation{{Name: "readonly"}, {Name: "id", Value: "a"}}, policy.Annotations)
+}
@@ -0,0 +1,91 @@
+package eid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse parses a Cedar entity UID string, such as 'CF::User::"alice"', into an EID.
+// The entity type is a path of identifiers separated by '::', and the ID is a
+// Cedar string literal, which may contain escape sequences such as '\"' or '\u{1F600}'.
+// Whitespace between the components of the UID is ignored.
+func Parse(s string) (EID, error) {
+	start := strings.IndexByte(s, '"')
+	if start < 0 {
+		return EID{}, fmt.Errorf("invalid entity UID %q: expected a quoted ID, such as 'User::\"alice\"'", s)
+	}
+
+	path := strings.TrimSpace(s[:start])
+	if !strings.HasSuffix(path, "::") {
+		return EID{}, fmt.Errorf("invalid entity UID %q: expected '::' before the ID", s)
+	}
+
+	typ, err := parsePath(strings.TrimSuffix(path, "::"))
+	if err != nil {
+		return EID{}, fmt.Errorf("invalid entity UID %q: %w", s, err)
+	}
+
+	end := literalEnd(s[start:])
+	if end < 0 {
+		return EID{}, fmt.Errorf("invalid entity UID %q: unterminated ID string", s)
+	}
+	id, err := Unquote(s[start : start+end])
+	if err != nil {
+		return EID{}, fmt.Errorf("invalid entity UID %q: %w", s, err)
+	}
+	rest := s[start+end:]
+	if strings.TrimSpace(rest) != "" {
+		return EID{}, fmt.Errorf("invalid entity UID %q: unexpected %q after the ID", s, strings.TrimSpace(rest))
+	}
+
+	return EID{Type: typ, ID: id}, nil
+}
+
+// String renders the EID as a Cedar entity UID, such as 'CF::User::"alice"'.
+func (e EID) String() string {
+	return e.Type + "::" + Quote(e.ID)
+}
+
+// parsePath validates an entity type path, returning it with whitespace removed.
+func parsePath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("missing entity type")
+	}
+
+	parts := strings.Split(path, "::")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if !isIdentifier(part) {
+			return "", fmt.Errorf("invalid entity type %q", path)
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "::"), nil
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// literalEnd returns the length of the Cedar string literal at the start
+// of s, including the surrounding quotes, or -1 if it is unterminated.
+func literalEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
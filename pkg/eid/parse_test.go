@@ -0,0 +1,77 @@
+package eid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		uid     string
+		want    EID
+		wantErr string
+	}{
+		{
+			name: "simple",
+			uid:  `User::"alice"`,
+			want: EID{Type: "User", ID: "alice"},
+		},
+		{
+			name: "namespaced_with_whitespace",
+			uid:  ` CF :: User :: "alice" `,
+			want: EID{Type: "CF::User", ID: "alice"},
+		},
+		{
+			name: "escaped_id",
+			uid:  `CF::Document::"a \"quoted\" \\ path\n\u{1F600}\x41"`,
+			want: EID{Type: "CF::Document", ID: "a \"quoted\" \\ path\n\U0001F600A"},
+		},
+		{
+			name:    "missing_id",
+			uid:     `CF::User`,
+			wantErr: `invalid entity UID "CF::User": expected a quoted ID, such as 'User::"alice"'`,
+		},
+		{
+			name:    "invalid_type",
+			uid:     `CF::1User::"alice"`,
+			wantErr: `invalid entity UID "CF::1User::\"alice\"": invalid entity type "CF::1User"`,
+		},
+		{
+			name:    "trailing_text",
+			uid:     `User::"alice"::"bob"`,
+			wantErr: `invalid entity UID "User::\"alice\"::\"bob\"": unexpected "::\"bob\"" after the ID`,
+		},
+		{
+			name:    "invalid_escape",
+			uid:     `User::"a\q"`,
+			wantErr: `invalid entity UID "User::\"a\\q\"": invalid escape sequence '\q' in string "a\q"`,
+		},
+		{
+			name:    "unterminated",
+			uid:     `User::"alice`,
+			wantErr: `invalid entity UID "User::\"alice": unterminated ID string`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.uid)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			// the EID renders back to an equivalent UID string.
+			roundTrip, err := Parse(got.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, got, roundTrip)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package eid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Quote renders s as a Cedar string literal, escaping characters as required.
+func Quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case 0:
+			b.WriteString(`\0`)
+		default:
+			if unicode.IsPrint(r) {
+				b.WriteRune(r)
+			} else {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Unquote decodes a Cedar string literal, including the surrounding quotes.
+func Unquote(lit string) (string, error) {
+	var b strings.Builder
+	err := DecodeString(lit, false, func(r rune, wildcard bool) {
+		b.WriteRune(r)
+	})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// DecodeString decodes the escape sequences in a Cedar string literal,
+// including the surrounding quotes, calling emit for each decoded character.
+// If pattern is true the literal is the pattern of a 'like' operator, in which
+// unescaped '*' characters are emitted as wildcards and '\*' is a literal '*'.
+func DecodeString(lit string, pattern bool, emit func(r rune, wildcard bool)) error {
+	if len(lit) < 2 || lit[0] != '"' || lit[len(lit)-1] != '"' {
+		return fmt.Errorf("invalid string literal %s", lit)
+	}
+	s := lit[1 : len(lit)-1]
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r != '\\' {
+			emit(r, pattern && r == '*')
+			i += size
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return fmt.Errorf("invalid escape sequence at end of string %s", lit)
+		}
+		switch s[i] {
+		case 'n':
+			emit('\n', false)
+		case 'r':
+			emit('\r', false)
+		case 't':
+			emit('\t', false)
+		case '0':
+			emit(0, false)
+		case '\\', '\'', '"':
+			emit(rune(s[i]), false)
+		case '*':
+			if !pattern {
+				return fmt.Errorf("invalid escape sequence '\\*' in string %s", lit)
+			}
+			emit('*', false)
+		case 'x':
+			if i+3 > len(s) {
+				return fmt.Errorf("invalid hex escape in string %s", lit)
+			}
+			n, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil || n > 0x7f {
+				return fmt.Errorf("invalid hex escape in string %s", lit)
+			}
+			emit(rune(n), false)
+			i += 2
+		case 'u':
+			if i+1 >= len(s) || s[i+1] != '{' {
+				return fmt.Errorf("invalid unicode escape in string %s", lit)
+			}
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return fmt.Errorf("invalid unicode escape in string %s", lit)
+			}
+			hex := s[i+2 : i+end]
+			n, err := strconv.ParseUint(hex, 16, 32)
+			if err != nil || len(hex) == 0 || len(hex) > 6 || !utf8.ValidRune(rune(n)) {
+				return fmt.Errorf("invalid unicode escape in string %s", lit)
+			}
+			emit(rune(n), false)
+			i += end
+		default:
+			return fmt.Errorf("invalid escape sequence '\\%c' in string %s", s[i], lit)
+		}
+		i++
+	}
+	return nil
+}
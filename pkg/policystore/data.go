@@ -0,0 +1,230 @@
+package policystore
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+)
+
+// now returns the time recorded for new versions. It is replaced in tests.
+var now = time.Now
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func checkID(id string) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("invalid ID %q: IDs may only contain letters, digits, '-' and '_'", id)
+	}
+	return nil
+}
+
+// SetSchema sets the schema of the store. The schema must be
+// in the Cedar JSON schema format, or empty to remove it.
+func (d *Data) SetSchema(schema string) error {
+	if schema != "" {
+		if _, err := cedarpolicy.ParseSchema([]byte(schema)); err != nil {
+			return fmt.Errorf("unable to parse schema: %w", err)
+		}
+	}
+	d.Schema = schema
+	return nil
+}
+
+// validate checks the policy against the store's schema, if it has one.
+func (d *Data) validate(policy cedarpolicy.Policy) error {
+	if d.Schema == "" {
+		return nil
+	}
+	schema, err := cedarpolicy.ParseSchema([]byte(d.Schema))
+	if err != nil {
+		return fmt.Errorf("unable to parse schema: %w", err)
+	}
+	return errors.Join(schema.ValidatePolicy(policy)...)
+}
+
+// PutPolicy creates or updates a static policy. A new version is recorded
+// if the statement or description of an existing policy has changed.
+func (d *Data) PutPolicy(id, description, statement string) (*Entry, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	policy, err := cedarpolicy.ParsePolicy(statement)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse policy %q: %w", id, err)
+	}
+	if err := d.validate(policy); err != nil {
+		return nil, fmt.Errorf("policy %q is not valid for the schema: %w", id, err)
+	}
+
+	return put(d.Policies, id, description, statement, nil), nil
+}
+
+// PutTemplateLinkedPolicy creates or updates a policy which is linked to a template.
+// The principal and resource fill the template's '?principal' and '?resource' slots.
+func (d *Data) PutTemplateLinkedPolicy(id, description, templateID string, principal, resource *eid.EID) (*Entry, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	template, ok := d.Templates[templateID]
+	if !ok {
+		return nil, fmt.Errorf("template %q: %w", templateID, ErrNotFound)
+	}
+
+	link := &TemplateLink{TemplateID: templateID}
+	if principal != nil {
+		link.Principal = principal.String()
+	}
+	if resource != nil {
+		link.Resource = resource.String()
+	}
+
+	statement, err := d.link(template, link)
+	if err != nil {
+		return nil, fmt.Errorf("unable to link policy %q to template %q: %w", id, templateID, err)
+	}
+
+	return put(d.Policies, id, description, statement, link), nil
+}
+
+// link renders the statement of a policy linked to the template.
+func (d *Data) link(template *Entry, link *TemplateLink) (string, error) {
+	parsed, err := cedarpolicy.ParseTemplate(template.Statement)
+	if err != nil {
+		return "", err
+	}
+
+	var principal, resource *eid.EID
+	if link.Principal != "" {
+		uid, err := eid.Parse(link.Principal)
+		if err != nil {
+			return "", err
+		}
+		principal = &uid
+	}
+	if link.Resource != "" {
+		uid, err := eid.Parse(link.Resource)
+		if err != nil {
+			return "", err
+		}
+		resource = &uid
+	}
+
+	policy, err := parsed.Link(principal, resource)
+	if err != nil {
+		return "", err
+	}
+	if err := d.validate(policy); err != nil {
+		return "", fmt.Errorf("the linked policy is not valid for the schema: %w", err)
+	}
+	return policy.RenderString()
+}
+
+// PutTemplate creates or updates a policy template. Policies linked
+// to the template are updated to match the new statement.
+func (d *Data) PutTemplate(id, description, statement string) (*Entry, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	template, err := cedarpolicy.ParseTemplate(statement)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse template %q: %w", id, err)
+	}
+	if err := d.validate(template); err != nil {
+		return nil, fmt.Errorf("template %q is not valid for the schema: %w", id, err)
+	}
+
+	entry := put(d.Templates, id, description, statement, nil)
+
+	for _, policyID := range SortedIDs(d.Policies) {
+		policy := d.Policies[policyID]
+		if policy.Link == nil || policy.Link.TemplateID != id || policy.Missing {
+			continue
+		}
+		linked, err := d.link(entry, policy.Link)
+		if err != nil {
+			return nil, fmt.Errorf("unable to update policy %q linked to template %q: %w", policyID, id, err)
+		}
+		put(d.Policies, policyID, policy.Description, linked, policy.Link)
+	}
+
+	return entry, nil
+}
+
+// FindPolicy returns the static policy whose statement is equivalent to the
+// statement, ignoring differences in formatting. It returns ErrNotFound if
+// there is no such policy, and an error if more than one policy matches.
+func (d *Data) FindPolicy(statement string) (*Entry, error) {
+	var found *Entry
+	for _, id := range SortedIDs(d.Policies) {
+		entry := d.Policies[id]
+		if entry.Link != nil || entry.Missing || !cedarpolicy.Equivalent(entry.Statement, statement) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("policies %q and %q both match the statement", found.ID, entry.ID)
+		}
+		found = entry
+	}
+	if found == nil {
+		return nil, fmt.Errorf("policy matching the statement: %w", ErrNotFound)
+	}
+	return found, nil
+}
+
+// DeletePolicy removes a policy and its version history from the store.
+func (d *Data) DeletePolicy(id string) error {
+	if _, ok := d.Policies[id]; !ok {
+		return fmt.Errorf("policy %q: %w", id, ErrNotFound)
+	}
+	delete(d.Policies, id)
+	return nil
+}
+
+// DeleteTemplate removes a template and its version history from the store.
+// Templates which have linked policies can't be removed.
+func (d *Data) DeleteTemplate(id string) error {
+	if _, ok := d.Templates[id]; !ok {
+		return fmt.Errorf("template %q: %w", id, ErrNotFound)
+	}
+	for _, policyID := range SortedIDs(d.Policies) {
+		if link := d.Policies[policyID].Link; link != nil && link.TemplateID == id {
+			return fmt.Errorf("template %q can't be deleted as policy %q is linked to it", id, policyID)
+		}
+	}
+	delete(d.Templates, id)
+	return nil
+}
+
+// put creates or updates an entry, recording a new version if it has changed.
+// Statements which differ only in formatting are not recorded as a new version.
+func put(entries map[string]*Entry, id, description, statement string, link *TemplateLink) *Entry {
+	entry, ok := entries[id]
+	if !ok {
+		entry = &Entry{ID: id}
+		entries[id] = entry
+	}
+
+	changed := !ok || entry.Description != description || len(entry.Versions) == 0 ||
+		!cedarpolicy.Equivalent(entry.Versions[len(entry.Versions)-1].Statement, statement)
+
+	entry.Description = description
+	entry.Statement = statement
+	entry.Link = link
+	entry.Missing = false
+
+	if changed {
+		entry.Versions = append(entry.Versions, Version{
+			Version:   entry.Version() + 1,
+			Statement: statement,
+			CreatedAt: now().UTC(),
+		})
+	}
+	return entry
+}
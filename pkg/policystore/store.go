@@ -0,0 +1,312 @@
+// Package policystore implements a local, file-backed Cedar policy store.
+//
+// A store holds an optional schema, static and template-linked policies, and
+// policy templates. Every change to a policy or template is recorded as a new
+// version, so the history of each policy is retained in the store.
+//
+// A store is kept either in a single JSON file, if the path ends in '.json',
+// or in a directory. Directory stores keep their metadata in 'store.json', and
+// also write each policy to 'policies/<id>.cedar' and each template to
+// 'templates/<id>.cedar' so that the directory can be loaded by authorizers.
+package policystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/cedarpolicy"
+)
+
+// ErrNotFound is returned when a store, policy or template does not exist.
+var ErrNotFound = errors.New("not found")
+
+const (
+	metadataFileName = "store.json"
+	schemaFileName   = "schema.cedarschema.json"
+	policiesDir      = "policies"
+	templatesDir     = "templates"
+)
+
+// Version is a single revision of a policy or template.
+type Version struct {
+	Version   int       `json:"version"`
+	Statement string    `json:"statement"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TemplateLink describes the template and entities a template-linked policy was created from.
+type TemplateLink struct {
+	TemplateID string `json:"template_id"`
+	// Principal and Resource are Cedar entity UIDs, such as 'CF::User::"alice"'.
+	Principal string `json:"principal,omitempty"`
+	Resource  string `json:"resource,omitempty"`
+}
+
+// Entry is a policy or template in the store.
+type Entry struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	// Statement is the current Cedar text of the policy or template.
+	// For template-linked policies, it is the text of the linked policy.
+	Statement string        `json:"statement"`
+	Link      *TemplateLink `json:"template_link,omitempty"`
+	Versions  []Version     `json:"versions"`
+
+	// Missing is set when the entry's '.cedar' file has been removed from a
+	// directory store. The entry and its version history are kept in the
+	// store's metadata, and the file is written again when the entry is.
+	Missing bool `json:"-"`
+}
+
+// Version returns the current version number of the entry.
+func (e *Entry) Version() int {
+	if len(e.Versions) == 0 {
+		return 0
+	}
+	return e.Versions[len(e.Versions)-1].Version
+}
+
+// Data is the contents of a policy store.
+type Data struct {
+	// Schema is a Cedar schema in JSON format. If set, policies and
+	// templates are validated against the schema when they are written.
+	Schema    string            `json:"schema,omitempty"`
+	Policies  map[string]*Entry `json:"policies"`
+	Templates map[string]*Entry `json:"templates"`
+}
+
+// Store is a handle to a policy store at a path.
+type Store struct {
+	path string
+}
+
+// Open returns a handle to the policy store at the path. The store
+// is not read until Read or Update is called.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the path of the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// locks serializes changes to each store, as Terraform may modify
+// several policies in the same store concurrently. The locks are only
+// held within the process, so they don't protect a store from changes
+// made by other processes, such as concurrent Terraform runs.
+var locks sync.Map
+
+func (s *Store) lock() func() {
+	mu, _ := locks.LoadOrStore(filepath.Clean(s.path), &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+func (s *Store) isFile() bool {
+	return strings.HasSuffix(s.path, ".json")
+}
+
+func (s *Store) metadataPath() string {
+	if s.isFile() {
+		return s.path
+	}
+	return filepath.Join(s.path, metadataFileName)
+}
+
+// Create initializes an empty store with the schema. It returns an error if a store already exists at the path.
+func (s *Store) Create(schema string) error {
+	defer s.lock()()
+
+	if _, err := os.Stat(s.metadataPath()); err == nil {
+		return fmt.Errorf("a policy store already exists at %s", s.path)
+	}
+
+	data := &Data{Policies: map[string]*Entry{}, Templates: map[string]*Entry{}}
+	if err := data.SetSchema(schema); err != nil {
+		return err
+	}
+	return s.write(data)
+}
+
+// Read returns the contents of the store. It returns ErrNotFound if the store does not exist.
+//
+// For directory stores, the policy and template files are read, so that changes made to
+// them outside of the store are reported. Files which have been removed are omitted.
+func (s *Store) Read() (*Data, error) {
+	defer s.lock()()
+	return s.read()
+}
+
+// Update reads the store, calls fn to modify its contents, and writes the
+// result. Changes are discarded if fn returns an error.
+func (s *Store) Update(fn func(data *Data) error) error {
+	defer s.lock()()
+
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	if err := fn(data); err != nil {
+		return err
+	}
+	return s.write(data)
+}
+
+// Destroy removes the store and all of its files.
+func (s *Store) Destroy() error {
+	defer s.lock()()
+
+	if s.isFile() {
+		return ignoreNotExist(os.Remove(s.path))
+	}
+
+	for _, name := range []string{metadataFileName, schemaFileName, policiesDir, templatesDir} {
+		if err := os.RemoveAll(filepath.Join(s.path, name)); err != nil {
+			return err
+		}
+	}
+
+	// the directory is only removed if it is empty.
+	entries, err := os.ReadDir(s.path)
+	if err == nil && len(entries) == 0 {
+		return ignoreNotExist(os.Remove(s.path))
+	}
+	return ignoreNotExist(err)
+}
+
+func (s *Store) read() (*Data, error) {
+	content, err := os.ReadFile(s.metadataPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("policy store %s: %w", s.path, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data Data
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("unable to read policy store %s: %w", s.path, err)
+	}
+	if data.Policies == nil {
+		data.Policies = map[string]*Entry{}
+	}
+	if data.Templates == nil {
+		data.Templates = map[string]*Entry{}
+	}
+
+	if !s.isFile() {
+		for dir, entries := range map[string]map[string]*Entry{policiesDir: data.Policies, templatesDir: data.Templates} {
+			for id, entry := range entries {
+				text, err := os.ReadFile(filepath.Join(s.path, dir, id+cedarpolicy.PolicyFileExtension))
+				if errors.Is(err, fs.ErrNotExist) {
+					entry.Missing = true
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				entry.Statement = strings.TrimSpace(string(text))
+			}
+		}
+	}
+
+	return &data, nil
+}
+
+func (s *Store) write(data *Data) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if s.isFile() {
+		if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(s.path, content, 0644)
+	}
+
+	if err := os.MkdirAll(s.path, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(s.path, metadataFileName), content, 0644); err != nil {
+		return err
+	}
+
+	schemaPath := filepath.Join(s.path, schemaFileName)
+	if data.Schema != "" {
+		if err := os.WriteFile(schemaPath, []byte(data.Schema), 0644); err != nil {
+			return err
+		}
+	} else if err := ignoreNotExist(os.Remove(schemaPath)); err != nil {
+		return err
+	}
+
+	for dir, entries := range map[string]map[string]*Entry{policiesDir: data.Policies, templatesDir: data.Templates} {
+		if err := writeEntries(filepath.Join(s.path, dir), entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeEntries writes each entry to a '.cedar' file in the directory,
+// removing the files of entries which are no longer in the store.
+// Files are not written for entries which are missing.
+func writeEntries(dir string, entries map[string]*Entry) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	existing, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range existing {
+		id, ok := strings.CutSuffix(f.Name(), cedarpolicy.PolicyFileExtension)
+		if !ok || f.IsDir() {
+			continue
+		}
+		if _, ok := entries[id]; !ok {
+			if err := os.Remove(filepath.Join(dir, f.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	for id, entry := range entries {
+		if entry.Missing {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, id+cedarpolicy.PolicyFileExtension), []byte(entry.Statement+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SortedIDs returns the IDs of the entries in sorted order.
+func SortedIDs(entries map[string]*Entry) []string {
+	ids := make([]string, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func ignoreNotExist(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,185 @@
+package policystore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/common-fate/terraform-provider-cedar/pkg/eid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchema = `{"CF": {"entityTypes": {"User": {}, "Document": {}}, "actions": {"Read": {"appliesTo": {"principalTypes": ["User"], "resourceTypes": ["Document"]}}}}}`
+
+func TestStore(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+
+	for _, path := range []string{
+		filepath.Join(t.TempDir(), "store"),
+		filepath.Join(t.TempDir(), "store.json"),
+	} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			store := Open(path)
+
+			_, err := store.Read()
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			if err := store.Create(testSchema); err != nil {
+				t.Fatal(err)
+			}
+			assert.Error(t, store.Create(""), "a store can't be created twice")
+
+			err = store.Update(func(data *Data) error {
+				if _, err := data.PutPolicy("read", "", `permit (principal, action == CF::Action::"Read", resource);`); err != nil {
+					return err
+				}
+				_, err := data.PutTemplate("owner", "", `permit (principal == ?principal, action == CF::Action::"Read", resource == ?resource);`)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// policies are validated against the schema.
+			err = store.Update(func(data *Data) error {
+				_, err := data.PutPolicy("write", "", `permit (principal, action == CF::Action::"Write", resource);`)
+				return err
+			})
+			assert.ErrorContains(t, err, `unknown action CF::Action::"Write"`)
+
+			err = store.Update(func(data *Data) error {
+				if _, err := data.PutPolicy("read", "updated", `permit (principal is CF::User, action == CF::Action::"Read", resource);`); err != nil {
+					return err
+				}
+				_, err := data.PutTemplateLinkedPolicy("alice-plan", "", "owner",
+					&eid.EID{Type: "CF::User", ID: "alice"},
+					&eid.EID{Type: "CF::Document", ID: "plan"})
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := store.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			read := data.Policies["read"]
+			assert.Equal(t, 2, read.Version())
+			assert.Equal(t, `permit (principal, action == CF::Action::"Read", resource);`, read.Versions[0].Statement)
+			assert.Equal(t, "permit (\n\tprincipal == CF::User::\"alice\",\n\taction == CF::Action::\"Read\",\n\tresource == CF::Document::\"plan\"\n);", data.Policies["alice-plan"].Statement)
+
+			// formatting-only changes are not recorded as a new version.
+			err = store.Update(func(data *Data) error {
+				_, err := data.PutPolicy("read", "updated", "permit (\n  principal is CF::User,\n  action == CF::Action::\"Read\",\n  resource\n);")
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// updating a template updates its linked policies.
+			err = store.Update(func(data *Data) error {
+				_, err := data.PutTemplate("owner", "", `permit (principal in ?principal, action == CF::Action::"Read", resource == ?resource);`)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = store.Update(func(data *Data) error {
+				return data.DeleteTemplate("owner")
+			})
+			assert.ErrorContains(t, err, `template "owner" can't be deleted as policy "alice-plan" is linked to it`)
+
+			data, err = store.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, 2, data.Policies["read"].Version())
+			assert.Equal(t, 2, data.Policies["alice-plan"].Version())
+			assert.Contains(t, data.Policies["alice-plan"].Statement, `principal in CF::User::"alice"`)
+
+			// static policies can be found by their statement.
+			found, err := data.FindPolicy(`permit (principal is CF::User, action == CF::Action::"Read", resource);`)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "read", found.ID)
+			}
+			_, err = data.FindPolicy(data.Policies["alice-plan"].Statement)
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			if err := store.Destroy(); err != nil {
+				t.Fatal(err)
+			}
+			_, err = os.Stat(path)
+			assert.True(t, errors.Is(err, os.ErrNotExist))
+		})
+	}
+}
+
+func TestStore_DirectoryDrift(t *testing.T) {
+	path := t.TempDir()
+	store := Open(path)
+	if err := store.Create(""); err != nil {
+		t.Fatal(err)
+	}
+
+	err := store.Update(func(data *Data) error {
+		if _, err := data.PutPolicy("a", "", `permit (principal, action, resource);`); err != nil {
+			return err
+		}
+		_, err := data.PutPolicy("b", "", `forbid (principal, action, resource);`)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(path, "policies", "a.cedar"), []byte("permit (principal, action, resource) when { true };\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(path, "policies", "b.cedar")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := store.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"a", "b"}, SortedIDs(data.Policies))
+	assert.Equal(t, "permit (principal, action, resource) when { true };", data.Policies["a"].Statement)
+	assert.False(t, data.Policies["a"].Missing)
+	assert.True(t, data.Policies["b"].Missing)
+
+	// writing another policy keeps the missing policy and its history,
+	// without restoring its file.
+	err = store.Update(func(data *Data) error {
+		_, err := data.PutPolicy("c", "", `permit (principal, action, resource);`)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoFileExists(t, filepath.Join(path, "policies", "b.cedar"))
+
+	// writing the missing policy again restores its file and continues its history.
+	err = store.Update(func(data *Data) error {
+		_, err := data.PutPolicy("b", "", `forbid (principal, action, resource) when { false };`)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.FileExists(t, filepath.Join(path, "policies", "b.cedar"))
+
+	data, err = store.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, data.Policies["b"].Missing)
+	assert.Equal(t, 2, data.Policies["b"].Version())
+}